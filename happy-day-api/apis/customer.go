@@ -18,6 +18,11 @@ func MapCustomerEndpoints(e *echo.Echo) {
 	e.GET("/api/v1/customers/:id", getCustomerById)
 	e.PUT("/api/v1/customers/:id", updateCustomer)
 	e.DELETE("/api/v1/customers/:id", deleteCustomer)
+
+	e.POST("/api/v1/customers/:id/restore", restoreCustomer)
+	e.GET("/api/v1/customers/:id/history", getCustomerHistory)
+	e.POST("/api/v1/customers/:id/history/:revision/revert", revertCustomer)
+	e.GET("/api/v1/customers/:id/reservations", getCustomerReservations)
 }
 
 func getAllCustomers(ctx echo.Context) error {
@@ -25,7 +30,11 @@ func getAllCustomers(ctx echo.Context) error {
 	filter.Text = ctx.QueryParam("text")
 	filter.Size, _ = strconv.ParseInt(ctx.QueryParam("size"), 10, 64)
 	filter.Page, _ = strconv.ParseInt(ctx.QueryParam("page"), 10, 64)
-	filter.SortBy = infrastructure.CustomerNameAsc
+	filter.Cursor = ctx.QueryParam("cursor")
+	// Searches without an explicit sort are ordered by relevance
+	if len(filter.Text) == 0 {
+		filter.SortBy = infrastructure.CustomerNameAsc
+	}
 
 	params := ctx.QueryParams()
 	if params.Has("sort") {
@@ -96,6 +105,7 @@ func deleteCustomer(ctx echo.Context) error {
 	}
 
 	req := customer.DeleteRequest{Id: id}
+	req.Force, _ = strconv.ParseBool(ctx.QueryParam("force"))
 	err = initializeDeleteCustomerHandler().Handle(ctx.Request().Context(), req)
 	if err != nil {
 		return err
@@ -103,3 +113,76 @@ func deleteCustomer(ctx echo.Context) error {
 
 	return ctx.NoContent(http.StatusNoContent)
 }
+
+func restoreCustomer(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return infrastructure.ErrCustomerNotFound
+	}
+
+	err = initializeRestoreCustomerHandler().Handle(ctx.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func getCustomerHistory(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return infrastructure.ErrCustomerNotFound
+	}
+
+	res, err := initializeCustomerHistoryHandler().Handle(ctx.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, res)
+}
+
+func revertCustomer(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return infrastructure.ErrCustomerNotFound
+	}
+
+	revision, err := uuid.Parse(ctx.Param("revision"))
+	if err != nil {
+		return infrastructure.ErrRevisionNotFound
+	}
+
+	req := customer.RevertRequest{Id: id, RevisionId: revision}
+	req.PropagateToReservations, _ = strconv.ParseBool(ctx.QueryParam("propagateToReservations"))
+	res, err := initializeRevertCustomerHandler().Handle(ctx.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, res)
+}
+
+func getCustomerReservations(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return infrastructure.ErrCustomerNotFound
+	}
+
+	req := customer.GetReservationsRequest{Id: id}
+	req.Size, _ = strconv.ParseInt(ctx.QueryParam("size"), 10, 64)
+	req.Page, _ = strconv.ParseInt(ctx.QueryParam("page"), 10, 64)
+	req.SortBy = infrastructure.DeliveryDesc
+
+	params := ctx.QueryParams()
+	if params.Has("sort") {
+		req.SortBy = infrastructure.ReservationOrderBy(params.Get("sort"))
+	}
+
+	res, err := initializeGetCustomerReservationsHandler().Handle(ctx.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,369 @@
+package apis_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"happy_day/apis"
+	"happy_day/domain/customer"
+	"happy_day/domain/product"
+	"happy_day/domain/reservation"
+	"happy_day/domain/staff"
+	"happy_day/infrastructure"
+	"happy_day/middlewares"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type problem struct {
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+}
+
+func newServer() *echo.Echo {
+	viper.Set("storage.provider", infrastructure.MemoryStorage)
+	infrastructure.ResetMemoryStorage()
+
+	e := echo.New()
+	e.Use(middlewares.ErrorMiddleware)
+	e.Use(middlewares.ActorMiddleware)
+
+	apis.MapCustomerEndpoints(e)
+	apis.MapProductEndpoints(e)
+	apis.MapReservationEndpoints(e)
+	apis.MapStaffEndpoints(e)
+	apis.MapTrashEndpoints(e)
+	return e
+}
+
+func send(t *testing.T, e *echo.Echo, method, path string, body any, res any) int {
+	var payload bytes.Buffer
+	if body != nil {
+		assert.Nil(t, json.NewEncoder(&payload).Encode(body))
+	}
+
+	req := httptest.NewRequest(method, path, &payload)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(middlewares.ActorHeader, "e2e")
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if res != nil && rec.Body.Len() > 0 {
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), res))
+	}
+
+	return rec.Code
+}
+
+func TestCustomerEndToEndWhenUsingMemoryStorage(t *testing.T) {
+	e := newServer()
+
+	var created customer.State
+	status := send(t, e, http.MethodPost, "/api/v1/customers", customer.State{
+		Name:   "Maria Silva",
+		Phones: []customer.Phone{{Number: "11987654321"}},
+	}, &created)
+	assert.Equal(t, http.StatusCreated, status)
+	assert.NotEmpty(t, created.Id)
+
+	path := "/api/v1/customers/" + created.Id.String()
+
+	var found customer.State
+	status = send(t, e, http.MethodGet, path, nil, &found)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, created.Name, found.Name)
+
+	found.Comment = "vip"
+	var changed customer.State
+	status = send(t, e, http.MethodPut, path, found, &changed)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "vip", changed.Comment)
+
+	var page infrastructure.Page[customer.State]
+	status = send(t, e, http.MethodGet, "/api/v1/customers?text=maria", nil, &page)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, int64(1), page.TotalElements)
+
+	status = send(t, e, http.MethodDelete, path, nil, nil)
+	assert.Equal(t, http.StatusNoContent, status)
+
+	var err problem
+	status = send(t, e, http.MethodGet, path, nil, &err)
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, "CUS001", err.Title)
+
+	var items []struct {
+		Id        string `json:"id"`
+		DeletedBy string `json:"deletedBy"`
+	}
+	status = send(t, e, http.MethodGet, "/api/v1/trash?kind=customer", nil, &items)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Len(t, items, 1)
+	assert.Equal(t, "e2e", items[0].DeletedBy)
+
+	status = send(t, e, http.MethodGet, "/api/v1/trash?kind=customers", nil, &err)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "TRS001", err.Title)
+
+	status = send(t, e, http.MethodPost, path+"/restore", nil, nil)
+	assert.Equal(t, http.StatusNoContent, status)
+
+	status = send(t, e, http.MethodGet, path, nil, &found)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Nil(t, found.DeletedAt)
+}
+
+func TestReservationEndToEndWhenUsingMemoryStorage(t *testing.T) {
+	e := newServer()
+
+	var table product.State
+	status := send(t, e, http.MethodPost, "/api/v1/products", product.State{
+		Name:  "Table",
+		Price: 100,
+		Stock: 1,
+	}, &table)
+	assert.Equal(t, http.StatusCreated, status)
+
+	create := func(values map[string]any) map[string]any {
+		values["products"] = []map[string]any{{"id": table.Id, "quantity": 1}}
+		values["price"] = 100
+		return values
+	}
+
+	change := func(at time.Time) map[string]any {
+		return map[string]any{
+			"delivery": reservation.DeliveryOrPickUp{At: at},
+			"pickUp":   reservation.DeliveryOrPickUp{At: at.Add(4 * time.Hour)},
+			"customer": map[string]any{
+				"name":   "Maria Silva",
+				"phones": []customer.Phone{{Number: "11987654321"}},
+			},
+			"address": reservation.Address{
+				Street:     "Rua A",
+				Number:     "10",
+				PostalCode: "01000-000",
+				City:       "Sao Paulo",
+			},
+		}
+	}
+
+	at := time.Now().UTC().Add(72 * time.Hour).Truncate(time.Hour)
+
+	var err problem
+	status = send(t, e, http.MethodPost, "/api/v1/reservations", create(map[string]any{}), &err)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "RSV017", err.Title)
+
+	var first reservation.State
+	status = send(t, e, http.MethodPost, "/api/v1/reservations", create(change(at)), &first)
+	assert.Equal(t, http.StatusCreated, status)
+	assert.NotEmpty(t, first.CustomerId)
+	assert.Equal(t, reservation.Confirmed, first.Status)
+
+	path := "/api/v1/reservations/" + first.Id.String()
+
+	var second reservation.State
+	status = send(t, e, http.MethodPost, "/api/v1/reservations", create(map[string]any{"draft": true}), &second)
+	assert.Equal(t, http.StatusCreated, status)
+
+	status = send(t, e, http.MethodPut, "/api/v1/reservations/"+second.Id.String(), change(at.Add(time.Hour)), &err)
+	assert.Equal(t, http.StatusConflict, status)
+	assert.Equal(t, "RSV005", err.Title)
+
+	status = send(t, e, http.MethodPost, path+"/confirm", nil, &err)
+	assert.Equal(t, http.StatusConflict, status)
+
+	customerId := first.CustomerId
+	status = send(t, e, http.MethodPut, path, change(at.Add(24*time.Hour)), &first)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, customerId, first.CustomerId)
+
+	var customers infrastructure.Page[customer.State]
+	status = send(t, e, http.MethodGet, "/api/v1/customers", nil, &customers)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Len(t, customers.Items, 1)
+
+	var history []infrastructure.Revision
+	status = send(t, e, http.MethodGet, path+"/history", nil, &history)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Len(t, history, 2)
+
+	status = send(t, e, http.MethodPost, path+"/history/"+history[1].Id.String()+"/revert", nil, &first)
+	assert.Equal(t, http.StatusOK, status)
+	assert.True(t, at.Equal(first.Delivery.At))
+	assert.Equal(t, reservation.Confirmed, first.Status)
+}
+
+func TestStaffScheduleWhenAssigningDeliveries(t *testing.T) {
+	e := newServer()
+
+	var table product.State
+	send(t, e, http.MethodPost, "/api/v1/products", product.State{Name: "Table", Price: 100, Stock: 10}, &table)
+
+	var ana staff.State
+	status := send(t, e, http.MethodPost, "/api/v1/staff", staff.State{
+		Name:   "Ana",
+		Phone:  "11987654321",
+		Role:   staff.Driver,
+		Active: true,
+	}, &ana)
+	assert.Equal(t, http.StatusCreated, status)
+
+	create := func(at time.Time) map[string]any {
+		return map[string]any{
+			"products": []map[string]any{{"id": table.Id, "quantity": 1}},
+			"delivery": reservation.DeliveryOrPickUp{At: at, By: []uuid.UUID{ana.Id}},
+			"pickUp":   reservation.DeliveryOrPickUp{At: at.Add(4 * time.Hour)},
+			"customer": map[string]any{
+				"name":   "Maria Silva",
+				"phones": []customer.Phone{{Number: "11987654321"}},
+			},
+			"address": reservation.Address{Street: "Rua A", Number: "10", PostalCode: "01000-000", City: "Sao Paulo"},
+		}
+	}
+
+	at := time.Now().UTC().AddDate(0, 0, 3).Truncate(24 * time.Hour).Add(10 * time.Hour)
+	var first reservation.State
+	status = send(t, e, http.MethodPost, "/api/v1/reservations", create(at), &first)
+	assert.Equal(t, http.StatusCreated, status)
+
+	var err problem
+	status = send(t, e, http.MethodPost, "/api/v1/reservations", create(at.Add(30*time.Minute)), &err)
+	assert.Equal(t, http.StatusConflict, status)
+	assert.Equal(t, "STF008", err.Title)
+
+	var second reservation.State
+	status = send(t, e, http.MethodPost, "/api/v1/reservations", create(at.Add(2*time.Hour)), &second)
+	assert.Equal(t, http.StatusCreated, status)
+
+	var schedule struct {
+		Assignments []struct {
+			ReservationId uuid.UUID `json:"reservationId"`
+			Kind          string    `json:"kind"`
+		} `json:"assignments"`
+	}
+	path := "/api/v1/staff/" + ana.Id.String()
+	status = send(t, e, http.MethodGet, path+"/schedule?from="+at.Format("2006-01-02")+"&to="+at.Format("2006-01-02"), nil, &schedule)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Len(t, schedule.Assignments, 2)
+	assert.Equal(t, first.Id, schedule.Assignments[0].ReservationId)
+	assert.Equal(t, "delivery", schedule.Assignments[0].Kind)
+
+	var page infrastructure.Page[reservation.State]
+	status = send(t, e, http.MethodGet, "/api/v1/reservations?by="+ana.Id.String(), nil, &page)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, int64(2), page.TotalElements)
+
+	status = send(t, e, http.MethodDelete, path, nil, &err)
+	assert.Equal(t, http.StatusConflict, status)
+	assert.Equal(t, "STF005", err.Title)
+
+	ana.Active = false
+	status = send(t, e, http.MethodPut, path, ana, &err)
+	assert.Equal(t, http.StatusConflict, status)
+	assert.Equal(t, "STF005", err.Title)
+
+	for _, id := range []uuid.UUID{first.Id, second.Id} {
+		status = send(t, e, http.MethodPost, "/api/v1/reservations/"+id.String()+"/cancel", nil, nil)
+		assert.Equal(t, http.StatusOK, status)
+	}
+
+	status = send(t, e, http.MethodPut, path, ana, &ana)
+	assert.Equal(t, http.StatusOK, status)
+	assert.False(t, ana.Active)
+
+	status = send(t, e, http.MethodPost, "/api/v1/reservations", create(at.AddDate(0, 0, 1)), &err)
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+	assert.Equal(t, "STF007", err.Title)
+}
+
+func TestReservationFiltersWhenUsingQueryString(t *testing.T) {
+	e := newServer()
+
+	var table product.State
+	send(t, e, http.MethodPost, "/api/v1/products", product.State{Name: "Table", Price: 100}, &table)
+
+	var created reservation.State
+	status := send(t, e, http.MethodPost, "/api/v1/reservations", map[string]any{
+		"draft":    true,
+		"products": []map[string]any{{"id": table.Id, "quantity": 1}},
+		"price":    100,
+	}, &created)
+	assert.Equal(t, http.StatusCreated, status)
+
+	var page infrastructure.Page[reservation.State]
+	status = send(t, e, http.MethodGet, "/api/v1/reservations?size=1&page=1&productId="+table.Id.String(), nil, &page)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, int64(1), page.TotalElements)
+
+	status = send(t, e, http.MethodGet, "/api/v1/reservations?productId="+uuid.NewString(), nil, &page)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, int64(0), page.TotalElements)
+
+	var err problem
+	status = send(t, e, http.MethodGet, "/api/v1/reservations?deliveryFrom=yesterday", nil, &err)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "RSV014", err.Title)
+
+	status = send(t, e, http.MethodGet, "/api/v1/reservations?status=draft,booked", nil, &err)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "RSV014", err.Title)
+
+	status = send(t, e, http.MethodGet, "/api/v1/reservations?paymentMethod=check", nil, &err)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "RSV014", err.Title)
+
+	status = send(t, e, http.MethodGet, "/api/v1/reservations?status=draft&paymentMethod=pix", nil, &page)
+	assert.Equal(t, http.StatusOK, status)
+}
+
+func TestProductCursorWhenUsingQueryString(t *testing.T) {
+	e := newServer()
+
+	for _, name := range []string{"Chair", "Balloon", "Table"} {
+		send(t, e, http.MethodPost, "/api/v1/products", product.State{Name: name, Price: 10}, nil)
+	}
+
+	var page infrastructure.Page[product.State]
+	status := send(t, e, http.MethodGet, "/api/v1/products?size=2", nil, &page)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "Balloon", page.Items[0].Name)
+	assert.NotEmpty(t, page.NextCursor)
+
+	var next infrastructure.Page[product.State]
+	status = send(t, e, http.MethodGet, "/api/v1/products?size=2&cursor="+page.NextCursor, nil, &next)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Len(t, next.Items, 1)
+	assert.Equal(t, "Table", next.Items[0].Name)
+	assert.Empty(t, next.NextCursor)
+	assert.NotEmpty(t, next.PrevCursor)
+
+	var err problem
+	status = send(t, e, http.MethodGet, "/api/v1/products?cursor=invalid", nil, &err)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "APP002", err.Title)
+}
+
+func TestProductAvailabilityWhenProductNotFound(t *testing.T) {
+	e := newServer()
+
+	var table product.State
+	send(t, e, http.MethodPost, "/api/v1/products", product.State{Name: "Table", Price: 10, Stock: 5}, &table)
+
+	period := "/availability?from=2022-12-03&to=2022-12-04"
+	status := send(t, e, http.MethodGet, "/api/v1/products/"+table.Id.String()+period, nil, nil)
+	assert.Equal(t, http.StatusOK, status)
+
+	var err problem
+	status = send(t, e, http.MethodGet, "/api/v1/products/"+uuid.NewString()+period, nil, &err)
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, "PROD001", err.Title)
+}
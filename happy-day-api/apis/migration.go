@@ -0,0 +1,43 @@
+package apis
+
+import (
+	"context"
+
+	"happy_day/infrastructure"
+)
+
+// OpenMongoDbClient connects the client shared by the repositories, the cleanup disconnects it
+func OpenMongoDbClient() (func(), error) {
+	client, cleanup, err := initializeMongoDbClient()
+	if err != nil {
+		return nil, err
+	}
+
+	infrastructure.ShareMongoDbClient(client)
+	return cleanup, nil
+}
+
+func Migrate(ctx context.Context) ([]infrastructure.MigrationRecord, error) {
+	migrator, cleanup, err := initializeMigrator()
+	if err != nil {
+		return nil, err
+	}
+
+	defer cleanup()
+	return migrator.Run(ctx)
+}
+
+func ImportMongoDbIntoBolt(ctx context.Context) (infrastructure.BoltImport, error) {
+	if _, err := Migrate(ctx); err != nil {
+		return infrastructure.BoltImport{}, err
+	}
+
+	client, cleanup, err := initializeMongoDbClient()
+	if err != nil {
+		return infrastructure.BoltImport{}, err
+	}
+
+	defer cleanup()
+	return infrastructure.ImportMongoDbIntoBolt(ctx, client,
+		infrastructure.ProvideBoltDatabase(infrastructure.ProvideStorageOptions()))
+}
@@ -3,8 +3,11 @@ package apis
 import (
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"happy_day/application/product"
+	"happy_day/application/reservation"
 	"happy_day/infrastructure"
 
 	"github.com/google/uuid"
@@ -14,18 +17,27 @@ import (
 func MapProductEndpoints(e *echo.Echo) {
 	e.GET("/api/v1/products", getAllProducts)
 	e.POST("/api/v1/products", createProduct)
+	e.GET("/api/v1/products/availability", getProductsAvailability)
 
 	e.GET("/api/v1/products/:id", getProductById)
 	e.PUT("/api/v1/products/:id", updateProduct)
 	e.DELETE("/api/v1/products/:id", deleteProduct)
+	e.POST("/api/v1/products/:id/restore", restoreProduct)
+	e.GET("/api/v1/products/:id/history", getProductHistory)
+	e.POST("/api/v1/products/:id/history/:revision/revert", revertProduct)
+	e.GET("/api/v1/products/:id/availability", getProductAvailability)
 }
 
 func getAllProducts(ctx echo.Context) error {
 	var filter infrastructure.ProductFilter
 	filter.Text = ctx.QueryParam("text")
-	filter.Size, _ = strconv.ParseInt(ctx.Param("size"), 10, 64)
-	filter.Page, _ = strconv.ParseInt(ctx.Param("page"), 10, 64)
-	filter.SortBy = infrastructure.ProductNameAsc
+	filter.Size, _ = strconv.ParseInt(ctx.QueryParam("size"), 10, 64)
+	filter.Page, _ = strconv.ParseInt(ctx.QueryParam("page"), 10, 64)
+	filter.Cursor = ctx.QueryParam("cursor")
+	// Searches without an explicit sort are ordered by relevance
+	if len(filter.Text) == 0 {
+		filter.SortBy = infrastructure.ProductNameAsc
+	}
 
 	params := ctx.QueryParams()
 	if params.Has("sort") {
@@ -99,6 +111,8 @@ func deleteProduct(ctx echo.Context) error {
 		Id: id,
 	}
 
+	req.Force, _ = strconv.ParseBool(ctx.QueryParam("force"))
+
 	err = initializeDeleteProductHandler().Handle(ctx.Request().Context(), req)
 	if err != nil {
 		return err
@@ -106,3 +120,137 @@ func deleteProduct(ctx echo.Context) error {
 
 	return ctx.NoContent(http.StatusNoContent)
 }
+
+func restoreProduct(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return infrastructure.ErrProductNotFound
+	}
+
+	err = initializeRestoreProductHandler().Handle(ctx.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func getProductHistory(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return infrastructure.ErrProductNotFound
+	}
+
+	res, err := initializeProductHistoryHandler().Handle(ctx.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, res)
+}
+
+func revertProduct(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return infrastructure.ErrProductNotFound
+	}
+
+	revision, err := uuid.Parse(ctx.Param("revision"))
+	if err != nil {
+		return infrastructure.ErrRevisionNotFound
+	}
+
+	req := product.RevertRequest{Id: id, RevisionId: revision}
+	res, err := initializeRevertProductHandler().Handle(ctx.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, res)
+}
+
+func getProductAvailability(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return infrastructure.ErrProductNotFound
+	}
+
+	req, err := bindAvailabilityRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	req.Products = []uuid.UUID{id}
+	res, err := initializeAvailabilityHandler().Handle(ctx.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, res[0])
+}
+
+func getProductsAvailability(ctx echo.Context) error {
+	req, err := bindAvailabilityRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, value := range strings.Split(ctx.QueryParam("ids"), ",") {
+		if len(value) == 0 {
+			continue
+		}
+
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return infrastructure.ErrOneProductNotFound
+		}
+
+		req.Products = append(req.Products, id)
+	}
+
+	res, err := initializeAvailabilityHandler().Handle(ctx.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, res)
+}
+
+func bindAvailabilityRequest(ctx echo.Context) (reservation.AvailabilityRequest, error) {
+	var req reservation.AvailabilityRequest
+	var err error
+
+	req.From, _, err = parseDateOrTime(ctx.QueryParam("from"))
+	if err != nil {
+		return req, infrastructure.ErrAvailabilityPeriodIsInvalid
+	}
+
+	to, isDate, err := parseDateOrTime(ctx.QueryParam("to"))
+	if err != nil {
+		return req, infrastructure.ErrAvailabilityPeriodIsInvalid
+	}
+
+	req.To = to
+	if isDate {
+		req.To = to.AddDate(0, 0, 1)
+	}
+
+	if slot := ctx.QueryParam("slot"); len(slot) > 0 {
+		req.Slot, err = time.ParseDuration(slot)
+		if err != nil {
+			return req, infrastructure.ErrAvailabilityPeriodIsInvalid
+		}
+	}
+
+	return req, nil
+}
+
+func parseDateOrTime(value string) (time.Time, bool, error) {
+	date, err := time.ParseInLocation("2006-01-02", value, infrastructure.BusinessLocation())
+	if err == nil {
+		return date, true, nil
+	}
+
+	at, err := time.Parse(time.RFC3339, value)
+	return at, false, err
+}
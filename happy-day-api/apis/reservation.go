@@ -1,10 +1,14 @@
 package apis
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"happy_day/application/reservation"
+	domain "happy_day/domain/reservation"
 	"happy_day/infrastructure"
 
 	"github.com/google/uuid"
@@ -20,6 +24,22 @@ func MapReservationEndpoints(e *echo.Echo) {
 	e.GET("/api/v1/reservations/:id", getReservationById)
 	e.PUT("/api/v1/reservations/:id", updateReservation)
 	e.DELETE("/api/v1/reservations/:id", deleteReservation)
+	e.POST("/api/v1/reservations/:id/restore", restoreReservation)
+	e.GET("/api/v1/reservations/:id/history", getReservationHistory)
+	e.POST("/api/v1/reservations/:id/history/:revision/revert", revertReservation)
+
+	e.POST("/api/v1/reservations/:id/confirm", changeReservationStatus(domain.Confirmed))
+	e.POST("/api/v1/reservations/:id/deliver", changeReservationStatus(domain.Delivered))
+	e.POST("/api/v1/reservations/:id/pick-up", changeReservationStatus(domain.PickedUp))
+	e.POST("/api/v1/reservations/:id/close", changeReservationStatus(domain.Closed))
+	e.POST("/api/v1/reservations/:id/cancel", changeReservationStatus(domain.Cancelled))
+
+	e.POST("/api/v1/reservations/:id/installments/:n/pay", payReservationInstallment)
+	e.GET("/api/v1/reservations/:id/installments/:n/pix", getReservationInstallmentPix)
+	e.GET("/api/v1/reservations/:id/installments/:n/pix/qrcode", getReservationInstallmentPixQrCode)
+
+	e.GET("/api/v1/reservations/:id/contract", getReservationDocument(reservation.Contract))
+	e.GET("/api/v1/reservations/:id/receipt", getReservationDocument(reservation.Receipt))
 }
 
 func createReservation(ctx echo.Context) error {
@@ -74,6 +94,54 @@ func deleteReservation(ctx echo.Context) error {
 	return ctx.NoContent(http.StatusNoContent)
 }
 
+func restoreReservation(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return infrastructure.ErrReservationNotFound
+	}
+
+	err = initializeRestoreReservationHandler().Handle(ctx.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func getReservationHistory(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return infrastructure.ErrReservationNotFound
+	}
+
+	res, err := initializeReservationHistoryHandler().Handle(ctx.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, res)
+}
+
+func revertReservation(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return infrastructure.ErrReservationNotFound
+	}
+
+	revision, err := uuid.Parse(ctx.Param("revision"))
+	if err != nil {
+		return infrastructure.ErrRevisionNotFound
+	}
+
+	req := reservation.RevertRequest{Id: id, RevisionId: revision}
+	res, err := initializeRevertReservationHandler().Handle(ctx.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, res)
+}
+
 func getReservationById(ctx echo.Context) error {
 	id, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
@@ -90,11 +158,10 @@ func getReservationById(ctx echo.Context) error {
 }
 
 func getAllReservation(ctx echo.Context) error {
-	var filter infrastructure.ReservationFilter
-	filter.Text = ctx.Param("text")
-	filter.Size, _ = strconv.ParseInt(ctx.Param("size"), 10, 64)
-	filter.Page, _ = strconv.ParseInt(ctx.Param("page"), 10, 64)
-	filter.SortBy = infrastructure.ReservationOrderBy(ctx.Param("orderBy"))
+	filter, err := bindReservationFilter(ctx)
+	if err != nil {
+		return err
+	}
 
 	res, err := initializeGetAllReservationHandler().Handle(ctx.Request().Context(), filter)
 	if err != nil {
@@ -104,6 +171,121 @@ func getAllReservation(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, res)
 }
 
+func bindReservationFilter(ctx echo.Context) (infrastructure.ReservationFilter, error) {
+	var filter infrastructure.ReservationFilter
+	var err error
+
+	params := ctx.QueryParams()
+	filter.Text = params.Get("text")
+	filter.Size, _ = strconv.ParseInt(params.Get("size"), 10, 64)
+	filter.Page, _ = strconv.ParseInt(params.Get("page"), 10, 64)
+	filter.Cursor = params.Get("cursor")
+	filter.SortBy = infrastructure.ReservationOrderBy(params.Get("sort"))
+	if params.Has("orderBy") {
+		filter.SortBy = infrastructure.ReservationOrderBy(params.Get("orderBy"))
+	}
+
+	for _, status := range strings.Split(params.Get("status"), ",") {
+		if len(status) == 0 {
+			continue
+		}
+
+		if !isKnownStatus(domain.Status(status)) {
+			return filter, infrastructure.ErrReservationFilterIsInvalid
+		}
+
+		filter.Status = append(filter.Status, domain.Status(status))
+	}
+
+	filter.City = params.Get("city")
+	filter.Neighborhood = params.Get("neighborhood")
+	filter.PaymentMethod = domain.PaymentMethod(params.Get("paymentMethod"))
+	if len(filter.PaymentMethod) > 0 && !isKnownPaymentMethod(filter.PaymentMethod) {
+		return filter, infrastructure.ErrReservationFilterIsInvalid
+	}
+
+	if filter.CustomerId, err = parseOptionalUUID(params.Get("customerId")); err != nil {
+		return filter, infrastructure.ErrReservationFilterIsInvalid
+	}
+
+	if filter.ProductId, err = parseOptionalUUID(params.Get("productId")); err != nil {
+		return filter, infrastructure.ErrReservationFilterIsInvalid
+	}
+
+	if filter.By, err = parseOptionalUUID(params.Get("by")); err != nil {
+		return filter, infrastructure.ErrReservationFilterIsInvalid
+	}
+
+	if filter.DeliveryFrom, filter.DeliveryTo, err = parseOptionalPeriod(params.Get("deliveryFrom"), params.Get("deliveryTo")); err != nil {
+		return filter, infrastructure.ErrReservationFilterIsInvalid
+	}
+
+	if filter.PickUpFrom, filter.PickUpTo, err = parseOptionalPeriod(params.Get("pickUpFrom"), params.Get("pickUpTo")); err != nil {
+		return filter, infrastructure.ErrReservationFilterIsInvalid
+	}
+
+	if params.Has("hasBalanceDue") {
+		hasBalanceDue, err := strconv.ParseBool(params.Get("hasBalanceDue"))
+		if err != nil {
+			return filter, infrastructure.ErrReservationFilterIsInvalid
+		}
+
+		filter.HasBalanceDue = &hasBalanceDue
+	}
+
+	return filter, nil
+}
+
+func isKnownStatus(status domain.Status) bool {
+	switch status {
+	case domain.Draft, domain.Confirmed, domain.Delivered, domain.PickedUp, domain.Closed, domain.Cancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+func isKnownPaymentMethod(method domain.PaymentMethod) bool {
+	switch method {
+	case domain.Pix, domain.BankTransfer, domain.Cash:
+		return true
+	default:
+		return false
+	}
+}
+
+func parseOptionalUUID(value string) (uuid.UUID, error) {
+	if len(value) == 0 {
+		return uuid.Nil, nil
+	}
+
+	return uuid.Parse(value)
+}
+
+// A date-only upper bound includes the whole day
+func parseOptionalPeriod(fromValue, toValue string) (time.Time, time.Time, error) {
+	var from, to time.Time
+	var err error
+	if len(fromValue) > 0 {
+		if from, _, err = parseDateOrTime(fromValue); err != nil {
+			return from, to, err
+		}
+	}
+
+	if len(toValue) > 0 {
+		var isDate bool
+		if to, isDate, err = parseDateOrTime(toValue); err != nil {
+			return from, to, err
+		}
+
+		if isDate {
+			to = to.AddDate(0, 0, 1)
+		}
+	}
+
+	return from, to, nil
+}
+
 func quoteReservation(ctx echo.Context) error {
 	var req reservation.QuoteRequest
 	if err := ctx.Bind(&req); err != nil {
@@ -117,3 +299,109 @@ func quoteReservation(ctx echo.Context) error {
 
 	return ctx.JSON(http.StatusOK, res)
 }
+
+func changeReservationStatus(status domain.Status) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			return infrastructure.ErrReservationNotFound
+		}
+
+		req := reservation.ChangeStatusRequest{
+			Id:     id,
+			Status: status,
+		}
+
+		res, err := initializeChangeReservationStatusHandler().Handle(ctx.Request().Context(), req)
+		if err != nil {
+			return err
+		}
+
+		return ctx.JSON(http.StatusOK, res)
+	}
+}
+
+func payReservationInstallment(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return infrastructure.ErrReservationNotFound
+	}
+
+	installment, err := strconv.Atoi(ctx.Param("n"))
+	if err != nil {
+		return infrastructure.ErrReservationInstallmentNotFound
+	}
+
+	var req reservation.PayInstallmentRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ErrInvalidBody
+	}
+
+	req.Id = id
+	req.Installment = installment
+	res, err := initializePayInstallmentHandler().Handle(ctx.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, res)
+}
+
+func getReservationInstallmentPix(ctx echo.Context) error {
+	res, err := handleReservationInstallmentPix(ctx)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, res)
+}
+
+func getReservationInstallmentPixQrCode(ctx echo.Context) error {
+	res, err := handleReservationInstallmentPix(ctx)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Blob(http.StatusOK, "image/png", res.QrCode)
+}
+
+func handleReservationInstallmentPix(ctx echo.Context) (reservation.PixResponse, error) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return reservation.PixResponse{}, infrastructure.ErrReservationNotFound
+	}
+
+	installment, err := strconv.Atoi(ctx.Param("n"))
+	if err != nil {
+		return reservation.PixResponse{}, infrastructure.ErrReservationInstallmentNotFound
+	}
+
+	req := reservation.PixRequest{
+		Id:          id,
+		Installment: installment,
+	}
+
+	return initializePixHandler().Handle(ctx.Request().Context(), req)
+}
+
+func getReservationDocument(kind reservation.DocumentKind) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			return infrastructure.ErrReservationNotFound
+		}
+
+		req := reservation.DocumentRequest{
+			Id:   id,
+			Kind: kind,
+		}
+
+		res, err := initializeDocumentHandler().Handle(ctx.Request().Context(), req)
+		if err != nil {
+			return err
+		}
+
+		ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("inline; filename=%s-%s.pdf", kind, id))
+		return ctx.Blob(http.StatusOK, "application/pdf", res)
+	}
+}
@@ -0,0 +1,153 @@
+package apis
+
+import (
+	"net/http"
+	"strconv"
+
+	"happy_day/application/staff"
+	domain "happy_day/domain/staff"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+func MapStaffEndpoints(e *echo.Echo) {
+	e.GET("/api/v1/staff", getAllStaff)
+	e.POST("/api/v1/staff", createStaff)
+
+	e.GET("/api/v1/staff/:id", getStaffById)
+	e.PUT("/api/v1/staff/:id", updateStaff)
+	e.DELETE("/api/v1/staff/:id", deleteStaff)
+	e.POST("/api/v1/staff/:id/restore", restoreStaff)
+	e.GET("/api/v1/staff/:id/schedule", getStaffSchedule)
+}
+
+func getAllStaff(ctx echo.Context) error {
+	var filter infrastructure.StaffFilter
+	params := ctx.QueryParams()
+	filter.Text = params.Get("text")
+	filter.Role = domain.Role(params.Get("role"))
+	filter.Size, _ = strconv.ParseInt(params.Get("size"), 10, 64)
+	filter.Page, _ = strconv.ParseInt(params.Get("page"), 10, 64)
+	filter.Cursor = params.Get("cursor")
+	// Searches without an explicit sort are ordered by relevance
+	if len(filter.Text) == 0 {
+		filter.SortBy = infrastructure.StaffNameAsc
+	}
+
+	if params.Has("sort") {
+		filter.SortBy = infrastructure.StaffSortBy(params.Get("sort"))
+	}
+
+	if active, err := strconv.ParseBool(params.Get("active")); err == nil {
+		filter.Active = &active
+	}
+
+	res, err := initializeGetAllStaffHandler().Handle(ctx.Request().Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, res)
+}
+
+func createStaff(ctx echo.Context) error {
+	var req staff.ChangeOrCreateRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ErrInvalidBody
+	}
+
+	req.Id = uuid.Nil
+	res, err := initializeChangeOrCreateStaffHandler().Handle(ctx.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusCreated, res)
+}
+
+func getStaffById(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return infrastructure.ErrStaffNotFound
+	}
+
+	res, err := initializeGetStaffByIdHandler().Handle(ctx.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, res)
+}
+
+func updateStaff(ctx echo.Context) error {
+	var req staff.ChangeOrCreateRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ErrInvalidBody
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return infrastructure.ErrStaffNotFound
+	}
+
+	req.Id = id
+	res, err := initializeChangeOrCreateStaffHandler().Handle(ctx.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, res)
+}
+
+func deleteStaff(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return infrastructure.ErrStaffNotFound
+	}
+
+	req := staff.DeleteRequest{Id: id}
+	req.Force, _ = strconv.ParseBool(ctx.QueryParam("force"))
+
+	err = initializeDeleteStaffHandler().Handle(ctx.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func restoreStaff(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return infrastructure.ErrStaffNotFound
+	}
+
+	err = initializeRestoreStaffHandler().Handle(ctx.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func getStaffSchedule(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return infrastructure.ErrStaffNotFound
+	}
+
+	req := staff.ScheduleRequest{Id: id}
+	req.From, req.To, err = parseOptionalPeriod(ctx.QueryParam("from"), ctx.QueryParam("to"))
+	if err != nil {
+		return infrastructure.ErrStaffSchedulePeriodIsInvalid
+	}
+
+	res, err := initializeStaffScheduleHandler().Handle(ctx.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,28 @@
+package apis
+
+import (
+	"context"
+	"net/http"
+
+	"happy_day/application/trash"
+
+	"github.com/labstack/echo/v4"
+)
+
+func MapTrashEndpoints(e *echo.Echo) {
+	e.GET("/api/v1/trash", getAllTrash)
+}
+
+func getAllTrash(ctx echo.Context) error {
+	req := trash.GetAllRequest{Kind: trash.Kind(ctx.QueryParam("kind"))}
+	res, err := initializeGetAllTrashHandler().Handle(ctx.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, res)
+}
+
+func PurgeTrash(ctx context.Context) (trash.PurgeResponse, error) {
+	return initializePurgeTrashHandler().Handle(ctx, trash.PurgeRequest{})
+}
@@ -6,9 +6,12 @@ package apis
 import (
 	"happy_day/application/product"
 	"happy_day/application/reservation"
+	"happy_day/application/staff"
+	"happy_day/application/trash"
 	"happy_day/infrastructure"
 
 	"github.com/google/wire"
+	"go.mongodb.org/mongo-driver/mongo"
 
 	"happy_day/application/customer"
 )
@@ -18,6 +21,8 @@ var (
 		customer.ProviderSet,
 		product.ProviderSet,
 		reservation.ProvideSet,
+		staff.ProviderSet,
+		trash.ProviderSet,
 		infrastructure.ProviderSet,
 	)
 )
@@ -45,6 +50,26 @@ func initializeDeleteCustomerHandler() customer.DeleteHandler {
 	return customer.DeleteHandler{}
 }
 
+func initializeGetCustomerReservationsHandler() customer.GetReservationsHandler {
+	wire.Build(ProviderSet)
+	return customer.GetReservationsHandler{}
+}
+
+func initializeRestoreCustomerHandler() customer.RestoreHandler {
+	wire.Build(ProviderSet)
+	return customer.RestoreHandler{}
+}
+
+func initializeCustomerHistoryHandler() customer.HistoryHandler {
+	wire.Build(ProviderSet)
+	return customer.HistoryHandler{}
+}
+
+func initializeRevertCustomerHandler() customer.RevertHandler {
+	wire.Build(ProviderSet)
+	return customer.RevertHandler{}
+}
+
 // Product
 func initializeGetAllProductHandler() product.GetAllHandler {
 	wire.Build(ProviderSet)
@@ -66,6 +91,21 @@ func initializeDeleteProductHandler() product.DeleteHandler {
 	return product.DeleteHandler{}
 }
 
+func initializeRestoreProductHandler() product.RestoreHandler {
+	wire.Build(ProviderSet)
+	return product.RestoreHandler{}
+}
+
+func initializeProductHistoryHandler() product.HistoryHandler {
+	wire.Build(ProviderSet)
+	return product.HistoryHandler{}
+}
+
+func initializeRevertProductHandler() product.RevertHandler {
+	wire.Build(ProviderSet)
+	return product.RevertHandler{}
+}
+
 // Reservation
 func initializeGetAllReservationHandler() reservation.GetAllHandler {
 	wire.Build(ProviderSet)
@@ -92,7 +132,100 @@ func initializeDeleteReservationHandler() reservation.DeleteHandler {
 	return reservation.DeleteHandler{}
 }
 
+func initializeRestoreReservationHandler() reservation.RestoreHandler {
+	wire.Build(ProviderSet)
+	return reservation.RestoreHandler{}
+}
+
+func initializeReservationHistoryHandler() reservation.HistoryHandler {
+	wire.Build(ProviderSet)
+	return reservation.HistoryHandler{}
+}
+
+func initializeRevertReservationHandler() reservation.RevertHandler {
+	wire.Build(ProviderSet)
+	return reservation.RevertHandler{}
+}
+
 func initializeQuoteReservationHandler() reservation.QuoteHandler {
 	wire.Build(ProviderSet)
 	return reservation.QuoteHandler{}
 }
+
+func initializeAvailabilityHandler() reservation.AvailabilityHandler {
+	wire.Build(ProviderSet)
+	return reservation.AvailabilityHandler{}
+}
+
+func initializeChangeReservationStatusHandler() reservation.ChangeStatusHandler {
+	wire.Build(ProviderSet)
+	return reservation.ChangeStatusHandler{}
+}
+
+func initializePayInstallmentHandler() reservation.PayInstallmentHandler {
+	wire.Build(ProviderSet)
+	return reservation.PayInstallmentHandler{}
+}
+
+func initializePixHandler() reservation.PixHandler {
+	wire.Build(ProviderSet)
+	return reservation.PixHandler{}
+}
+
+func initializeDocumentHandler() reservation.DocumentHandler {
+	wire.Build(ProviderSet)
+	return reservation.DocumentHandler{}
+}
+
+// Staff
+func initializeGetAllStaffHandler() staff.GetAllHandler {
+	wire.Build(ProviderSet)
+	return staff.GetAllHandler{}
+}
+
+func initializeGetStaffByIdHandler() staff.GetByIdHandler {
+	wire.Build(ProviderSet)
+	return staff.GetByIdHandler{}
+}
+
+func initializeChangeOrCreateStaffHandler() staff.ChangeOrCreateHandler {
+	wire.Build(ProviderSet)
+	return staff.ChangeOrCreateHandler{}
+}
+
+func initializeDeleteStaffHandler() staff.DeleteHandler {
+	wire.Build(ProviderSet)
+	return staff.DeleteHandler{}
+}
+
+func initializeRestoreStaffHandler() staff.RestoreHandler {
+	wire.Build(ProviderSet)
+	return staff.RestoreHandler{}
+}
+
+func initializeStaffScheduleHandler() staff.ScheduleHandler {
+	wire.Build(ProviderSet)
+	return staff.ScheduleHandler{}
+}
+
+// Trash
+func initializeGetAllTrashHandler() trash.GetAllHandler {
+	wire.Build(ProviderSet)
+	return trash.GetAllHandler{}
+}
+
+func initializePurgeTrashHandler() trash.PurgeHandler {
+	wire.Build(ProviderSet)
+	return trash.PurgeHandler{}
+}
+
+// Migrations
+func initializeMigrator() (*infrastructure.MongoDbMigrator, func(), error) {
+	wire.Build(ProviderSet)
+	return &infrastructure.MongoDbMigrator{}, nil, nil
+}
+
+func initializeMongoDbClient() (*mongo.Client, func(), error) {
+	wire.Build(ProviderSet)
+	return &mongo.Client{}, nil, nil
+}
@@ -8,117 +8,378 @@ package apis
 
 import (
 	"github.com/google/wire"
+	"go.mongodb.org/mongo-driver/mongo"
 	"happy_day/application/customer"
 	"happy_day/application/product"
 	"happy_day/application/reservation"
+	"happy_day/application/staff"
+	"happy_day/application/trash"
 	"happy_day/infrastructure"
 )
 
 // Injectors from wire.go:
 
 func initializeGetAllCustomerHandler() customer.GetAllHandler {
-	clientOptions := infrastructure.ProvideMongoDbOptions()
-	mongoDbCustomerRepository := infrastructure.ProviderCustomerRepository(clientOptions)
-	getAllHandler := customer.ProvideGetAllHandler(mongoDbCustomerRepository)
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	customerRepository := infrastructure.ProvideCustomerRepository(storageOptions, mongoDbClientFactory)
+	getAllHandler := customer.ProvideGetAllHandler(customerRepository)
 	return getAllHandler
 }
 
 func initializeGetCustomerByIdHandler() customer.GetByIdHandler {
-	clientOptions := infrastructure.ProvideMongoDbOptions()
-	mongoDbCustomerRepository := infrastructure.ProviderCustomerRepository(clientOptions)
-	getByIdHandler := customer.ProvideGetByIdHandler(mongoDbCustomerRepository)
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	customerRepository := infrastructure.ProvideCustomerRepository(storageOptions, mongoDbClientFactory)
+	getByIdHandler := customer.ProvideGetByIdHandler(customerRepository)
 	return getByIdHandler
 }
 
 func initializeChangeOrCreateCustomerHandler() customer.ChangeOrCreateHandler {
-	clientOptions := infrastructure.ProvideMongoDbOptions()
-	mongoDbCustomerRepository := infrastructure.ProviderCustomerRepository(clientOptions)
-	changeOrCreateHandler := customer.ProvideChangeOrCreateHandler(mongoDbCustomerRepository)
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	customerRepository := infrastructure.ProvideCustomerRepository(storageOptions, mongoDbClientFactory)
+	reservationRepository := infrastructure.ProvideReservationRepository(storageOptions, mongoDbClientFactory)
+	changeOrCreateHandler := customer.ProvideChangeOrCreateHandler(customerRepository, reservationRepository)
 	return changeOrCreateHandler
 }
 
 func initializeDeleteCustomerHandler() customer.DeleteHandler {
-	clientOptions := infrastructure.ProvideMongoDbOptions()
-	mongoDbCustomerRepository := infrastructure.ProviderCustomerRepository(clientOptions)
-	deleteHandler := customer.ProvideDeleteHandler(mongoDbCustomerRepository)
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	customerRepository := infrastructure.ProvideCustomerRepository(storageOptions, mongoDbClientFactory)
+	reservationRepository := infrastructure.ProvideReservationRepository(storageOptions, mongoDbClientFactory)
+	deleteHandler := customer.ProvideDeleteHandler(customerRepository, reservationRepository)
 	return deleteHandler
 }
 
+func initializeGetCustomerReservationsHandler() customer.GetReservationsHandler {
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	customerRepository := infrastructure.ProvideCustomerRepository(storageOptions, mongoDbClientFactory)
+	reservationRepository := infrastructure.ProvideReservationRepository(storageOptions, mongoDbClientFactory)
+	getReservationsHandler := customer.ProvideGetReservationsHandler(customerRepository, reservationRepository)
+	return getReservationsHandler
+}
+
+func initializeRestoreCustomerHandler() customer.RestoreHandler {
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	customerRepository := infrastructure.ProvideCustomerRepository(storageOptions, mongoDbClientFactory)
+	restoreHandler := customer.ProvideRestoreHandler(customerRepository)
+	return restoreHandler
+}
+
+func initializeCustomerHistoryHandler() customer.HistoryHandler {
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	customerRepository := infrastructure.ProvideCustomerRepository(storageOptions, mongoDbClientFactory)
+	historyHandler := customer.ProvideHistoryHandler(customerRepository)
+	return historyHandler
+}
+
+func initializeRevertCustomerHandler() customer.RevertHandler {
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	customerRepository := infrastructure.ProvideCustomerRepository(storageOptions, mongoDbClientFactory)
+	reservationRepository := infrastructure.ProvideReservationRepository(storageOptions, mongoDbClientFactory)
+	revertHandler := customer.ProvideRevertHandler(customerRepository, reservationRepository)
+	return revertHandler
+}
+
 // Product
 func initializeGetAllProductHandler() product.GetAllHandler {
-	clientOptions := infrastructure.ProvideMongoDbOptions()
-	mongoDbProductRepository := infrastructure.ProvideProductRepository(clientOptions)
-	getAllHandler := product.ProvideGetAllHandler(mongoDbProductRepository)
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	productRepository := infrastructure.ProvideProductRepository(storageOptions, mongoDbClientFactory)
+	getAllHandler := product.ProvideGetAllHandler(productRepository)
 	return getAllHandler
 }
 
 func initalizeGetProductByIdHandler() product.GetByIdHandler {
-	clientOptions := infrastructure.ProvideMongoDbOptions()
-	mongoDbProductRepository := infrastructure.ProvideProductRepository(clientOptions)
-	getByIdHandler := product.ProvideGetByIdHandler(mongoDbProductRepository)
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	productRepository := infrastructure.ProvideProductRepository(storageOptions, mongoDbClientFactory)
+	getByIdHandler := product.ProvideGetByIdHandler(productRepository)
 	return getByIdHandler
 }
 
 func initializeChangeOrCreateProductHandler() product.ChangeOrCreateHandler {
-	clientOptions := infrastructure.ProvideMongoDbOptions()
-	mongoDbProductRepository := infrastructure.ProvideProductRepository(clientOptions)
-	changeOrCreateHandler := product.ProvideChangeOrCreateHandler(mongoDbProductRepository)
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	productRepository := infrastructure.ProvideProductRepository(storageOptions, mongoDbClientFactory)
+	changeOrCreateHandler := product.ProvideChangeOrCreateHandler(productRepository)
 	return changeOrCreateHandler
 }
 
 func initializeDeleteProductHandler() product.DeleteHandler {
-	clientOptions := infrastructure.ProvideMongoDbOptions()
-	mongoDbProductRepository := infrastructure.ProvideProductRepository(clientOptions)
-	deleteHandler := product.ProvideDeleteHandler(mongoDbProductRepository)
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	productRepository := infrastructure.ProvideProductRepository(storageOptions, mongoDbClientFactory)
+	reservationRepository := infrastructure.ProvideReservationRepository(storageOptions, mongoDbClientFactory)
+	deleteHandler := product.ProvideDeleteHandler(productRepository, reservationRepository)
 	return deleteHandler
 }
 
+func initializeRestoreProductHandler() product.RestoreHandler {
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	productRepository := infrastructure.ProvideProductRepository(storageOptions, mongoDbClientFactory)
+	restoreHandler := product.ProvideRestoreHandler(productRepository)
+	return restoreHandler
+}
+
+func initializeProductHistoryHandler() product.HistoryHandler {
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	productRepository := infrastructure.ProvideProductRepository(storageOptions, mongoDbClientFactory)
+	historyHandler := product.ProvideHistoryHandler(productRepository)
+	return historyHandler
+}
+
+func initializeRevertProductHandler() product.RevertHandler {
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	productRepository := infrastructure.ProvideProductRepository(storageOptions, mongoDbClientFactory)
+	revertHandler := product.ProvideRevertHandler(productRepository)
+	return revertHandler
+}
+
 // Reservation
 func initializeGetAllReservationHandler() reservation.GetAllHandler {
-	clientOptions := infrastructure.ProvideMongoDbOptions()
-	mongoDbReservationRepository := infrastructure.ProvideReservationRepository(clientOptions)
-	getAllHandler := reservation.ProvideGetAllHandler(mongoDbReservationRepository)
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	reservationRepository := infrastructure.ProvideReservationRepository(storageOptions, mongoDbClientFactory)
+	getAllHandler := reservation.ProvideGetAllHandler(reservationRepository)
 	return getAllHandler
 }
 
 func initializeGetReservationByIdHandler() reservation.GetByIdHandler {
-	clientOptions := infrastructure.ProvideMongoDbOptions()
-	mongoDbReservationRepository := infrastructure.ProvideReservationRepository(clientOptions)
-	getByIdHandler := reservation.ProvideGetByIdHandler(mongoDbReservationRepository)
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	reservationRepository := infrastructure.ProvideReservationRepository(storageOptions, mongoDbClientFactory)
+	getByIdHandler := reservation.ProvideGetByIdHandler(reservationRepository)
 	return getByIdHandler
 }
 
 func initializeCreateReservationHandler() reservation.CreateHandler {
-	clientOptions := infrastructure.ProvideMongoDbOptions()
-	mongoDbProductRepository := infrastructure.ProvideProductRepository(clientOptions)
-	mongoDbReservationRepository := infrastructure.ProvideReservationRepository(clientOptions)
-	createHandler := reservation.ProvideCreateHandler(mongoDbProductRepository, mongoDbReservationRepository)
+	pricingOptions := infrastructure.ProvidePricingOptions()
+	reservationOptions := infrastructure.ProvideReservationOptions()
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	customerRepository := infrastructure.ProvideCustomerRepository(storageOptions, mongoDbClientFactory)
+	productRepository := infrastructure.ProvideProductRepository(storageOptions, mongoDbClientFactory)
+	reservationRepository := infrastructure.ProvideReservationRepository(storageOptions, mongoDbClientFactory)
+	staffRepository := infrastructure.ProvideStaffRepository(storageOptions, mongoDbClientFactory)
+	createHandler := reservation.ProvideCreateHandler(pricingOptions, reservationOptions, customerRepository, productRepository, reservationRepository, staffRepository)
 	return createHandler
 }
 
 func initializeChangeReservationHandler() reservation.ChangeHandler {
-	clientOptions := infrastructure.ProvideMongoDbOptions()
-	mongoDbReservationRepository := infrastructure.ProvideReservationRepository(clientOptions)
-	changeHandler := reservation.ProvideChangeHandler(mongoDbReservationRepository)
+	pricingOptions := infrastructure.ProvidePricingOptions()
+	reservationOptions := infrastructure.ProvideReservationOptions()
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	customerRepository := infrastructure.ProvideCustomerRepository(storageOptions, mongoDbClientFactory)
+	productRepository := infrastructure.ProvideProductRepository(storageOptions, mongoDbClientFactory)
+	reservationRepository := infrastructure.ProvideReservationRepository(storageOptions, mongoDbClientFactory)
+	staffRepository := infrastructure.ProvideStaffRepository(storageOptions, mongoDbClientFactory)
+	changeHandler := reservation.ProvideChangeHandler(pricingOptions, reservationOptions, customerRepository, productRepository, reservationRepository, staffRepository)
 	return changeHandler
 }
 
 func initializeDeleteReservationHandler() reservation.DeleteHandler {
-	clientOptions := infrastructure.ProvideMongoDbOptions()
-	mongoDbReservationRepository := infrastructure.ProvideReservationRepository(clientOptions)
-	deleteHandler := reservation.ProvideDeleteHandler(mongoDbReservationRepository)
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	reservationRepository := infrastructure.ProvideReservationRepository(storageOptions, mongoDbClientFactory)
+	deleteHandler := reservation.ProvideDeleteHandler(reservationRepository)
 	return deleteHandler
 }
 
+func initializeRestoreReservationHandler() reservation.RestoreHandler {
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	reservationRepository := infrastructure.ProvideReservationRepository(storageOptions, mongoDbClientFactory)
+	restoreHandler := reservation.ProvideRestoreHandler(reservationRepository)
+	return restoreHandler
+}
+
+func initializeReservationHistoryHandler() reservation.HistoryHandler {
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	reservationRepository := infrastructure.ProvideReservationRepository(storageOptions, mongoDbClientFactory)
+	historyHandler := reservation.ProvideHistoryHandler(reservationRepository)
+	return historyHandler
+}
+
+func initializeRevertReservationHandler() reservation.RevertHandler {
+	reservationOptions := infrastructure.ProvideReservationOptions()
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	customerRepository := infrastructure.ProvideCustomerRepository(storageOptions, mongoDbClientFactory)
+	productRepository := infrastructure.ProvideProductRepository(storageOptions, mongoDbClientFactory)
+	reservationRepository := infrastructure.ProvideReservationRepository(storageOptions, mongoDbClientFactory)
+	staffRepository := infrastructure.ProvideStaffRepository(storageOptions, mongoDbClientFactory)
+	revertHandler := reservation.ProvideRevertHandler(reservationOptions, customerRepository, productRepository, reservationRepository, staffRepository)
+	return revertHandler
+}
+
 func initializeQuoteReservationHandler() reservation.QuoteHandler {
-	clientOptions := infrastructure.ProvideMongoDbOptions()
-	mongoDbProductRepository := infrastructure.ProvideProductRepository(clientOptions)
-	quoteHandler := reservation.ProvideQuoteHandler(mongoDbProductRepository)
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	productRepository := infrastructure.ProvideProductRepository(storageOptions, mongoDbClientFactory)
+	quoteHandler := reservation.ProvideQuoteHandler(productRepository)
 	return quoteHandler
 }
 
+func initializeAvailabilityHandler() reservation.AvailabilityHandler {
+	reservationOptions := infrastructure.ProvideReservationOptions()
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	productRepository := infrastructure.ProvideProductRepository(storageOptions, mongoDbClientFactory)
+	reservationRepository := infrastructure.ProvideReservationRepository(storageOptions, mongoDbClientFactory)
+	availabilityHandler := reservation.ProvideAvailabilityHandler(reservationOptions, productRepository, reservationRepository)
+	return availabilityHandler
+}
+
+func initializeChangeReservationStatusHandler() reservation.ChangeStatusHandler {
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	reservationRepository := infrastructure.ProvideReservationRepository(storageOptions, mongoDbClientFactory)
+	changeStatusHandler := reservation.ProvideChangeStatusHandler(reservationRepository)
+	return changeStatusHandler
+}
+
+func initializePayInstallmentHandler() reservation.PayInstallmentHandler {
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	reservationRepository := infrastructure.ProvideReservationRepository(storageOptions, mongoDbClientFactory)
+	payInstallmentHandler := reservation.ProvidePayInstallmentHandler(reservationRepository)
+	return payInstallmentHandler
+}
+
+func initializePixHandler() reservation.PixHandler {
+	pixOptions := infrastructure.ProvidePixOptions()
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	reservationRepository := infrastructure.ProvideReservationRepository(storageOptions, mongoDbClientFactory)
+	pixHandler := reservation.ProvidePixHandler(pixOptions, reservationRepository)
+	return pixHandler
+}
+
+func initializeDocumentHandler() reservation.DocumentHandler {
+	documentOptions := infrastructure.ProvideDocumentOptions()
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	productRepository := infrastructure.ProvideProductRepository(storageOptions, mongoDbClientFactory)
+	reservationRepository := infrastructure.ProvideReservationRepository(storageOptions, mongoDbClientFactory)
+	documentHandler := reservation.ProvideDocumentHandler(documentOptions, productRepository, reservationRepository)
+	return documentHandler
+}
+
+// Staff
+func initializeGetAllStaffHandler() staff.GetAllHandler {
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	staffRepository := infrastructure.ProvideStaffRepository(storageOptions, mongoDbClientFactory)
+	getAllHandler := staff.ProvideGetAllHandler(staffRepository)
+	return getAllHandler
+}
+
+func initializeGetStaffByIdHandler() staff.GetByIdHandler {
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	staffRepository := infrastructure.ProvideStaffRepository(storageOptions, mongoDbClientFactory)
+	getByIdHandler := staff.ProvideGetByIdHandler(staffRepository)
+	return getByIdHandler
+}
+
+func initializeChangeOrCreateStaffHandler() staff.ChangeOrCreateHandler {
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	staffRepository := infrastructure.ProvideStaffRepository(storageOptions, mongoDbClientFactory)
+	reservationRepository := infrastructure.ProvideReservationRepository(storageOptions, mongoDbClientFactory)
+	changeOrCreateHandler := staff.ProvideChangeOrCreateHandler(staffRepository, reservationRepository)
+	return changeOrCreateHandler
+}
+
+func initializeDeleteStaffHandler() staff.DeleteHandler {
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	staffRepository := infrastructure.ProvideStaffRepository(storageOptions, mongoDbClientFactory)
+	reservationRepository := infrastructure.ProvideReservationRepository(storageOptions, mongoDbClientFactory)
+	deleteHandler := staff.ProvideDeleteHandler(staffRepository, reservationRepository)
+	return deleteHandler
+}
+
+func initializeRestoreStaffHandler() staff.RestoreHandler {
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	staffRepository := infrastructure.ProvideStaffRepository(storageOptions, mongoDbClientFactory)
+	restoreHandler := staff.ProvideRestoreHandler(staffRepository)
+	return restoreHandler
+}
+
+func initializeStaffScheduleHandler() staff.ScheduleHandler {
+	reservationOptions := infrastructure.ProvideReservationOptions()
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	staffRepository := infrastructure.ProvideStaffRepository(storageOptions, mongoDbClientFactory)
+	reservationRepository := infrastructure.ProvideReservationRepository(storageOptions, mongoDbClientFactory)
+	scheduleHandler := staff.ProvideScheduleHandler(reservationOptions, staffRepository, reservationRepository)
+	return scheduleHandler
+}
+
+// Trash
+func initializeGetAllTrashHandler() trash.GetAllHandler {
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	customerRepository := infrastructure.ProvideCustomerRepository(storageOptions, mongoDbClientFactory)
+	productRepository := infrastructure.ProvideProductRepository(storageOptions, mongoDbClientFactory)
+	reservationRepository := infrastructure.ProvideReservationRepository(storageOptions, mongoDbClientFactory)
+	staffRepository := infrastructure.ProvideStaffRepository(storageOptions, mongoDbClientFactory)
+	getAllHandler := trash.ProvideGetAllHandler(customerRepository, productRepository, reservationRepository, staffRepository)
+	return getAllHandler
+}
+
+func initializePurgeTrashHandler() trash.PurgeHandler {
+	trashOptions := infrastructure.ProvideTrashOptions()
+	storageOptions := infrastructure.ProvideStorageOptions()
+	mongoDbClientFactory := infrastructure.ProvideMongoDbClientFactory()
+	customerRepository := infrastructure.ProvideCustomerRepository(storageOptions, mongoDbClientFactory)
+	productRepository := infrastructure.ProvideProductRepository(storageOptions, mongoDbClientFactory)
+	reservationRepository := infrastructure.ProvideReservationRepository(storageOptions, mongoDbClientFactory)
+	staffRepository := infrastructure.ProvideStaffRepository(storageOptions, mongoDbClientFactory)
+	purgeHandler := trash.ProvidePurgeHandler(trashOptions, customerRepository, productRepository, reservationRepository, staffRepository)
+	return purgeHandler
+}
+
+// Migrations
+func initializeMigrator() (*infrastructure.MongoDbMigrator, func(), error) {
+	clientOptions := infrastructure.ProvideMongoDbOptions()
+	client, cleanup, err := infrastructure.ProvideMongoDbClient(clientOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+	mongoDbMigrator := infrastructure.ProvideMigrator(client)
+	return mongoDbMigrator, func() {
+		cleanup()
+	}, nil
+}
+
+func initializeMongoDbClient() (*mongo.Client, func(), error) {
+	clientOptions := infrastructure.ProvideMongoDbOptions()
+	client, cleanup, err := infrastructure.ProvideMongoDbClient(clientOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, func() {
+		cleanup()
+	}, nil
+}
+
 // wire.go:
 
 var (
-	ProviderSet = wire.NewSet(customer.ProviderSet, product.ProviderSet, reservation.ProvideSet, infrastructure.ProviderSet)
+	ProviderSet = wire.NewSet(customer.ProviderSet, product.ProviderSet, reservation.ProvideSet, staff.ProviderSet, trash.ProviderSet, infrastructure.ProviderSet)
 )
@@ -13,10 +13,12 @@ import (
 type (
 	ChangeOrCreateRequest struct {
 		customer.State
+		PropagateToReservations bool `json:"propagateToReservations,omitempty"`
 	}
 
 	ChangeOrCreateHandler struct {
-		repository infrastructure.CustomerRepository
+		repository            infrastructure.CustomerRepository
+		reservationRepository infrastructure.ReservationRepository
 	}
 )
 
@@ -36,7 +38,12 @@ func (handler ChangeOrCreateHandler) Handle(ctx context.Context, req ChangeOrCre
 		req.ModifiedAt = state.ModifiedAt
 	}
 
-	return handler.repository.Save(ctx, req.State)
+	state, err := handler.repository.Save(ctx, req.State)
+	if err != nil || req.Id == uuid.Nil || !req.PropagateToReservations {
+		return state, err
+	}
+
+	return state, handler.reservationRepository.UpdateCustomer(ctx, state)
 }
 
 func Validate(state customer.State) error {
@@ -113,3 +113,62 @@ func TestCreateOrChangeCustomerHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateOrChangeCustomerHandlerWhenPropagateToReservations(t *testing.T) {
+	state := customer.State{
+		Id:   uuid.New(),
+		Name: common.RandString(10),
+		Phones: []customer.Phone{
+			{
+				Number: "123456789",
+			},
+		},
+	}
+
+	repo := &infrastructure.MockCustomerRepository{}
+	repo.
+		On("GetById", mock.Anything, state.Id).
+		Return(state, nil)
+	repo.
+		On("Save", mock.Anything, mock.Anything).
+		Return(state, nil)
+
+	reservationRepo := &infrastructure.MockReservationRepository{}
+	reservationRepo.
+		On("UpdateCustomer", mock.Anything, state).
+		Return(nil)
+
+	handler := ChangeOrCreateHandler{repository: repo, reservationRepository: reservationRepo}
+	_, err := handler.Handle(context.Background(), ChangeOrCreateRequest{
+		State:                   state,
+		PropagateToReservations: true,
+	})
+	assert.Nil(t, err)
+	reservationRepo.AssertExpectations(t)
+}
+
+func TestCreateOrChangeCustomerHandlerWhenNotPropagateToReservations(t *testing.T) {
+	state := customer.State{
+		Id:   uuid.New(),
+		Name: common.RandString(10),
+		Phones: []customer.Phone{
+			{
+				Number: "123456789",
+			},
+		},
+	}
+
+	repo := &infrastructure.MockCustomerRepository{}
+	repo.
+		On("GetById", mock.Anything, state.Id).
+		Return(state, nil)
+	repo.
+		On("Save", mock.Anything, mock.Anything).
+		Return(state, nil)
+
+	reservationRepo := &infrastructure.MockReservationRepository{}
+	handler := ChangeOrCreateHandler{repository: repo, reservationRepository: reservationRepo}
+	_, err := handler.Handle(context.Background(), ChangeOrCreateRequest{State: state})
+	assert.Nil(t, err)
+	reservationRepo.AssertNotCalled(t, "UpdateCustomer", mock.Anything, mock.Anything)
+}
@@ -10,14 +10,27 @@ import (
 
 type (
 	DeleteRequest struct {
-		Id uuid.UUID `json:"id"`
+		Id    uuid.UUID `json:"id"`
+		Force bool      `json:"force"`
 	}
 
 	DeleteHandler struct {
-		repository infrastructure.CustomerRepository
+		repository            infrastructure.CustomerRepository
+		reservationRepository infrastructure.ReservationRepository
 	}
 )
 
 func (handler DeleteHandler) Handle(ctx context.Context, req DeleteRequest) error {
+	if !req.Force {
+		exists, err := handler.reservationRepository.ExistsOpenWithCustomer(ctx, req.Id)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			return infrastructure.ErrCustomerInUse
+		}
+	}
+
 	return handler.repository.Delete(ctx, req.Id)
 }
@@ -5,17 +5,50 @@ import (
 
 	"happy_day/infrastructure"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+func TestDeleteCustomerHandlerWhenExistOpenReservation(t *testing.T) {
+	req := DeleteRequest{Id: uuid.New()}
+	repo := &infrastructure.MockCustomerRepository{}
+	reservationRepo := &infrastructure.MockReservationRepository{}
+	reservationRepo.
+		On("ExistsOpenWithCustomer", mock.Anything, req.Id).
+		Return(true, nil)
+
+	handler := DeleteHandler{repository: repo, reservationRepository: reservationRepo}
+	err := handler.Handle(nil, req)
+	assert.Equal(t, infrastructure.ErrCustomerInUse, err)
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestDeleteCustomerHandlerWhenForce(t *testing.T) {
+	repo := &infrastructure.MockCustomerRepository{}
+	repo.
+		On("Delete", mock.Anything, mock.Anything).
+		Return(nil)
+
+	reservationRepo := &infrastructure.MockReservationRepository{}
+	handler := DeleteHandler{repository: repo, reservationRepository: reservationRepo}
+	err := handler.Handle(nil, DeleteRequest{Force: true})
+	assert.Nil(t, err)
+	reservationRepo.AssertNotCalled(t, "ExistsOpenWithCustomer", mock.Anything, mock.Anything)
+}
+
 func TestDeleteCustomerHandler(t *testing.T) {
 	repo := &infrastructure.MockCustomerRepository{}
 	repo.
 		On("Delete", mock.Anything, mock.Anything).
 		Return(nil)
 
-	handler := DeleteHandler{repository: repo}
+	reservationRepo := &infrastructure.MockReservationRepository{}
+	reservationRepo.
+		On("ExistsOpenWithCustomer", mock.Anything, mock.Anything).
+		Return(false, nil)
+
+	handler := DeleteHandler{repository: repo, reservationRepository: reservationRepo}
 	err := handler.Handle(nil, DeleteRequest{})
 	assert.Nil(t, err)
 }
@@ -0,0 +1,108 @@
+package customer
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"happy_day/domain/reservation"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+)
+
+type (
+	GetReservationsRequest struct {
+		Id     uuid.UUID
+		Page   int64
+		Size   int64
+		SortBy infrastructure.ReservationOrderBy
+	}
+
+	GetReservationsResponse struct {
+		Summary      ReservationSummary                     `json:"summary"`
+		Reservations infrastructure.Page[reservation.State] `json:"reservations"`
+	}
+
+	ReservationSummary struct {
+		Parties            int64      `json:"parties"`
+		TotalSpent         float64    `json:"totalSpent"`
+		OutstandingBalance float64    `json:"outstandingBalance"`
+		LastEventAt        *time.Time `json:"lastEventAt,omitempty"`
+	}
+
+	GetReservationsHandler struct {
+		repository            infrastructure.CustomerRepository
+		reservationRepository infrastructure.ReservationRepository
+	}
+)
+
+func (handler GetReservationsHandler) Handle(ctx context.Context, req GetReservationsRequest) (GetReservationsResponse, error) {
+	var res GetReservationsResponse
+	if !req.SortBy.IsValid() {
+		return res, infrastructure.ErrReservationFilterIsInvalid
+	}
+
+	_, err := handler.repository.GetById(ctx, req.Id)
+	if err != nil {
+		return res, err
+	}
+
+	if req.Size <= 0 {
+		req.Size = 50
+	}
+
+	if req.Page <= 1 {
+		req.Page = 1
+	}
+
+	res.Reservations, err = handler.reservationRepository.GetAll(ctx, infrastructure.ReservationFilter{
+		CustomerId: req.Id,
+		Page:       req.Page,
+		Size:       req.Size,
+		SortBy:     req.SortBy,
+	})
+	if err != nil {
+		return res, err
+	}
+
+	reservations, err := handler.reservationRepository.GetByCustomer(ctx, req.Id)
+	if err != nil {
+		return res, err
+	}
+
+	res.Summary = summarize(reservations, time.Now().UTC())
+	return res, nil
+}
+
+// summarize only counts the parties that already happened, drafts, cancellations and future events are left out
+func summarize(reservations []reservation.State, now time.Time) ReservationSummary {
+	var summary ReservationSummary
+	for _, item := range reservations {
+		if !happened(item, now) {
+			continue
+		}
+
+		summary.Parties++
+		summary.TotalSpent += item.AmountPaid
+		summary.OutstandingBalance += item.BalanceDue
+
+		at := item.Delivery.At
+		if !at.IsZero() && (summary.LastEventAt == nil || at.After(*summary.LastEventAt)) {
+			summary.LastEventAt = &at
+		}
+	}
+
+	summary.TotalSpent = math.Round(summary.TotalSpent*100) / 100
+	summary.OutstandingBalance = math.Round(summary.OutstandingBalance*100) / 100
+	return summary
+}
+
+func happened(state reservation.State, now time.Time) bool {
+	switch state.Status {
+	case reservation.Delivered, reservation.PickedUp, reservation.Closed:
+		return !state.Delivery.At.IsZero() && !state.Delivery.At.After(now)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,94 @@
+package customer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"happy_day/domain/customer"
+	"happy_day/domain/reservation"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetCustomerReservationsWhenCustomerNotFound(t *testing.T) {
+	repo := &infrastructure.MockCustomerRepository{}
+	repo.
+		On("GetById", mock.Anything, mock.Anything).
+		Return(customer.State{}, infrastructure.ErrCustomerNotFound)
+
+	handler := GetReservationsHandler{repository: repo}
+	_, err := handler.Handle(context.Background(), GetReservationsRequest{Id: uuid.New()})
+	assert.Equal(t, infrastructure.ErrCustomerNotFound, err)
+}
+
+func TestGetCustomerReservationsWhenSortIsInvalid(t *testing.T) {
+	handler := GetReservationsHandler{}
+	_, err := handler.Handle(context.Background(), GetReservationsRequest{Id: uuid.New(), SortBy: "name_asc"})
+	assert.Equal(t, infrastructure.ErrReservationFilterIsInvalid, err)
+}
+
+func TestGetCustomerReservations(t *testing.T) {
+	id := uuid.New()
+	last := time.Date(2023, 5, 20, 14, 0, 0, 0, time.UTC)
+	reservations := []reservation.State{
+		{
+			Id:         uuid.New(),
+			Status:     reservation.Closed,
+			AmountPaid: 300,
+			Delivery:   reservation.DeliveryOrPickUp{At: last.AddDate(-1, 0, 0)},
+		},
+		{
+			Id:         uuid.New(),
+			Status:     reservation.Delivered,
+			AmountPaid: 100.1,
+			BalanceDue: 200.2,
+			Delivery:   reservation.DeliveryOrPickUp{At: last},
+		},
+		{
+			Id:         uuid.New(),
+			Status:     reservation.Draft,
+			BalanceDue: 80,
+			Delivery:   reservation.DeliveryOrPickUp{At: last.AddDate(0, -1, 0)},
+		},
+		{
+			Id:         uuid.New(),
+			Status:     reservation.Confirmed,
+			AmountPaid: 40,
+			BalanceDue: 60,
+			Delivery:   reservation.DeliveryOrPickUp{At: time.Now().UTC().AddDate(0, 1, 0)},
+		},
+		{
+			Id:         uuid.New(),
+			Status:     reservation.Cancelled,
+			AmountPaid: 50,
+			BalanceDue: 250,
+			Delivery:   reservation.DeliveryOrPickUp{At: last.AddDate(1, 0, 0)},
+		},
+	}
+
+	repo := &infrastructure.MockCustomerRepository{}
+	repo.
+		On("GetById", mock.Anything, id).
+		Return(customer.State{Id: id}, nil)
+
+	reservationRepo := &infrastructure.MockReservationRepository{}
+	reservationRepo.
+		On("GetAll", mock.Anything, infrastructure.ReservationFilter{CustomerId: id, Page: 1, Size: 50}).
+		Return(infrastructure.Page[reservation.State]{Items: reservations, TotalPages: 1, TotalElements: 5}, nil)
+	reservationRepo.
+		On("GetByCustomer", mock.Anything, id).
+		Return(reservations, nil)
+
+	handler := GetReservationsHandler{repository: repo, reservationRepository: reservationRepo}
+	res, err := handler.Handle(context.Background(), GetReservationsRequest{Id: id})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), res.Reservations.TotalElements)
+	assert.Equal(t, int64(2), res.Summary.Parties)
+	assert.Equal(t, 400.1, res.Summary.TotalSpent)
+	assert.Equal(t, 200.2, res.Summary.OutstandingBalance)
+	assert.Equal(t, last, *res.Summary.LastEventAt)
+}
@@ -11,6 +11,10 @@ var ProviderSet = wire.NewSet(
 	ProvideGetByIdHandler,
 	ProvideChangeOrCreateHandler,
 	ProvideDeleteHandler,
+	ProvideRestoreHandler,
+	ProvideHistoryHandler,
+	ProvideRevertHandler,
+	ProvideGetReservationsHandler,
 )
 
 func ProvideGetAllHandler(repository infrastructure.CustomerRepository) GetAllHandler {
@@ -21,10 +25,46 @@ func ProvideGetByIdHandler(repository infrastructure.CustomerRepository) GetById
 	return GetByIdHandler{repository: repository}
 }
 
-func ProvideChangeOrCreateHandler(repository infrastructure.CustomerRepository) ChangeOrCreateHandler {
-	return ChangeOrCreateHandler{repository: repository}
+func ProvideChangeOrCreateHandler(
+	repository infrastructure.CustomerRepository,
+	reservationRepository infrastructure.ReservationRepository) ChangeOrCreateHandler {
+	return ChangeOrCreateHandler{
+		repository:            repository,
+		reservationRepository: reservationRepository,
+	}
 }
 
-func ProvideDeleteHandler(repository infrastructure.CustomerRepository) DeleteHandler {
-	return DeleteHandler{repository: repository}
+func ProvideDeleteHandler(
+	repository infrastructure.CustomerRepository,
+	reservationRepository infrastructure.ReservationRepository) DeleteHandler {
+	return DeleteHandler{
+		repository:            repository,
+		reservationRepository: reservationRepository,
+	}
+}
+
+func ProvideGetReservationsHandler(
+	repository infrastructure.CustomerRepository,
+	reservationRepository infrastructure.ReservationRepository) GetReservationsHandler {
+	return GetReservationsHandler{
+		repository:            repository,
+		reservationRepository: reservationRepository,
+	}
+}
+
+func ProvideRestoreHandler(repository infrastructure.CustomerRepository) RestoreHandler {
+	return RestoreHandler{repository: repository}
+}
+
+func ProvideHistoryHandler(repository infrastructure.CustomerRepository) HistoryHandler {
+	return HistoryHandler{repository: repository}
+}
+
+func ProvideRevertHandler(
+	repository infrastructure.CustomerRepository,
+	reservationRepository infrastructure.ReservationRepository) RevertHandler {
+	return RevertHandler{
+		repository:            repository,
+		reservationRepository: reservationRepository,
+	}
 }
@@ -0,0 +1,34 @@
+package customer
+
+import (
+	"context"
+	"testing"
+
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRestoreCustomerHandlerWhenNotFound(t *testing.T) {
+	repo := &infrastructure.MockCustomerRepository{}
+	repo.
+		On("Restore", mock.Anything, mock.Anything).
+		Return(infrastructure.ErrCustomerNotFound)
+
+	handler := RestoreHandler{repository: repo}
+	err := handler.Handle(context.Background(), uuid.New())
+	assert.Equal(t, infrastructure.ErrCustomerNotFound, err)
+}
+
+func TestRestoreCustomerHandler(t *testing.T) {
+	repo := &infrastructure.MockCustomerRepository{}
+	repo.
+		On("Restore", mock.Anything, mock.Anything).
+		Return(nil)
+
+	handler := RestoreHandler{repository: repo}
+	err := handler.Handle(context.Background(), uuid.New())
+	assert.Nil(t, err)
+}
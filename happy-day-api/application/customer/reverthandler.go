@@ -0,0 +1,44 @@
+package customer
+
+import (
+	"context"
+
+	"happy_day/domain/customer"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+)
+
+type (
+	RevertRequest struct {
+		Id                      uuid.UUID
+		RevisionId              uuid.UUID
+		PropagateToReservations bool
+	}
+
+	RevertHandler struct {
+		repository            infrastructure.CustomerRepository
+		reservationRepository infrastructure.ReservationRepository
+	}
+)
+
+// Handle saves the revision as a normal edit, so it is validated and propagated the same way
+func (handler RevertHandler) Handle(ctx context.Context, req RevertRequest) (customer.State, error) {
+	current, err := handler.repository.GetById(ctx, req.Id)
+	if err != nil {
+		return current, err
+	}
+
+	state, err := handler.repository.GetRevision(ctx, req.Id, req.RevisionId)
+	if err != nil {
+		return current, err
+	}
+
+	state.Id = current.Id
+	change := ChangeOrCreateHandler{
+		repository:            handler.repository,
+		reservationRepository: handler.reservationRepository,
+	}
+
+	return change.Handle(ctx, ChangeOrCreateRequest{State: state, PropagateToReservations: req.PropagateToReservations})
+}
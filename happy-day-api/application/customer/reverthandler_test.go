@@ -0,0 +1,114 @@
+package customer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"happy_day/common"
+	"happy_day/domain/customer"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRevertCustomerWhenRevisionNotFound(t *testing.T) {
+	req := RevertRequest{Id: uuid.New(), RevisionId: uuid.New()}
+	repo := &infrastructure.MockCustomerRepository{}
+	repo.
+		On("GetById", mock.Anything, req.Id).
+		Return(customer.State{Id: req.Id}, nil)
+	repo.
+		On("GetRevision", mock.Anything, req.Id, req.RevisionId).
+		Return(customer.State{}, infrastructure.ErrRevisionNotFound)
+
+	handler := RevertHandler{repository: repo}
+	_, err := handler.Handle(context.Background(), req)
+	assert.Equal(t, infrastructure.ErrRevisionNotFound, err)
+}
+
+func TestRevertCustomer(t *testing.T) {
+	req := RevertRequest{Id: uuid.New(), RevisionId: uuid.New()}
+	current := customer.State{
+		Id:         req.Id,
+		Name:       common.RandString(10),
+		CreatedAt:  time.Now().Add(-time.Hour),
+		ModifiedAt: time.Now(),
+	}
+
+	revision := customer.State{
+		Id:         req.Id,
+		Name:       common.RandString(10),
+		Phones:     []customer.Phone{{Number: "11987654321"}},
+		CreatedAt:  current.CreatedAt,
+		ModifiedAt: current.CreatedAt,
+	}
+
+	expected := revision
+	expected.ModifiedAt = current.ModifiedAt
+
+	repo := &infrastructure.MockCustomerRepository{}
+	repo.
+		On("GetById", mock.Anything, req.Id).
+		Return(current, nil)
+	repo.
+		On("GetRevision", mock.Anything, req.Id, req.RevisionId).
+		Return(revision, nil)
+	repo.
+		On("Save", mock.Anything, expected).
+		Return(expected, nil)
+
+	handler := RevertHandler{repository: repo}
+	res, err := handler.Handle(context.Background(), req)
+	assert.Nil(t, err)
+	assert.Equal(t, revision.Name, res.Name)
+	repo.AssertExpectations(t)
+}
+
+func TestRevertCustomerWhenRevisionIsInvalid(t *testing.T) {
+	req := RevertRequest{Id: uuid.New(), RevisionId: uuid.New()}
+	repo := &infrastructure.MockCustomerRepository{}
+	repo.
+		On("GetById", mock.Anything, req.Id).
+		Return(customer.State{Id: req.Id}, nil)
+	repo.
+		On("GetRevision", mock.Anything, req.Id, req.RevisionId).
+		Return(customer.State{Id: req.Id, Name: common.RandString(10)}, nil)
+
+	handler := RevertHandler{repository: repo}
+	_, err := handler.Handle(context.Background(), req)
+	assert.Equal(t, infrastructure.ErrCustomerPhonesIsEmpty, err)
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestRevertCustomerWhenPropagateToReservations(t *testing.T) {
+	req := RevertRequest{Id: uuid.New(), RevisionId: uuid.New(), PropagateToReservations: true}
+	revision := customer.State{
+		Id:     req.Id,
+		Name:   common.RandString(10),
+		Phones: []customer.Phone{{Number: "11987654321"}},
+	}
+
+	repo := &infrastructure.MockCustomerRepository{}
+	repo.
+		On("GetById", mock.Anything, req.Id).
+		Return(customer.State{Id: req.Id}, nil)
+	repo.
+		On("GetRevision", mock.Anything, req.Id, req.RevisionId).
+		Return(revision, nil)
+	repo.
+		On("Save", mock.Anything, revision).
+		Return(revision, nil)
+
+	reservationRepo := &infrastructure.MockReservationRepository{}
+	reservationRepo.
+		On("UpdateCustomer", mock.Anything, revision).
+		Return(nil)
+
+	handler := RevertHandler{repository: repo, reservationRepository: reservationRepo}
+	_, err := handler.Handle(context.Background(), req)
+	assert.Nil(t, err)
+	reservationRepo.AssertExpectations(t)
+}
@@ -28,6 +28,10 @@ func (handler ChangeOrCreateHandler) Handle(ctx context.Context, req ChangeOrCre
 		return req.State, infrastructure.ErrProductPriceIsLessThanZero
 	}
 
+	if req.Stock < 0 {
+		return req.State, infrastructure.ErrProductStockIsLessThanZero
+	}
+
 	for _, p := range req.Products {
 		exists, err := handler.repository.Exists(ctx, p.Id)
 		if err != nil {
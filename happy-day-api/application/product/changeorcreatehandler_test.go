@@ -68,6 +68,22 @@ func TestCreateOrChangeProductWhenPriceIsLessThanZero(t *testing.T) {
 	assert.Equal(t, infrastructure.ErrProductPriceIsLessThanZero, err)
 }
 
+func TestCreateOrChangeProductWhenStockIsLessThanZero(t *testing.T) {
+	req := ChangeOrCreateRequest{
+		State: product.State{
+			Name:  common.RandString(10),
+			Price: 10,
+			Stock: -1,
+		},
+	}
+
+	handler := ChangeOrCreateHandler{}
+	_, err := handler.Handle(context.Background(), req)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, infrastructure.ErrProductStockIsLessThanZero, err)
+}
+
 func TestCreateOrChangeProductWhenErrToCheckIfExists(t *testing.T) {
 	req := ChangeOrCreateRequest{
 		State: product.State{
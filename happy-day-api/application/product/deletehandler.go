@@ -10,11 +10,13 @@ import (
 
 type (
 	DeleteRequest struct {
-		Id uuid.UUID
+		Id    uuid.UUID
+		Force bool
 	}
 
 	DeleteHandler struct {
-		repository infrastructure.ProductRepository
+		repository            infrastructure.ProductRepository
+		reservationRepository infrastructure.ReservationRepository
 	}
 )
 
@@ -28,5 +30,16 @@ func (handler DeleteHandler) Handle(ctx context.Context, req DeleteRequest) erro
 		return infrastructure.ErrExistOtherProductWithThisProduct
 	}
 
+	if !req.Force {
+		exits, err = handler.reservationRepository.ExistsOpenWithProduct(ctx, req.Id)
+		if err != nil {
+			return err
+		}
+
+		if exits {
+			return infrastructure.ErrProductInUse
+		}
+	}
+
 	return handler.repository.Delete(ctx, req.Id)
 }
@@ -51,6 +51,58 @@ func TestDeleteProductWhenExistAnyWithProduct(t *testing.T) {
 	assert.Equal(t, infrastructure.ErrExistOtherProductWithThisProduct, err)
 }
 
+func TestDeleteProductWhenExistOpenReservation(t *testing.T) {
+	req := DeleteRequest{
+		Id: uuid.New(),
+	}
+
+	repo := &infrastructure.MockProductRepository{}
+	repo.
+		On("ExistAnyWithProduct", mock.Anything, req.Id).
+		Return(false, nil)
+
+	reservationRepo := &infrastructure.MockReservationRepository{}
+	reservationRepo.
+		On("ExistsOpenWithProduct", mock.Anything, req.Id).
+		Return(true, nil)
+
+	handler := DeleteHandler{
+		repository:            repo,
+		reservationRepository: reservationRepo,
+	}
+
+	err := handler.Handle(context.Background(), req)
+	assert.NotNil(t, err)
+	assert.Equal(t, infrastructure.ErrProductInUse, err)
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestDeleteProductWhenForce(t *testing.T) {
+	req := DeleteRequest{
+		Id:    uuid.New(),
+		Force: true,
+	}
+
+	repo := &infrastructure.MockProductRepository{}
+	repo.
+		On("ExistAnyWithProduct", mock.Anything, req.Id).
+		Return(false, nil)
+
+	repo.
+		On("Delete", mock.Anything, req.Id).
+		Return(nil)
+
+	reservationRepo := &infrastructure.MockReservationRepository{}
+	handler := DeleteHandler{
+		repository:            repo,
+		reservationRepository: reservationRepo,
+	}
+
+	err := handler.Handle(context.Background(), req)
+	assert.Nil(t, err)
+	reservationRepo.AssertNotCalled(t, "ExistsOpenWithProduct", mock.Anything, mock.Anything)
+}
+
 func TestDeleteProduct(t *testing.T) {
 	req := DeleteRequest{
 		Id: uuid.New(),
@@ -65,8 +117,14 @@ func TestDeleteProduct(t *testing.T) {
 		On("Delete", mock.Anything, req.Id).
 		Return(nil)
 
+	reservationRepo := &infrastructure.MockReservationRepository{}
+	reservationRepo.
+		On("ExistsOpenWithProduct", mock.Anything, req.Id).
+		Return(false, nil)
+
 	handler := DeleteHandler{
-		repository: repo,
+		repository:            repo,
+		reservationRepository: reservationRepo,
 	}
 
 	err := handler.Handle(context.Background(), req)
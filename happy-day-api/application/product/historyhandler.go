@@ -0,0 +1,17 @@
+package product
+
+import (
+	"context"
+
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+)
+
+type HistoryHandler struct {
+	repository infrastructure.ProductRepository
+}
+
+func (handler HistoryHandler) Handle(ctx context.Context, req uuid.UUID) ([]infrastructure.Revision, error) {
+	return handler.repository.GetHistory(ctx, req)
+}
@@ -11,6 +11,9 @@ var ProviderSet = wire.NewSet(
 	ProvideGetByIdHandler,
 	ProvideChangeOrCreateHandler,
 	ProvideDeleteHandler,
+	ProvideRestoreHandler,
+	ProvideHistoryHandler,
+	ProvideRevertHandler,
 )
 
 func ProvideGetAllHandler(repository infrastructure.ProductRepository) GetAllHandler {
@@ -25,6 +28,23 @@ func ProvideChangeOrCreateHandler(repository infrastructure.ProductRepository) C
 	return ChangeOrCreateHandler{repository: repository}
 }
 
-func ProvideDeleteHandler(repository infrastructure.ProductRepository) DeleteHandler {
-	return DeleteHandler{repository: repository}
+func ProvideDeleteHandler(
+	repository infrastructure.ProductRepository,
+	reservationRepository infrastructure.ReservationRepository) DeleteHandler {
+	return DeleteHandler{
+		repository:            repository,
+		reservationRepository: reservationRepository,
+	}
+}
+
+func ProvideRestoreHandler(repository infrastructure.ProductRepository) RestoreHandler {
+	return RestoreHandler{repository: repository}
+}
+
+func ProvideHistoryHandler(repository infrastructure.ProductRepository) HistoryHandler {
+	return HistoryHandler{repository: repository}
+}
+
+func ProvideRevertHandler(repository infrastructure.ProductRepository) RevertHandler {
+	return RevertHandler{repository: repository}
 }
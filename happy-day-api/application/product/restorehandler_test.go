@@ -0,0 +1,34 @@
+package product
+
+import (
+	"context"
+	"testing"
+
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRestoreProductHandlerWhenNotFound(t *testing.T) {
+	repo := &infrastructure.MockProductRepository{}
+	repo.
+		On("Restore", mock.Anything, mock.Anything).
+		Return(infrastructure.ErrProductNotFound)
+
+	handler := RestoreHandler{repository: repo}
+	err := handler.Handle(context.Background(), uuid.New())
+	assert.Equal(t, infrastructure.ErrProductNotFound, err)
+}
+
+func TestRestoreProductHandler(t *testing.T) {
+	repo := &infrastructure.MockProductRepository{}
+	repo.
+		On("Restore", mock.Anything, mock.Anything).
+		Return(nil)
+
+	handler := RestoreHandler{repository: repo}
+	err := handler.Handle(context.Background(), uuid.New())
+	assert.Nil(t, err)
+}
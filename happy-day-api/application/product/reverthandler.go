@@ -0,0 +1,38 @@
+package product
+
+import (
+	"context"
+
+	"happy_day/domain/product"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+)
+
+type (
+	RevertRequest struct {
+		Id         uuid.UUID
+		RevisionId uuid.UUID
+	}
+
+	RevertHandler struct {
+		repository infrastructure.ProductRepository
+	}
+)
+
+// Handle saves the revision as a normal edit, so its values and components are checked again
+func (handler RevertHandler) Handle(ctx context.Context, req RevertRequest) (product.State, error) {
+	current, err := handler.repository.GetById(ctx, req.Id)
+	if err != nil {
+		return current, err
+	}
+
+	state, err := handler.repository.GetRevision(ctx, req.Id, req.RevisionId)
+	if err != nil {
+		return current, err
+	}
+
+	state.Id = current.Id
+	change := ChangeOrCreateHandler{repository: handler.repository}
+	return change.Handle(ctx, ChangeOrCreateRequest{State: state})
+}
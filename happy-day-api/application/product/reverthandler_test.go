@@ -0,0 +1,95 @@
+package product
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"happy_day/common"
+	"happy_day/domain/product"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRevertProductWhenRevisionNotFound(t *testing.T) {
+	req := RevertRequest{Id: uuid.New(), RevisionId: uuid.New()}
+	repo := &infrastructure.MockProductRepository{}
+	repo.
+		On("GetById", mock.Anything, req.Id).
+		Return(product.State{Id: req.Id}, nil)
+	repo.
+		On("GetRevision", mock.Anything, req.Id, req.RevisionId).
+		Return(product.State{}, infrastructure.ErrRevisionNotFound)
+
+	handler := RevertHandler{repository: repo}
+	_, err := handler.Handle(context.Background(), req)
+	assert.Equal(t, infrastructure.ErrRevisionNotFound, err)
+}
+
+func TestRevertProductWhenComponentWasRemoved(t *testing.T) {
+	req := RevertRequest{Id: uuid.New(), RevisionId: uuid.New()}
+	revision := product.State{
+		Id:       req.Id,
+		Name:     common.RandString(10),
+		Price:    10,
+		Products: []product.Product{{Id: uuid.New(), Quantity: 1}},
+	}
+
+	repo := &infrastructure.MockProductRepository{}
+	repo.
+		On("GetById", mock.Anything, req.Id).
+		Return(product.State{Id: req.Id}, nil)
+	repo.
+		On("GetRevision", mock.Anything, req.Id, req.RevisionId).
+		Return(revision, nil)
+	repo.
+		On("Exists", mock.Anything, revision.Products[0].Id).
+		Return(false, nil)
+
+	handler := RevertHandler{repository: repo}
+	_, err := handler.Handle(context.Background(), req)
+	assert.Equal(t, infrastructure.ErrProductNotFound, err)
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestRevertProduct(t *testing.T) {
+	req := RevertRequest{Id: uuid.New(), RevisionId: uuid.New()}
+	current := product.State{
+		Id:         req.Id,
+		Name:       common.RandString(10),
+		Price:      10,
+		CreatedAt:  time.Now().Add(-time.Hour),
+		ModifiedAt: time.Now(),
+	}
+
+	revision := product.State{
+		Id:         req.Id,
+		Name:       common.RandString(10),
+		Price:      20,
+		CreatedAt:  current.CreatedAt,
+		ModifiedAt: current.CreatedAt,
+	}
+
+	expected := revision
+	expected.ModifiedAt = current.ModifiedAt
+
+	repo := &infrastructure.MockProductRepository{}
+	repo.
+		On("GetById", mock.Anything, req.Id).
+		Return(current, nil)
+	repo.
+		On("GetRevision", mock.Anything, req.Id, req.RevisionId).
+		Return(revision, nil)
+	repo.
+		On("Save", mock.Anything, expected).
+		Return(expected, nil)
+
+	handler := RevertHandler{repository: repo}
+	res, err := handler.Handle(context.Background(), req)
+	assert.Nil(t, err)
+	assert.Equal(t, revision.Price, res.Price)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,202 @@
+package reservation
+
+import (
+	"context"
+	"time"
+
+	"happy_day/common"
+	"happy_day/domain/product"
+	"happy_day/domain/reservation"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+)
+
+type productUsage struct {
+	ReservationId uuid.UUID
+	From          time.Time
+	To            time.Time
+	Quantity      int64
+}
+
+func ensureAvailability(
+	ctx context.Context,
+	productRepository infrastructure.ProductRepository,
+	reservationRepository infrastructure.ReservationRepository,
+	state reservation.State) error {
+	if len(state.Products) == 0 || state.Delivery.At.IsZero() || state.PickUp.At.IsZero() {
+		return nil
+	}
+
+	if state.Status == reservation.Cancelled {
+		return nil
+	}
+
+	reservations, err := reservationRepository.GetByPeriod(ctx, state.Delivery.At, state.PickUp.At)
+	if err != nil {
+		return err
+	}
+
+	reservations = common.Filter(reservations, func(item reservation.State) bool {
+		return item.Id != state.Id
+	})
+
+	// The products of a reservation already saved may have been deleted since, they are not required
+	var required []uuid.UUID
+	if state.Id == uuid.Nil {
+		required = reservationProductIds(state)
+	}
+
+	ids := reservationProductIds(state)
+	for _, item := range reservations {
+		ids = append(ids, reservationProductIds(item)...)
+	}
+
+	products, err := loadProducts(ctx, productRepository, required, ids)
+	if err != nil {
+		return err
+	}
+
+	requested := expandReservation(products, state)
+	usages := reservationUsages(products, reservations)
+	for id, quantity := range requested {
+		stock := products[id].Stock
+		// Products without stock are not controlled
+		if stock <= 0 {
+			continue
+		}
+
+		if peakUsage(usages[id], state.Delivery.At, state.PickUp.At)+quantity > stock {
+			return infrastructure.ErrReservationProductNotAvailable
+		}
+	}
+
+	return nil
+}
+
+func reservationProductIds(state reservation.State) []uuid.UUID {
+	return common.Map(state.Products, func(item reservation.Product) uuid.UUID {
+		return item.Id
+	})
+}
+
+// loadProducts loads the required products, the reserved ones and their components, only the required ones must exist
+func loadProducts(
+	ctx context.Context,
+	repository infrastructure.ProductRepository,
+	required, reserved []uuid.UUID) (map[uuid.UUID]product.State, error) {
+	products := map[uuid.UUID]product.State{}
+	pending := common.Distinct(append(append([]uuid.UUID{}, required...), reserved...))
+	for len(pending) > 0 {
+		states, err := getKnownProducts(ctx, repository, pending, required)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, state := range states {
+			products[state.Id] = state
+		}
+
+		var components []uuid.UUID
+		for _, state := range states {
+			for _, component := range state.Products {
+				if _, exists := products[component.Id]; !exists {
+					components = append(components, component.Id)
+				}
+			}
+		}
+
+		pending = common.Distinct(components)
+	}
+
+	return products, nil
+}
+
+// getKnownProducts skips the products deleted since they were reserved, they are no longer controlled
+func getKnownProducts(
+	ctx context.Context,
+	repository infrastructure.ProductRepository,
+	ids, required []uuid.UUID) ([]product.State, error) {
+	states, err := repository.GetByProducts(ctx, ids)
+	if err != infrastructure.ErrOneProductNotFound {
+		return states, err
+	}
+
+	states = make([]product.State, 0, len(ids))
+	for _, id := range ids {
+		state, err := repository.GetById(ctx, id)
+		if err == infrastructure.ErrProductNotFound && !common.Contains(required, id) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		states = append(states, state)
+	}
+
+	return states, nil
+}
+
+func expandReservation(products map[uuid.UUID]product.State, state reservation.State) map[uuid.UUID]int64 {
+	res := map[uuid.UUID]int64{}
+	for _, item := range state.Products {
+		expandProduct(products, item.Id, item.Quantity, res)
+	}
+
+	return res
+}
+
+func expandProduct(products map[uuid.UUID]product.State, id uuid.UUID, quantity int64, res map[uuid.UUID]int64) {
+	state, exists := products[id]
+	if !exists || len(state.Products) == 0 {
+		res[id] += quantity
+		return
+	}
+
+	for _, component := range state.Products {
+		expandProduct(products, component.Id, component.Quantity*quantity, res)
+	}
+}
+
+func reservationUsages(products map[uuid.UUID]product.State, reservations []reservation.State) map[uuid.UUID][]productUsage {
+	usages := map[uuid.UUID][]productUsage{}
+	for _, item := range reservations {
+		for id, quantity := range expandReservation(products, item) {
+			usages[id] = append(usages[id], productUsage{
+				ReservationId: item.Id,
+				From:          item.Delivery.At,
+				To:            item.PickUp.At,
+				Quantity:      quantity,
+			})
+		}
+	}
+
+	return usages
+}
+
+func peakUsage(usages []productUsage, from, to time.Time) int64 {
+	points := []time.Time{from}
+	for _, usage := range usages {
+		if usage.From.After(from) && usage.From.Before(to) {
+			points = append(points, usage.From)
+		}
+	}
+
+	var peak int64
+	for _, point := range points {
+		var total int64
+		for _, usage := range usages {
+			if !usage.From.After(point) && usage.To.After(point) {
+				total += usage.Quantity
+			}
+		}
+
+		if total > peak {
+			peak = total
+		}
+	}
+
+	return peak
+}
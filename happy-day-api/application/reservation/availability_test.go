@@ -0,0 +1,190 @@
+package reservation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"happy_day/domain/product"
+	"happy_day/domain/reservation"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEnsureAvailabilityWhenScheduleIsNotDefined(t *testing.T) {
+	err := ensureAvailability(context.Background(), nil, nil, reservation.State{
+		Products: []reservation.Product{{Id: uuid.New(), Quantity: 1}},
+	})
+
+	assert.Nil(t, err)
+}
+
+func TestEnsureAvailability(t *testing.T) {
+	castle := uuid.MustParse("1fd49fff-e5c6-4878-9c42-f2fa77bdc2fe")
+	table := uuid.MustParse("4491c392-4b6e-4a6e-aa64-c2897a258451")
+	kit := uuid.MustParse("b387b182-e12a-4757-99c8-31b6596d102d")
+	saturday := time.Date(2022, 12, 3, 10, 0, 0, 0, time.UTC)
+
+	products := []product.State{
+		{Id: castle, Stock: 1},
+		{Id: table, Stock: 10},
+		{Id: kit, Products: []product.Product{{Id: table, Quantity: 4}}},
+	}
+
+	type param struct {
+		name         string
+		products     []reservation.Product
+		reservations []reservation.State
+		expected     error
+	}
+
+	cases := []param{
+		{
+			name:     "No other reservation",
+			products: []reservation.Product{{Id: castle, Quantity: 1}},
+			expected: nil,
+		},
+		{
+			name:     "Product already reserved",
+			products: []reservation.Product{{Id: castle, Quantity: 1}},
+			reservations: []reservation.State{
+				{
+					Id:       uuid.New(),
+					Products: []reservation.Product{{Id: castle, Quantity: 1}},
+					Delivery: reservation.DeliveryOrPickUp{At: saturday.Add(-2 * time.Hour)},
+					PickUp:   reservation.DeliveryOrPickUp{At: saturday.Add(2 * time.Hour)},
+				},
+			},
+			expected: infrastructure.ErrReservationProductNotAvailable,
+		},
+		{
+			name:     "Reservations not overlapping each other",
+			products: []reservation.Product{{Id: table, Quantity: 4}},
+			reservations: []reservation.State{
+				{
+					Id:       uuid.New(),
+					Products: []reservation.Product{{Id: table, Quantity: 6}},
+					Delivery: reservation.DeliveryOrPickUp{At: saturday},
+					PickUp:   reservation.DeliveryOrPickUp{At: saturday.Add(2 * time.Hour)},
+				},
+				{
+					Id:       uuid.New(),
+					Products: []reservation.Product{{Id: table, Quantity: 6}},
+					Delivery: reservation.DeliveryOrPickUp{At: saturday.Add(3 * time.Hour)},
+					PickUp:   reservation.DeliveryOrPickUp{At: saturday.Add(5 * time.Hour)},
+				},
+			},
+			expected: nil,
+		},
+		{
+			name:     "Kit expanded into components",
+			products: []reservation.Product{{Id: kit, Quantity: 2}},
+			reservations: []reservation.State{
+				{
+					Id:       uuid.New(),
+					Products: []reservation.Product{{Id: table, Quantity: 3}},
+					Delivery: reservation.DeliveryOrPickUp{At: saturday},
+					PickUp:   reservation.DeliveryOrPickUp{At: saturday.Add(2 * time.Hour)},
+				},
+			},
+			expected: infrastructure.ErrReservationProductNotAvailable,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(k *testing.T) {
+			state := reservation.State{
+				Id:       uuid.New(),
+				Products: c.products,
+				Delivery: reservation.DeliveryOrPickUp{At: saturday.Add(-time.Hour)},
+				PickUp:   reservation.DeliveryOrPickUp{At: saturday.Add(6 * time.Hour)},
+			}
+
+			productRepository := &infrastructure.MockProductRepository{}
+			productRepository.
+				On("GetByProducts", mock.Anything, mock.Anything).
+				Return(products, nil)
+
+			reservationRepository := &infrastructure.MockReservationRepository{}
+			reservationRepository.
+				On("GetByPeriod", mock.Anything, state.Delivery.At, state.PickUp.At).
+				Return(c.reservations, nil)
+
+			err := ensureAvailability(context.Background(), productRepository, reservationRepository, state)
+			assert.Equal(k, c.expected, err)
+		})
+	}
+}
+
+func TestEnsureAvailabilityWhenReservedProductWasDeleted(t *testing.T) {
+	castle := uuid.New()
+	deleted := uuid.New()
+	saturday := time.Date(2022, 12, 3, 10, 0, 0, 0, time.UTC)
+
+	reservationRepository := &infrastructure.MockReservationRepository{}
+	reservationRepository.
+		On("GetByPeriod", mock.Anything, mock.Anything, mock.Anything).
+		Return([]reservation.State{
+			{
+				Id:       uuid.New(),
+				Products: []reservation.Product{{Id: deleted, Quantity: 1}},
+				Delivery: reservation.DeliveryOrPickUp{At: saturday},
+				PickUp:   reservation.DeliveryOrPickUp{At: saturday.Add(2 * time.Hour)},
+			},
+		}, nil)
+
+	productRepository := &infrastructure.MockProductRepository{}
+	productRepository.
+		On("GetByProducts", mock.Anything, mock.Anything).
+		Return([]product.State(nil), infrastructure.ErrOneProductNotFound)
+	productRepository.
+		On("GetById", mock.Anything, castle).
+		Return(product.State{Id: castle, Stock: 1}, nil)
+	productRepository.
+		On("GetById", mock.Anything, deleted).
+		Return(product.State{}, infrastructure.ErrProductNotFound)
+
+	err := ensureAvailability(context.Background(), productRepository, reservationRepository, reservation.State{
+		Id:       uuid.New(),
+		Products: []reservation.Product{{Id: castle, Quantity: 1}},
+		Delivery: reservation.DeliveryOrPickUp{At: saturday},
+		PickUp:   reservation.DeliveryOrPickUp{At: saturday.Add(2 * time.Hour)},
+	})
+
+	assert.Nil(t, err)
+}
+
+func TestEnsureAvailabilityWhenRequestedProductWasDeleted(t *testing.T) {
+	deleted := uuid.New()
+	saturday := time.Date(2022, 12, 3, 10, 0, 0, 0, time.UTC)
+
+	reservationRepository := &infrastructure.MockReservationRepository{}
+	reservationRepository.
+		On("GetByPeriod", mock.Anything, mock.Anything, mock.Anything).
+		Return([]reservation.State{}, nil)
+
+	productRepository := &infrastructure.MockProductRepository{}
+	productRepository.
+		On("GetByProducts", mock.Anything, mock.Anything).
+		Return([]product.State(nil), infrastructure.ErrOneProductNotFound)
+	productRepository.
+		On("GetById", mock.Anything, deleted).
+		Return(product.State{}, infrastructure.ErrProductNotFound)
+
+	state := reservation.State{
+		Products: []reservation.Product{{Id: deleted, Quantity: 1}},
+		Delivery: reservation.DeliveryOrPickUp{At: saturday},
+		PickUp:   reservation.DeliveryOrPickUp{At: saturday.Add(2 * time.Hour)},
+	}
+
+	err := ensureAvailability(context.Background(), productRepository, reservationRepository, state)
+	assert.Equal(t, infrastructure.ErrProductNotFound, err)
+
+	// A saved reservation keeps working after its product is deleted
+	state.Id = uuid.New()
+	err = ensureAvailability(context.Background(), productRepository, reservationRepository, state)
+	assert.Nil(t, err)
+}
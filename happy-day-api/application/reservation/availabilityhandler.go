@@ -0,0 +1,150 @@
+package reservation
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"happy_day/common"
+	"happy_day/domain/product"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+)
+
+const maxAvailabilitySlots = 366
+
+type (
+	AvailabilityRequest struct {
+		Products []uuid.UUID
+		From     time.Time
+		To       time.Time
+		Slot     time.Duration
+	}
+
+	AvailabilityResponse struct {
+		ProductId uuid.UUID                  `json:"productId"`
+		Slots     []AvailabilitySlotResponse `json:"slots"`
+	}
+
+	AvailabilitySlotResponse struct {
+		From         time.Time   `json:"from"`
+		To           time.Time   `json:"to"`
+		Unlimited    bool        `json:"unlimited,omitempty"`
+		Stock        int64       `json:"stock"`
+		Free         int64       `json:"free"`
+		Reserved     int64       `json:"reserved"`
+		Reservations []uuid.UUID `json:"reservations"`
+	}
+
+	AvailabilityHandler struct {
+		options               infrastructure.ReservationOptions
+		productRepository     infrastructure.ProductRepository
+		reservationRepository infrastructure.ReservationRepository
+	}
+)
+
+func (handler AvailabilityHandler) Handle(ctx context.Context, req AvailabilityRequest) ([]AvailabilityResponse, error) {
+	if len(req.Products) == 0 {
+		return nil, infrastructure.ErrProductListIsEmpty
+	}
+
+	if req.Slot <= 0 {
+		req.Slot = 24 * time.Hour
+	}
+
+	if req.Slot == 24*time.Hour {
+		req.From = handler.options.StartOfDay(req.From)
+		req.To = handler.options.StartOfDay(req.To.Add(-time.Nanosecond)).AddDate(0, 0, 1)
+	}
+
+	if !req.To.After(req.From) || req.To.Sub(req.From)/req.Slot > maxAvailabilitySlots {
+		return nil, infrastructure.ErrAvailabilityPeriodIsInvalid
+	}
+
+	reservations, err := handler.reservationRepository.GetByPeriod(ctx, req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uuid.UUID
+	for _, item := range reservations {
+		ids = append(ids, reservationProductIds(item)...)
+	}
+
+	products, err := loadProducts(ctx, handler.productRepository, req.Products, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	usages := reservationUsages(products, reservations)
+	return common.Map(common.Distinct(req.Products), func(id uuid.UUID) AvailabilityResponse {
+		components := map[uuid.UUID]int64{}
+		expandProduct(products, id, 1, components)
+
+		res := AvailabilityResponse{ProductId: id}
+		for from := req.From; from.Before(req.To); from = from.Add(req.Slot) {
+			res.Slots = append(res.Slots, availabilitySlot(products, usages, components, from, from.Add(req.Slot)))
+		}
+
+		return res
+	}), nil
+}
+
+func availabilitySlot(
+	products map[uuid.UUID]product.State,
+	usages map[uuid.UUID][]productUsage,
+	components map[uuid.UUID]int64,
+	from, to time.Time) AvailabilitySlotResponse {
+	slot := AvailabilitySlotResponse{
+		From:         from,
+		To:           to,
+		Unlimited:    true,
+		Reservations: make([]uuid.UUID, 0),
+	}
+
+	var reserved int64
+	stock := int64(math.MaxInt64)
+	free := int64(math.MaxInt64)
+	for id, quantity := range components {
+		for _, usage := range usages[id] {
+			if usage.From.Before(to) && usage.To.After(from) {
+				slot.Reservations = append(slot.Reservations, usage.ReservationId)
+			}
+		}
+
+		peak := peakUsage(usages[id], from, to)
+		if units := (peak + quantity - 1) / quantity; units > reserved {
+			reserved = units
+		}
+
+		componentStock := products[id].Stock
+		if componentStock <= 0 {
+			continue
+		}
+
+		componentFree := componentStock - peak
+		if componentFree < 0 {
+			componentFree = 0
+		}
+
+		slot.Unlimited = false
+		if componentStock/quantity < stock {
+			stock = componentStock / quantity
+		}
+
+		if componentFree/quantity < free {
+			free = componentFree / quantity
+		}
+	}
+
+	slot.Reservations = common.Distinct(slot.Reservations)
+	slot.Reserved = reserved
+	if !slot.Unlimited {
+		slot.Stock = stock
+		slot.Free = free
+		slot.Reserved = stock - free
+	}
+
+	return slot
+}
@@ -0,0 +1,204 @@
+package reservation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"happy_day/common"
+	"happy_day/domain/product"
+	"happy_day/domain/reservation"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAvailabilityWhenProductListIsEmpty(t *testing.T) {
+	handler := AvailabilityHandler{}
+	_, err := handler.Handle(context.Background(), AvailabilityRequest{})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, infrastructure.ErrProductListIsEmpty, err)
+}
+
+func TestAvailabilityWhenPeriodIsInvalid(t *testing.T) {
+	now := time.Now().UTC()
+	periods := []AvailabilityRequest{
+		{Products: []uuid.UUID{uuid.New()}, From: now, To: now.Add(-48 * time.Hour)},
+		{Products: []uuid.UUID{uuid.New()}, From: now, To: now.AddDate(2, 0, 0)},
+	}
+
+	for _, req := range periods {
+		handler := AvailabilityHandler{}
+		_, err := handler.Handle(context.Background(), req)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, infrastructure.ErrAvailabilityPeriodIsInvalid, err)
+	}
+}
+
+func TestAvailabilityWhenErrToGetByPeriod(t *testing.T) {
+	expectedErr := errors.New(common.RandString(10))
+	reservationRepository := &infrastructure.MockReservationRepository{}
+	reservationRepository.
+		On("GetByPeriod", mock.Anything, mock.Anything, mock.Anything).
+		Return([]reservation.State{}, expectedErr)
+
+	handler := AvailabilityHandler{reservationRepository: reservationRepository}
+	_, err := handler.Handle(context.Background(), AvailabilityRequest{
+		Products: []uuid.UUID{uuid.New()},
+		From:     time.Now().UTC(),
+		To:       time.Now().UTC().Add(24 * time.Hour),
+	})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, expectedErr, err)
+}
+
+func TestAvailabilityWhenProductNotFound(t *testing.T) {
+	unknown := uuid.New()
+	deleted := uuid.New()
+	from := time.Date(2022, 12, 3, 0, 0, 0, 0, time.UTC)
+
+	reservationRepository := &infrastructure.MockReservationRepository{}
+	reservationRepository.
+		On("GetByPeriod", mock.Anything, mock.Anything, mock.Anything).
+		Return([]reservation.State{
+			{
+				Id:       uuid.New(),
+				Products: []reservation.Product{{Id: deleted, Quantity: 1}},
+				Delivery: reservation.DeliveryOrPickUp{At: from.Add(10 * time.Hour)},
+				PickUp:   reservation.DeliveryOrPickUp{At: from.Add(12 * time.Hour)},
+			},
+		}, nil)
+
+	productRepository := &infrastructure.MockProductRepository{}
+	productRepository.
+		On("GetByProducts", mock.Anything, mock.Anything).
+		Return([]product.State(nil), infrastructure.ErrOneProductNotFound)
+	productRepository.
+		On("GetById", mock.Anything, mock.Anything).
+		Return(product.State{}, infrastructure.ErrProductNotFound)
+
+	handler := AvailabilityHandler{productRepository: productRepository, reservationRepository: reservationRepository}
+	_, err := handler.Handle(context.Background(), AvailabilityRequest{
+		Products: []uuid.UUID{unknown},
+		From:     from,
+		To:       from.Add(24 * time.Hour),
+	})
+
+	assert.Equal(t, infrastructure.ErrProductNotFound, err)
+}
+
+func TestAvailability(t *testing.T) {
+	table := uuid.MustParse("4491c392-4b6e-4a6e-aa64-c2897a258451")
+	chair := uuid.MustParse("1fd49fff-e5c6-4878-9c42-f2fa77bdc2fe")
+	kit := uuid.MustParse("b387b182-e12a-4757-99c8-31b6596d102d")
+	day := time.Date(2022, 12, 12, 0, 0, 0, 0, time.UTC)
+
+	products := []product.State{
+		{Id: table, Stock: 10},
+		{Id: chair, Stock: 40},
+		{
+			Id: kit,
+			Products: []product.Product{
+				{Id: table, Quantity: 1},
+				{Id: chair, Quantity: 4},
+			},
+		},
+	}
+
+	reserved := reservation.State{
+		Id:       uuid.New(),
+		Products: []reservation.Product{{Id: kit, Quantity: 3}, {Id: chair, Quantity: 20}},
+		Delivery: reservation.DeliveryOrPickUp{At: day.Add(10 * time.Hour)},
+		PickUp:   reservation.DeliveryOrPickUp{At: day.Add(20 * time.Hour)},
+	}
+
+	productRepository := &infrastructure.MockProductRepository{}
+	productRepository.
+		On("GetByProducts", mock.Anything, mock.Anything).
+		Return(products, nil)
+
+	reservationRepository := &infrastructure.MockReservationRepository{}
+	reservationRepository.
+		On("GetByPeriod", mock.Anything, mock.Anything, mock.Anything).
+		Return([]reservation.State{reserved}, nil)
+
+	handler := AvailabilityHandler{
+		productRepository:     productRepository,
+		reservationRepository: reservationRepository,
+	}
+
+	res, err := handler.Handle(context.Background(), AvailabilityRequest{
+		Products: []uuid.UUID{table, kit},
+		From:     day,
+		To:       day.AddDate(0, 0, 2),
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, res, 2)
+
+	assert.Equal(t, table, res[0].ProductId)
+	assert.Len(t, res[0].Slots, 2)
+	assert.Equal(t, AvailabilitySlotResponse{
+		From:         day,
+		To:           day.AddDate(0, 0, 1),
+		Stock:        10,
+		Free:         7,
+		Reserved:     3,
+		Reservations: []uuid.UUID{reserved.Id},
+	}, res[0].Slots[0])
+	assert.Equal(t, int64(10), res[0].Slots[1].Free)
+	assert.Empty(t, res[0].Slots[1].Reservations)
+
+	assert.Equal(t, kit, res[1].ProductId)
+	assert.Equal(t, int64(10), res[1].Slots[0].Stock)
+	assert.Equal(t, int64(2), res[1].Slots[0].Free)
+	assert.Equal(t, int64(10), res[1].Slots[1].Free)
+}
+
+func TestAvailabilityWhenUsingBusinessTimeZone(t *testing.T) {
+	table := uuid.New()
+	location := time.FixedZone("BRT", -3*60*60)
+	day := time.Date(2022, 12, 12, 0, 0, 0, 0, location)
+
+	// A party at 22:00 in the business time zone is already the next day in UTC
+	reserved := reservation.State{
+		Id:       uuid.New(),
+		Products: []reservation.Product{{Id: table, Quantity: 3}},
+		Delivery: reservation.DeliveryOrPickUp{At: time.Date(2022, 12, 13, 1, 0, 0, 0, time.UTC)},
+		PickUp:   reservation.DeliveryOrPickUp{At: time.Date(2022, 12, 13, 2, 0, 0, 0, time.UTC)},
+	}
+
+	productRepository := &infrastructure.MockProductRepository{}
+	productRepository.
+		On("GetByProducts", mock.Anything, mock.Anything).
+		Return([]product.State{{Id: table, Stock: 10}}, nil)
+
+	reservationRepository := &infrastructure.MockReservationRepository{}
+	reservationRepository.
+		On("GetByPeriod", mock.Anything, mock.Anything, mock.Anything).
+		Return([]reservation.State{reserved}, nil)
+
+	handler := AvailabilityHandler{
+		options:               infrastructure.ReservationOptions{Location: location},
+		productRepository:     productRepository,
+		reservationRepository: reservationRepository,
+	}
+
+	res, err := handler.Handle(context.Background(), AvailabilityRequest{
+		Products: []uuid.UUID{table},
+		From:     day.Add(12 * time.Hour),
+		To:       day.Add(13 * time.Hour),
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, res[0].Slots, 1)
+	assert.True(t, day.Equal(res[0].Slots[0].From))
+	assert.Equal(t, int64(7), res[0].Slots[0].Free)
+	assert.Equal(t, []uuid.UUID{reserved.Id}, res[0].Slots[0].Reservations)
+}
@@ -2,6 +2,7 @@ package reservation
 
 import (
 	"context"
+	"time"
 
 	"happy_day/application/customer"
 	"happy_day/domain/reservation"
@@ -14,51 +15,157 @@ type (
 	ChangeRequest struct {
 		Id                  uuid.UUID                        `json:"id"`
 		Discount            float64                          `json:"discount"`
+		DiscountPercentage  float64                          `json:"discountPercentage,omitempty"`
 		Delivery            reservation.DeliveryOrPickUp     `json:"delivery"`
 		PickUp              reservation.DeliveryOrPickUp     `json:"pickUp"`
 		PaymentInstallments []reservation.PaymentInstallment `json:"paymentInstallments,omitempty"`
 		Comment             string                           `json:"comment,omitempty"`
+		CustomerId          uuid.UUID                        `json:"customerId,omitempty"`
 		Customer            reservation.Customer             `json:"customer"`
 		Address             reservation.Address              `json:"address"`
 	}
 
 	ChangeHandler struct {
-		repository infrastructure.ReservationRepository
+		pricingOptions        infrastructure.PricingOptions
+		reservationOptions    infrastructure.ReservationOptions
+		customerRepository    infrastructure.CustomerRepository
+		productRepository     infrastructure.ProductRepository
+		reservationRepository infrastructure.ReservationRepository
+		staffRepository       infrastructure.StaffRepository
 	}
 )
 
 func (handler ChangeHandler) Handle(ctx context.Context, req ChangeRequest) (reservation.State, error) {
-	for _, item := range req.PaymentInstallments {
-		if item.Amount <= 0 {
-			return reservation.State{}, infrastructure.ErrReservationPaymentInstallmentAmount
-		}
-	}
-
-	err := customer.Validate(req.Customer.State)
+	err := validateDetails(req.PaymentInstallments, req.CustomerId, req.Customer, req.Address)
 	if err != nil {
 		return reservation.State{}, err
 	}
 
-	err = validateAddress(req.Address)
+	state, err := handler.reservationRepository.GetById(ctx, req.Id)
 	if err != nil {
 		return reservation.State{}, err
 	}
 
-	state, err := handler.repository.GetById(ctx, req.Id)
+	// Only a draft may still be completed without a schedule
+	if currentStatus(state) != reservation.Draft || !req.Delivery.At.IsZero() || !req.PickUp.At.IsZero() {
+		err = validateSchedule(handler.reservationOptions, state, req.Delivery, req.PickUp)
+		if err != nil {
+			return reservation.State{}, err
+		}
+	}
+
+	current := state
+	state.PaymentInstallments, err = mergeInstallments(current.PaymentInstallments, req.PaymentInstallments)
 	if err != nil {
 		return reservation.State{}, err
 	}
 
 	state.Delivery = req.Delivery
 	state.PickUp = req.PickUp
-	state.PaymentInstallments = req.PaymentInstallments
 	state.Comment = req.Comment
-	state.Customer = req.Customer
 	state.Address = req.Address
-	state.Discount = req.Discount
-	state.FinalPrice = state.Price - state.Discount
+	err = applyDiscount(handler.pricingOptions, &state, req.Discount, req.DiscountPercentage)
+	if err != nil {
+		return reservation.State{}, err
+	}
+
+	err = ensureCrew(ctx, handler.reservationOptions, handler.staffRepository, handler.reservationRepository, current, &state)
+	if err != nil {
+		return reservation.State{}, err
+	}
+
+	// A customer sent without id is the one already linked, it is only created when there is none yet
+	customerId := req.CustomerId
+	if customerId == uuid.Nil && req.Customer.Id == uuid.Nil {
+		customerId = state.CustomerId
+	}
 
-	return handler.repository.Save(ctx, state)
+	return saveDetails(ctx, handler.customerRepository, handler.productRepository, handler.reservationRepository,
+		state, customerId, req.Customer)
+}
+
+// validateDetails checks what the creation and the change receive before loading anything
+func validateDetails(
+	installments []reservation.PaymentInstallment,
+	customerId uuid.UUID,
+	inline reservation.Customer,
+	address reservation.Address) error {
+	for _, item := range installments {
+		if item.Amount <= 0 {
+			return infrastructure.ErrReservationPaymentInstallmentAmount
+		}
+	}
+
+	if customerId == uuid.Nil {
+		err := customer.Validate(inline.State)
+		if err != nil {
+			return err
+		}
+	}
+
+	return validateAddress(address)
+}
+
+// saveDetails checks the installments and the availability, links the customer when there is one and saves
+func saveDetails(
+	ctx context.Context,
+	customerRepository infrastructure.CustomerRepository,
+	productRepository infrastructure.ProductRepository,
+	reservationRepository infrastructure.ReservationRepository,
+	state reservation.State,
+	customerId uuid.UUID,
+	inline reservation.Customer) (reservation.State, error) {
+	err := validateInstallments(state)
+	if err != nil {
+		return reservation.State{}, err
+	}
+
+	updateBalance(&state)
+
+	err = ensureAvailability(ctx, productRepository, reservationRepository, state)
+	if err != nil {
+		return reservation.State{}, err
+	}
+
+	var created bool
+	if customerId != uuid.Nil || inline.Id != uuid.Nil || len(inline.Name) > 0 {
+		state.Customer, created, err = resolveCustomer(ctx, customerRepository, customerId, inline)
+		if err != nil {
+			return reservation.State{}, err
+		}
+
+		state.CustomerId = state.Customer.Id
+	}
+
+	saved, err := reservationRepository.Save(ctx, state)
+	if err != nil && created {
+		// The customer was only created for this reservation, it must not be left behind
+		_ = customerRepository.Delete(ctx, state.CustomerId)
+	}
+
+	return saved, err
+}
+
+// validateSchedule applies the lead time and the business hours only to the times being changed
+func validateSchedule(
+	options infrastructure.ReservationOptions,
+	current reservation.State,
+	delivery, pickUp reservation.DeliveryOrPickUp) error {
+	if delivery.At.IsZero() || pickUp.At.IsZero() || !pickUp.At.After(delivery.At) {
+		return infrastructure.ErrReservationScheduleIsInvalid
+	}
+
+	deliveryChanged := !delivery.At.Equal(current.Delivery.At)
+	if deliveryChanged && delivery.At.Before(time.Now().UTC().Add(options.MinimumLeadTime)) {
+		return infrastructure.ErrReservationLeadTimeIsTooShort
+	}
+
+	if (deliveryChanged && !options.InBusinessHours(delivery.At)) ||
+		(!pickUp.At.Equal(current.PickUp.At) && !options.InBusinessHours(pickUp.At)) {
+		return infrastructure.ErrReservationOutsideBusinessHours
+	}
+
+	return nil
 }
 
 func validateAddress(state reservation.Address) error {
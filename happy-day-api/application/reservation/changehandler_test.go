@@ -173,7 +173,7 @@ func TestChangeReservationHandlerWhenGetById(t *testing.T) {
 		On("GetById", mock.Anything, req.Id).
 		Return(reservation.State{}, expectedErr)
 
-	handler := ChangeHandler{repository: repository}
+	handler := ChangeHandler{reservationRepository: repository}
 	_, err := handler.Handle(context.Background(), req)
 
 	assert.NotNil(t, err)
@@ -210,7 +210,402 @@ func TestChangeReservationHandler(t *testing.T) {
 		On("Save", mock.Anything, mock.Anything).
 		Return(reservation.State{}, nil)
 
-	handler := ChangeHandler{repository: repository}
+	customerRepository := &infrastructure.MockCustomerRepository{}
+	customerRepository.
+		On("Save", mock.Anything, req.Customer.State).
+		Return(customer.State{Id: uuid.New()}, nil)
+
+	handler := ChangeHandler{customerRepository: customerRepository, reservationRepository: repository}
+	_, err := handler.Handle(context.Background(), req)
+	assert.Nil(t, err)
+}
+
+func TestChangeReservationHandlerWhenInstallmentsMismatchFinalPrice(t *testing.T) {
+	req := ChangeRequest{
+		Id:       uuid.New(),
+		Discount: 10,
+		PaymentInstallments: []reservation.PaymentInstallment{
+			{Amount: 50, Method: reservation.Pix},
+			{Amount: 50, Method: reservation.Cash},
+		},
+		Customer: reservation.Customer{
+			State: customer.State{
+				Name:   common.RandString(10),
+				Phones: []customer.Phone{{Number: "123456789"}},
+			},
+		},
+		Address: reservation.Address{
+			City:       common.RandString(10),
+			Street:     common.RandString(10),
+			Number:     common.RandString(10),
+			PostalCode: common.RandString(10),
+		},
+	}
+
+	repository := &infrastructure.MockReservationRepository{}
+	repository.
+		On("GetById", mock.Anything, req.Id).
+		Return(reservation.State{Price: 100}, nil)
+
+	handler := ChangeHandler{reservationRepository: repository}
+	_, err := handler.Handle(context.Background(), req)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, infrastructure.ErrReservationInstallmentsMismatch, err)
+}
+
+func TestChangeReservationHandlerComputeBalance(t *testing.T) {
+	paidAt := time.Now().UTC()
+	req := ChangeRequest{
+		Id:       uuid.New(),
+		Discount: 10,
+		PaymentInstallments: []reservation.PaymentInstallment{
+			{Amount: 40.3, Method: reservation.Pix},
+			{Amount: 49.7, Method: reservation.Cash, Status: reservation.Paid, PaidAt: &paidAt},
+		},
+		Customer: reservation.Customer{
+			State: customer.State{
+				Name:   common.RandString(10),
+				Phones: []customer.Phone{{Number: "123456789"}},
+			},
+		},
+		Address: reservation.Address{
+			City:       common.RandString(10),
+			Street:     common.RandString(10),
+			Number:     common.RandString(10),
+			PostalCode: common.RandString(10),
+		},
+	}
+
+	repository := &infrastructure.MockReservationRepository{}
+	repository.
+		On("GetById", mock.Anything, req.Id).
+		Return(reservation.State{
+			Price: 100,
+			PaymentInstallments: []reservation.PaymentInstallment{
+				{Amount: 40.3, Method: reservation.Pix, Status: reservation.Paid, PaidAt: &paidAt},
+			},
+		}, nil)
+
+	repository.
+		On("Save", mock.Anything, mock.MatchedBy(func(state reservation.State) bool {
+			return state.FinalPrice == 90 &&
+				state.AmountPaid == 40.3 &&
+				state.BalanceDue == 49.7 &&
+				state.PaymentInstallments[0].PaidAt.Equal(paidAt) &&
+				state.PaymentInstallments[1].Status == reservation.Scheduled &&
+				state.PaymentInstallments[1].PaidAt == nil
+		})).
+		Return(reservation.State{}, nil)
+
+	customerRepository := &infrastructure.MockCustomerRepository{}
+	customerRepository.
+		On("Save", mock.Anything, mock.Anything).
+		Return(customer.State{Id: uuid.New()}, nil)
+
+	handler := ChangeHandler{customerRepository: customerRepository, reservationRepository: repository}
 	_, err := handler.Handle(context.Background(), req)
+
+	assert.Nil(t, err)
+}
+
+func TestChangeReservationHandlerWhenRemovingPaidInstallment(t *testing.T) {
+	paidAt := time.Now().UTC()
+	req := ChangeRequest{
+		Id: uuid.New(),
+		PaymentInstallments: []reservation.PaymentInstallment{
+			{Amount: 100, Method: reservation.Cash},
+		},
+		Customer: reservation.Customer{
+			State: customer.State{
+				Name:   common.RandString(10),
+				Phones: []customer.Phone{{Number: "123456789"}},
+			},
+		},
+		Address: reservation.Address{
+			City:       common.RandString(10),
+			Street:     common.RandString(10),
+			Number:     common.RandString(10),
+			PostalCode: common.RandString(10),
+		},
+	}
+
+	repository := &infrastructure.MockReservationRepository{}
+	repository.
+		On("GetById", mock.Anything, req.Id).
+		Return(reservation.State{
+			Price: 100,
+			PaymentInstallments: []reservation.PaymentInstallment{
+				{Amount: 50, Method: reservation.Cash},
+				{Amount: 50, Method: reservation.Pix, Status: reservation.Paid, PaidAt: &paidAt},
+			},
+		}, nil)
+
+	handler := ChangeHandler{reservationRepository: repository}
+	_, err := handler.Handle(context.Background(), req)
+
+	assert.Equal(t, infrastructure.ErrReservationInstallmentAlreadyPaid, err)
+	repository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestChangeReservationHandlerWhenErrToSaveRemovesCreatedCustomer(t *testing.T) {
+	req := ChangeRequest{
+		Id: uuid.New(),
+		Customer: reservation.Customer{
+			State: customer.State{
+				Name:   common.RandString(10),
+				Phones: []customer.Phone{{Number: "123456789"}},
+			},
+		},
+		Address: reservation.Address{
+			City:       common.RandString(10),
+			Street:     common.RandString(10),
+			Number:     common.RandString(10),
+			PostalCode: common.RandString(10),
+		},
+	}
+
+	repository := &infrastructure.MockReservationRepository{}
+	repository.
+		On("GetById", mock.Anything, req.Id).
+		Return(reservation.State{Id: req.Id}, nil)
+	repository.
+		On("Save", mock.Anything, mock.Anything).
+		Return(reservation.State{}, infrastructure.ErrReservationConcurrencyIssue)
+
+	created := customer.State{Id: uuid.New()}
+	customerRepository := &infrastructure.MockCustomerRepository{}
+	customerRepository.
+		On("Save", mock.Anything, mock.Anything).
+		Return(created, nil)
+	customerRepository.
+		On("Delete", mock.Anything, created.Id).
+		Return(nil)
+
+	handler := ChangeHandler{customerRepository: customerRepository, reservationRepository: repository}
+	_, err := handler.Handle(context.Background(), req)
+
+	assert.Equal(t, infrastructure.ErrReservationConcurrencyIssue, err)
+	customerRepository.AssertCalled(t, "Delete", mock.Anything, created.Id)
+}
+
+func TestChangeReservationHandlerWhenCustomerIsAlreadyLinked(t *testing.T) {
+	req := ChangeRequest{
+		Id: uuid.New(),
+		Customer: reservation.Customer{
+			State: customer.State{
+				Name:   common.RandString(10),
+				Phones: []customer.Phone{{Number: "123456789"}},
+			},
+		},
+		Address: reservation.Address{
+			City:       common.RandString(10),
+			Street:     common.RandString(10),
+			Number:     common.RandString(10),
+			PostalCode: common.RandString(10),
+		},
+	}
+
+	linked := customer.State{Id: uuid.New(), Name: req.Customer.Name}
+	repository := &infrastructure.MockReservationRepository{}
+	repository.
+		On("GetById", mock.Anything, req.Id).
+		Return(reservation.State{Id: req.Id, CustomerId: linked.Id}, nil)
+	repository.
+		On("Save", mock.Anything, mock.MatchedBy(func(state reservation.State) bool {
+			return state.CustomerId == linked.Id
+		})).
+		Return(reservation.State{}, nil)
+
+	customerRepository := &infrastructure.MockCustomerRepository{}
+	customerRepository.
+		On("GetById", mock.Anything, linked.Id).
+		Return(linked, nil)
+
+	handler := ChangeHandler{customerRepository: customerRepository, reservationRepository: repository}
+	_, err := handler.Handle(context.Background(), req)
+
+	assert.Nil(t, err)
+	repository.AssertExpectations(t)
+	customerRepository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestChangeReservationHandlerWhenCustomerNotFound(t *testing.T) {
+	req := ChangeRequest{
+		Id:         uuid.New(),
+		CustomerId: uuid.New(),
+		Address: reservation.Address{
+			City:       common.RandString(10),
+			Street:     common.RandString(10),
+			Number:     common.RandString(10),
+			PostalCode: common.RandString(10),
+		},
+	}
+
+	repository := &infrastructure.MockReservationRepository{}
+	repository.
+		On("GetById", mock.Anything, req.Id).
+		Return(reservation.State{}, nil)
+
+	customerRepository := &infrastructure.MockCustomerRepository{}
+	customerRepository.
+		On("GetById", mock.Anything, req.CustomerId).
+		Return(customer.State{}, infrastructure.ErrCustomerNotFound)
+
+	handler := ChangeHandler{customerRepository: customerRepository, reservationRepository: repository}
+	_, err := handler.Handle(context.Background(), req)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, infrastructure.ErrCustomerNotFound, err)
+}
+
+func TestChangeReservationHandlerWithCustomerId(t *testing.T) {
+	stored := customer.State{
+		Id:     uuid.New(),
+		Name:   common.RandString(10),
+		Phones: []customer.Phone{{Number: "123456789"}},
+	}
+
+	req := ChangeRequest{
+		Id:         uuid.New(),
+		CustomerId: stored.Id,
+		Address: reservation.Address{
+			City:       common.RandString(10),
+			Street:     common.RandString(10),
+			Number:     common.RandString(10),
+			PostalCode: common.RandString(10),
+		},
+	}
+
+	repository := &infrastructure.MockReservationRepository{}
+	repository.
+		On("GetById", mock.Anything, req.Id).
+		Return(reservation.State{}, nil)
+
+	repository.
+		On("Save", mock.Anything, mock.MatchedBy(func(state reservation.State) bool {
+			return state.CustomerId == stored.Id && state.Customer.Name == stored.Name
+		})).
+		Return(reservation.State{}, nil)
+
+	customerRepository := &infrastructure.MockCustomerRepository{}
+	customerRepository.
+		On("GetById", mock.Anything, stored.Id).
+		Return(stored, nil)
+
+	handler := ChangeHandler{customerRepository: customerRepository, reservationRepository: repository}
+	_, err := handler.Handle(context.Background(), req)
+
+	assert.Nil(t, err)
+	customerRepository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func newScheduledChangeRequest(delivery, pickUp time.Time) ChangeRequest {
+	return ChangeRequest{
+		Id:       uuid.New(),
+		Delivery: reservation.DeliveryOrPickUp{At: delivery},
+		PickUp:   reservation.DeliveryOrPickUp{At: pickUp},
+		Customer: reservation.Customer{
+			State: customer.State{
+				Name:   common.RandString(10),
+				Phones: []customer.Phone{{Number: "123456789"}},
+			},
+		},
+		Address: reservation.Address{
+			City:       common.RandString(10),
+			Street:     common.RandString(10),
+			Number:     common.RandString(10),
+			PostalCode: common.RandString(10),
+		},
+	}
+}
+
+func TestChangeReservationHandlerWhenScheduleIsInvalid(t *testing.T) {
+	at := time.Now().UTC().Add(72 * time.Hour)
+	tests := []struct {
+		current  reservation.State
+		req      ChangeRequest
+		options  infrastructure.ReservationOptions
+		expected error
+	}{
+		{
+			req:      newScheduledChangeRequest(at, at.Add(-time.Hour)),
+			expected: infrastructure.ErrReservationScheduleIsInvalid,
+		},
+		{
+			req:      newScheduledChangeRequest(at, time.Time{}),
+			expected: infrastructure.ErrReservationScheduleIsInvalid,
+		},
+		{
+			current: reservation.State{
+				Status:   reservation.Confirmed,
+				Delivery: reservation.DeliveryOrPickUp{At: at},
+				PickUp:   reservation.DeliveryOrPickUp{At: at.Add(time.Hour)},
+			},
+			req:      newScheduledChangeRequest(time.Time{}, time.Time{}),
+			expected: infrastructure.ErrReservationScheduleIsInvalid,
+		},
+		{
+			req:      newScheduledChangeRequest(time.Now().UTC().Add(-time.Hour), at),
+			expected: infrastructure.ErrReservationLeadTimeIsTooShort,
+		},
+		{
+			req:      newScheduledChangeRequest(at, at.Add(time.Hour)),
+			options:  infrastructure.ReservationOptions{MinimumLeadTime: 96 * time.Hour},
+			expected: infrastructure.ErrReservationLeadTimeIsTooShort,
+		},
+		{
+			req:      newScheduledChangeRequest(at.Truncate(24*time.Hour).Add(3*time.Hour), at.Truncate(24*time.Hour).Add(12*time.Hour)),
+			options:  infrastructure.ReservationOptions{OpensAt: 8 * time.Hour, ClosesAt: 23 * time.Hour},
+			expected: infrastructure.ErrReservationOutsideBusinessHours,
+		},
+	}
+
+	for _, test := range tests {
+		repository := &infrastructure.MockReservationRepository{}
+		repository.
+			On("GetById", mock.Anything, test.req.Id).
+			Return(test.current, nil)
+
+		handler := ChangeHandler{reservationOptions: test.options, reservationRepository: repository}
+		_, err := handler.Handle(context.Background(), test.req)
+
+		assert.Equal(t, test.expected, err)
+	}
+}
+
+func TestChangeReservationHandlerWhenDeliveryIsUnchanged(t *testing.T) {
+	// Reservations already in the past can still be edited, for example to register a payment
+	delivery := time.Now().UTC().Add(-48 * time.Hour).Truncate(time.Hour)
+	pickUp := delivery.Add(4 * time.Hour)
+	req := newScheduledChangeRequest(delivery, pickUp)
+
+	repository := &infrastructure.MockReservationRepository{}
+	repository.
+		On("GetById", mock.Anything, req.Id).
+		Return(reservation.State{
+			Delivery: reservation.DeliveryOrPickUp{At: delivery},
+			PickUp:   reservation.DeliveryOrPickUp{At: pickUp},
+		}, nil)
+	repository.
+		On("Save", mock.Anything, mock.Anything).
+		Return(reservation.State{}, nil)
+
+	customerRepository := &infrastructure.MockCustomerRepository{}
+	customerRepository.
+		On("Save", mock.Anything, mock.Anything).
+		Return(customer.State{Id: uuid.New()}, nil)
+
+	handler := ChangeHandler{
+		reservationOptions: infrastructure.ReservationOptions{
+			OpensAt:         23 * time.Hour,
+			ClosesAt:        23 * time.Hour,
+			MinimumLeadTime: 24 * time.Hour,
+		},
+		customerRepository:    customerRepository,
+		reservationRepository: repository,
+	}
+	_, err := handler.Handle(context.Background(), req)
+
 	assert.Nil(t, err)
 }
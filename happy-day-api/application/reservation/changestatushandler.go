@@ -0,0 +1,98 @@
+package reservation
+
+import (
+	"context"
+	"time"
+
+	"happy_day/application/customer"
+	"happy_day/domain/reservation"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+)
+
+var transitions = map[reservation.Status][]reservation.Status{
+	reservation.Draft:     {reservation.Confirmed, reservation.Cancelled},
+	reservation.Confirmed: {reservation.Delivered, reservation.Cancelled},
+	reservation.Delivered: {reservation.PickedUp},
+	reservation.PickedUp:  {reservation.Closed},
+}
+
+type (
+	ChangeStatusRequest struct {
+		Id     uuid.UUID          `json:"id"`
+		Status reservation.Status `json:"status"`
+	}
+
+	ChangeStatusHandler struct {
+		repository infrastructure.ReservationRepository
+	}
+)
+
+func (handler ChangeStatusHandler) Handle(ctx context.Context, req ChangeStatusRequest) (reservation.State, error) {
+	state, err := handler.repository.GetById(ctx, req.Id)
+	if err != nil {
+		return reservation.State{}, err
+	}
+
+	if !canTransition(currentStatus(state), req.Status) {
+		return reservation.State{}, infrastructure.ErrReservationInvalidStatusTransition
+	}
+
+	if req.Status == reservation.Confirmed {
+		err = validateConfirmation(state)
+		if err != nil {
+			return reservation.State{}, err
+		}
+	}
+
+	changeStatus(&state, req.Status)
+	return handler.repository.Save(ctx, state)
+}
+
+func currentStatus(state reservation.State) reservation.Status {
+	if len(state.Status) == 0 {
+		return reservation.Draft
+	}
+
+	return state.Status
+}
+
+// validateConfirmation checks what a draft may still be missing, the schedule rules were applied when it was changed
+func validateConfirmation(state reservation.State) error {
+	if state.CustomerId == uuid.Nil {
+		err := customer.Validate(state.Customer.State)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := validateAddress(state.Address)
+	if err != nil {
+		return err
+	}
+
+	if state.Delivery.At.IsZero() || state.PickUp.At.IsZero() || !state.PickUp.At.After(state.Delivery.At) {
+		return infrastructure.ErrReservationScheduleIsInvalid
+	}
+
+	return nil
+}
+
+func canTransition(from, to reservation.Status) bool {
+	for _, status := range transitions[from] {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+func changeStatus(state *reservation.State, status reservation.Status) {
+	state.Status = status
+	state.StatusHistory = append(state.StatusHistory, reservation.StatusChange{
+		Status: status,
+		At:     time.Now().UTC(),
+	})
+}
@@ -0,0 +1,161 @@
+package reservation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"happy_day/common"
+	"happy_day/domain/reservation"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestChangeReservationStatusWhenErrToGetById(t *testing.T) {
+	expectedErr := errors.New(common.RandString(10))
+	repository := &infrastructure.MockReservationRepository{}
+	repository.
+		On("GetById", mock.Anything, mock.Anything).
+		Return(reservation.State{}, expectedErr)
+
+	handler := ChangeStatusHandler{repository: repository}
+	_, err := handler.Handle(context.Background(), ChangeStatusRequest{
+		Id:     uuid.New(),
+		Status: reservation.Confirmed,
+	})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, expectedErr, err)
+}
+
+func TestChangeReservationStatusWhenTransitionIsInvalid(t *testing.T) {
+	type param struct {
+		from reservation.Status
+		to   reservation.Status
+	}
+
+	cases := []param{
+		{from: reservation.Draft, to: reservation.Delivered},
+		{from: "", to: reservation.Closed},
+		{from: reservation.Confirmed, to: reservation.Draft},
+		{from: reservation.Delivered, to: reservation.Cancelled},
+		{from: reservation.Closed, to: reservation.Cancelled},
+		{from: reservation.Cancelled, to: reservation.Confirmed},
+	}
+
+	for _, c := range cases {
+		t.Run(string(c.from)+" to "+string(c.to), func(k *testing.T) {
+			repository := &infrastructure.MockReservationRepository{}
+			repository.
+				On("GetById", mock.Anything, mock.Anything).
+				Return(reservation.State{Status: c.from}, nil)
+
+			handler := ChangeStatusHandler{repository: repository}
+			_, err := handler.Handle(context.Background(), ChangeStatusRequest{
+				Id:     uuid.New(),
+				Status: c.to,
+			})
+
+			assert.NotNil(k, err)
+			assert.Equal(k, infrastructure.ErrReservationInvalidStatusTransition, err)
+		})
+	}
+}
+
+func TestChangeReservationStatus(t *testing.T) {
+	type param struct {
+		from reservation.Status
+		to   reservation.Status
+	}
+
+	cases := []param{
+		{from: "", to: reservation.Confirmed},
+		{from: reservation.Draft, to: reservation.Cancelled},
+		{from: reservation.Confirmed, to: reservation.Delivered},
+		{from: reservation.Confirmed, to: reservation.Cancelled},
+		{from: reservation.Delivered, to: reservation.PickedUp},
+		{from: reservation.PickedUp, to: reservation.Closed},
+	}
+
+	for _, c := range cases {
+		t.Run(string(c.from)+" to "+string(c.to), func(k *testing.T) {
+			repository := &infrastructure.MockReservationRepository{}
+			repository.
+				On("GetById", mock.Anything, mock.Anything).
+				Return(newConfirmableState(c.from), nil)
+
+			repository.
+				On("Save", mock.Anything, mock.MatchedBy(func(state reservation.State) bool {
+					return state.Status == c.to &&
+						len(state.StatusHistory) == 1 &&
+						state.StatusHistory[0].Status == c.to
+				})).
+				Return(reservation.State{Status: c.to}, nil)
+
+			handler := ChangeStatusHandler{repository: repository}
+			res, err := handler.Handle(context.Background(), ChangeStatusRequest{
+				Id:     uuid.New(),
+				Status: c.to,
+			})
+
+			assert.Nil(k, err)
+			assert.Equal(k, c.to, res.Status)
+		})
+	}
+}
+
+func TestChangeReservationStatusWhenConfirmingIncompleteDraft(t *testing.T) {
+	withoutCustomer := newConfirmableState(reservation.Draft)
+	withoutCustomer.CustomerId = uuid.Nil
+
+	withoutAddress := newConfirmableState(reservation.Draft)
+	withoutAddress.Address.Street = ""
+
+	withoutSchedule := newConfirmableState(reservation.Draft)
+	withoutSchedule.PickUp.At = time.Time{}
+
+	tests := []struct {
+		state    reservation.State
+		expected error
+	}{
+		{state: withoutCustomer, expected: infrastructure.ErrCustomerNameIsEmpty},
+		{state: withoutAddress, expected: infrastructure.ErrReservationAddressStreetIsEmpty},
+		{state: withoutSchedule, expected: infrastructure.ErrReservationScheduleIsInvalid},
+	}
+
+	for _, test := range tests {
+		repository := &infrastructure.MockReservationRepository{}
+		repository.
+			On("GetById", mock.Anything, mock.Anything).
+			Return(test.state, nil)
+
+		handler := ChangeStatusHandler{repository: repository}
+		_, err := handler.Handle(context.Background(), ChangeStatusRequest{
+			Id:     uuid.New(),
+			Status: reservation.Confirmed,
+		})
+
+		assert.Equal(t, test.expected, err)
+		repository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	}
+}
+
+func newConfirmableState(status reservation.Status) reservation.State {
+	at := time.Now().UTC().Add(72 * time.Hour)
+	return reservation.State{
+		Status:     status,
+		CustomerId: uuid.New(),
+		Address: reservation.Address{
+			City:       common.RandString(10),
+			Street:     common.RandString(10),
+			Number:     common.RandString(10),
+			PostalCode: common.RandString(10),
+		},
+		Delivery: reservation.DeliveryOrPickUp{At: at},
+		PickUp:   reservation.DeliveryOrPickUp{At: at.Add(4 * time.Hour)},
+	}
+}
@@ -12,10 +12,21 @@ import (
 )
 
 type (
+	// CreateRequest must be complete unless Draft is set, a draft is completed later by the change
 	CreateRequest struct {
-		Products []CreateProductRequest `json:"products"`
-		Price    float64                `json:"price"`
-		Discount float64                `json:"discount"`
+		Draft               bool                             `json:"draft,omitempty"`
+		Products            []CreateProductRequest           `json:"products"`
+		Strategy            QuoteStrategy                    `json:"strategy,omitempty"`
+		Price               *float64                         `json:"price,omitempty"`
+		Discount            float64                          `json:"discount"`
+		DiscountPercentage  float64                          `json:"discountPercentage,omitempty"`
+		Delivery            reservation.DeliveryOrPickUp     `json:"delivery"`
+		PickUp              reservation.DeliveryOrPickUp     `json:"pickUp"`
+		PaymentInstallments []reservation.PaymentInstallment `json:"paymentInstallments,omitempty"`
+		Comment             string                           `json:"comment,omitempty"`
+		CustomerId          uuid.UUID                        `json:"customerId,omitempty"`
+		Customer            reservation.Customer             `json:"customer"`
+		Address             reservation.Address              `json:"address"`
 	}
 
 	CreateProductRequest struct {
@@ -24,8 +35,12 @@ type (
 	}
 
 	CreateHandler struct {
+		pricingOptions        infrastructure.PricingOptions
+		reservationOptions    infrastructure.ReservationOptions
+		customerRepository    infrastructure.CustomerRepository
 		productRepository     infrastructure.ProductRepository
 		reservationRepository infrastructure.ReservationRepository
+		staffRepository       infrastructure.StaffRepository
 	}
 )
 
@@ -35,31 +50,116 @@ func (handler CreateHandler) Handle(ctx context.Context, req CreateRequest) (res
 		return state, infrastructure.ErrProductListIsEmpty
 	}
 
-	products, err := handler.productRepository.GetByProducts(ctx, common.Map(req.Products, func(item CreateProductRequest) uuid.UUID {
-		return item.Id
-	}))
+	for _, item := range req.Products {
+		err := validateQuantity(item.Quantity)
+		if err != nil {
+			return state, err
+		}
+	}
+
+	if len(req.Strategy) == 0 {
+		req.Strategy = LowestPrice
+	}
+
+	if !req.Strategy.IsValid() {
+		return state, infrastructure.ErrQuoteStrategyIsInvalid
+	}
 
+	if !req.Draft {
+		err := validateSchedule(handler.reservationOptions, state, req.Delivery, req.PickUp)
+		if err != nil {
+			return state, err
+		}
+
+		err = validateDetails(req.PaymentInstallments, req.CustomerId, req.Customer, req.Address)
+		if err != nil {
+			return state, err
+		}
+	}
+
+	quote, products, err := quoteProducts(ctx, handler.productRepository, req.Strategy,
+		common.Map(req.Products, func(item CreateProductRequest) QuoteProductRequest {
+			return QuoteProductRequest{Id: item.Id, Quantity: item.Quantity}
+		}))
+	if err != nil {
+		return state, err
+	}
+
+	// The price is always the quote, a price sent by the client is only checked against it
+	if req.Price != nil && roundPrice(*req.Price) != roundPrice(quote.Price) {
+		return state, infrastructure.ErrReservationPriceMismatch
+	}
+
+	installments, err := mergeInstallments(nil, req.PaymentInstallments)
 	if err != nil {
 		return state, err
 	}
 
 	state = reservation.State{
-		Discount:   req.Discount,
-		Price:      req.Price,
-		FinalPrice: req.Price - req.Discount,
+		Price:               roundPrice(quote.Price),
+		Delivery:            req.Delivery,
+		PickUp:              req.PickUp,
+		PaymentInstallments: installments,
+		Comment:             req.Comment,
+		Address:             req.Address,
 		Products: common.Map(products, func(item product.State) reservation.Product {
-			p, _ := common.First(req.Products,
-				func(item2 CreateProductRequest) bool {
-					return item2.Id == item.Id
-				},
-				CreateProductRequest{})
+			var quantity int64
+			for _, requested := range req.Products {
+				if requested.Id == item.Id {
+					quantity += requested.Quantity
+				}
+			}
+
 			return reservation.Product{
 				Id:       item.Id,
 				Price:    item.Price,
-				Quantity: p.Quantity,
+				Quantity: quantity,
 			}
 		}),
 	}
 
-	return handler.reservationRepository.Save(ctx, state)
+	err = applyDiscount(handler.pricingOptions, &state, req.Discount, req.DiscountPercentage)
+	if err != nil {
+		return reservation.State{}, err
+	}
+
+	err = ensureCrew(ctx, handler.reservationOptions, handler.staffRepository, handler.reservationRepository,
+		reservation.State{}, &state)
+	if err != nil {
+		return reservation.State{}, err
+	}
+
+	// A complete creation already passed what the confirmation checks
+	status := reservation.Confirmed
+	if req.Draft {
+		status = reservation.Draft
+	}
+
+	changeStatus(&state, status)
+	return saveDetails(ctx, handler.customerRepository, handler.productRepository, handler.reservationRepository,
+		state, req.CustomerId, req.Customer)
+}
+
+// applyDiscount accepts either an amount or a percentage of the price, never more than the configured limit
+func applyDiscount(options infrastructure.PricingOptions, state *reservation.State, discount, percentage float64) error {
+	if discount < 0 || percentage < 0 || percentage > 100 || (discount > 0 && percentage > 0) {
+		return infrastructure.ErrReservationDiscountIsInvalid
+	}
+
+	if percentage > 0 {
+		discount = roundPrice(state.Price * percentage / 100)
+	}
+
+	if discount > state.Price {
+		return infrastructure.ErrReservationDiscountIsInvalid
+	}
+
+	maxDiscount := roundPrice(state.Price * options.MaxDiscountPercentage / 100)
+	if options.MaxDiscountPercentage > 0 && discount > maxDiscount {
+		return infrastructure.ErrReservationDiscountIsInvalid
+	}
+
+	state.Discount = discount
+	state.FinalPrice = roundPrice(state.Price - discount)
+	return nil
 }
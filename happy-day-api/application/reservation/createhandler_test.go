@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"happy_day/common"
+	"happy_day/domain/customer"
 	"happy_day/domain/product"
 	"happy_day/domain/reservation"
 	"happy_day/infrastructure"
@@ -23,15 +25,37 @@ func TestCreateReservationHandlerWhenProductListIsEmpty(t *testing.T) {
 	assert.Equal(t, infrastructure.ErrProductListIsEmpty, err)
 }
 
+func TestCreateReservationHandlerWhenQuantityIsInvalid(t *testing.T) {
+	quantities := []int64{-3, 0, maxQuoteQuantity + 1}
+	for _, quantity := range quantities {
+		productRepository := &infrastructure.MockProductRepository{}
+		handler := CreateHandler{productRepository: productRepository}
+		_, err := handler.Handle(context.Background(), CreateRequest{
+			Draft: true,
+			Products: []CreateProductRequest{
+				{Id: uuid.New(), Quantity: 2},
+				{Id: uuid.New(), Quantity: quantity},
+			},
+		})
+
+		assert.Equal(t, infrastructure.ErrProductAmountIsInvalid, err)
+		productRepository.AssertNotCalled(t, "GetComposed", mock.Anything, mock.Anything)
+	}
+}
+
 func TestCreateReservationHandlerWhenErrToGetByProducts(t *testing.T) {
 	expectedErr := errors.New(common.RandString(10))
 	productRepository := &infrastructure.MockProductRepository{}
+	productRepository.
+		On("GetComposed", mock.Anything, mock.Anything).
+		Return([]product.State{}, nil)
 	productRepository.
 		On("GetByProducts", mock.Anything, mock.Anything).
 		Return([]product.State{}, expectedErr)
 
 	handler := CreateHandler{productRepository: productRepository}
 	_, err := handler.Handle(context.Background(), CreateRequest{
+		Draft: true,
 		Products: []CreateProductRequest{
 			{
 				Id:       uuid.New(),
@@ -44,8 +68,9 @@ func TestCreateReservationHandlerWhenErrToGetByProducts(t *testing.T) {
 	assert.Equal(t, expectedErr, err)
 }
 
-func TestCreateReservationHandler(t *testing.T) {
+func TestCreateReservationHandlerWhenDraft(t *testing.T) {
 	req := CreateRequest{
+		Draft: true,
 		Products: []CreateProductRequest{
 			{
 				Id:       uuid.New(),
@@ -59,6 +84,9 @@ func TestCreateReservationHandler(t *testing.T) {
 	}
 
 	productRepository := &infrastructure.MockProductRepository{}
+	productRepository.
+		On("GetComposed", mock.Anything, mock.Anything).
+		Return([]product.State{}, nil)
 	productRepository.
 		On("GetByProducts", mock.Anything, mock.Anything).
 		Return(common.Map(req.Products, func(item CreateProductRequest) product.State {
@@ -70,7 +98,10 @@ func TestCreateReservationHandler(t *testing.T) {
 
 	reservationRepository := &infrastructure.MockReservationRepository{}
 	reservationRepository.
-		On("Save", mock.Anything, mock.Anything).
+		On("Save", mock.Anything, mock.MatchedBy(func(state reservation.State) bool {
+			return state.Status == reservation.Draft && len(state.StatusHistory) == 1 &&
+				state.Price == 25.5 && state.FinalPrice == 25.5
+		})).
 		Return(reservation.State{}, nil)
 
 	handler := CreateHandler{
@@ -81,3 +112,200 @@ func TestCreateReservationHandler(t *testing.T) {
 
 	assert.Nil(t, err)
 }
+
+func newCreateReservationHandler(options infrastructure.PricingOptions, products ...product.State) (CreateHandler, *infrastructure.MockReservationRepository) {
+	productRepository := &infrastructure.MockProductRepository{}
+	productRepository.
+		On("GetComposed", mock.Anything, mock.Anything).
+		Return([]product.State{}, nil)
+	productRepository.
+		On("GetByProducts", mock.Anything, mock.Anything).
+		Return(products, nil)
+
+	reservationRepository := &infrastructure.MockReservationRepository{}
+	return CreateHandler{
+		pricingOptions:        options,
+		productRepository:     productRepository,
+		reservationRepository: reservationRepository,
+	}, reservationRepository
+}
+
+func TestCreateReservationHandlerWhenStrategyIsInvalid(t *testing.T) {
+	handler := CreateHandler{}
+	_, err := handler.Handle(context.Background(), CreateRequest{
+		Products: []CreateProductRequest{{Id: uuid.New(), Quantity: 1}},
+		Strategy: QuoteStrategy(common.RandString(10)),
+	})
+
+	assert.Equal(t, infrastructure.ErrQuoteStrategyIsInvalid, err)
+}
+
+func TestCreateReservationHandlerWhenPriceDoesNotMatchQuote(t *testing.T) {
+	id := uuid.New()
+	handler, _ := newCreateReservationHandler(infrastructure.PricingOptions{}, product.State{Id: id, Price: 10})
+
+	price := 5.0
+	_, err := handler.Handle(context.Background(), CreateRequest{
+		Draft:    true,
+		Products: []CreateProductRequest{{Id: id, Quantity: 2}},
+		Price:    &price,
+	})
+
+	assert.Equal(t, infrastructure.ErrReservationPriceMismatch, err)
+}
+
+func TestCreateReservationHandlerWhenDiscountIsInvalid(t *testing.T) {
+	id := uuid.New()
+	requests := []CreateRequest{
+		{Discount: -1},
+		{DiscountPercentage: 101},
+		{Discount: 1, DiscountPercentage: 1},
+		{Discount: 21},
+		{DiscountPercentage: 30},
+	}
+
+	for _, req := range requests {
+		handler, _ := newCreateReservationHandler(infrastructure.PricingOptions{MaxDiscountPercentage: 20}, product.State{Id: id, Price: 50})
+		req.Draft = true
+		req.Products = []CreateProductRequest{{Id: id, Quantity: 2}}
+		_, err := handler.Handle(context.Background(), req)
+		assert.Equal(t, infrastructure.ErrReservationDiscountIsInvalid, err)
+	}
+}
+
+func TestCreateReservationHandlerWhenDiscountIsPercentage(t *testing.T) {
+	id := uuid.New()
+	handler, reservationRepository := newCreateReservationHandler(infrastructure.PricingOptions{MaxDiscountPercentage: 20}, product.State{Id: id, Price: 50})
+	reservationRepository.
+		On("Save", mock.Anything, mock.MatchedBy(func(state reservation.State) bool {
+			return state.Price == 100 && state.Discount == 15 && state.FinalPrice == 85 &&
+				state.BalanceDue == 85 && state.Products[0].Quantity == 2
+		})).
+		Return(reservation.State{}, nil)
+
+	price := 100.0
+	_, err := handler.Handle(context.Background(), CreateRequest{
+		Draft:              true,
+		Products:           []CreateProductRequest{{Id: id, Quantity: 1}, {Id: id, Quantity: 1}},
+		Price:              &price,
+		DiscountPercentage: 15,
+	})
+
+	assert.Nil(t, err)
+	reservationRepository.AssertExpectations(t)
+}
+
+func newCompleteCreateRequest(productId uuid.UUID) CreateRequest {
+	at := time.Now().UTC().Add(48 * time.Hour)
+	return CreateRequest{
+		Products: []CreateProductRequest{{Id: productId, Quantity: 1}},
+		Delivery: reservation.DeliveryOrPickUp{At: at},
+		PickUp:   reservation.DeliveryOrPickUp{At: at.Add(4 * time.Hour)},
+		Customer: reservation.Customer{State: customer.State{
+			Name:   common.RandString(10),
+			Phones: []customer.Phone{{Number: "123456789"}},
+		}},
+		Address: reservation.Address{
+			City:       common.RandString(10),
+			Street:     common.RandString(10),
+			Number:     common.RandString(10),
+			PostalCode: common.RandString(10),
+		},
+	}
+}
+
+func TestCreateReservationHandlerWhenScheduleIsInvalid(t *testing.T) {
+	requests := []CreateRequest{newCompleteCreateRequest(uuid.New()), newCompleteCreateRequest(uuid.New())}
+	requests[0].PickUp = reservation.DeliveryOrPickUp{}
+	requests[1].PickUp = requests[1].Delivery
+
+	for _, req := range requests {
+		handler := CreateHandler{}
+		_, err := handler.Handle(context.Background(), req)
+		assert.Equal(t, infrastructure.ErrReservationScheduleIsInvalid, err)
+	}
+}
+
+func TestCreateReservationHandlerWhenCustomerIsInvalid(t *testing.T) {
+	req := newCompleteCreateRequest(uuid.New())
+	req.Customer.Name = ""
+
+	handler := CreateHandler{}
+	_, err := handler.Handle(context.Background(), req)
+
+	assert.Equal(t, infrastructure.ErrCustomerNameIsEmpty, err)
+}
+
+func TestCreateReservationHandlerWhenAddressCityIsEmpty(t *testing.T) {
+	req := newCompleteCreateRequest(uuid.New())
+	req.Address.City = ""
+
+	handler := CreateHandler{}
+	_, err := handler.Handle(context.Background(), req)
+
+	assert.Equal(t, infrastructure.ErrReservationAddressCityIsEmpty, err)
+}
+
+func TestCreateReservationHandler(t *testing.T) {
+	id := uuid.New()
+	req := newCompleteCreateRequest(id)
+
+	handler, reservationRepository := newCreateReservationHandler(infrastructure.PricingOptions{}, product.State{Id: id, Price: 50})
+	reservationRepository.
+		On("GetByPeriod", mock.Anything, req.Delivery.At, req.PickUp.At).
+		Return([]reservation.State{}, nil)
+	reservationRepository.
+		On("Save", mock.Anything, mock.MatchedBy(func(state reservation.State) bool {
+			return state.Price == 50 && state.Status == reservation.Confirmed &&
+				len(state.StatusHistory) == 1 && state.CustomerId != uuid.Nil && state.Customer.Name == req.Customer.Name &&
+				state.Address == req.Address && state.Delivery.At.Equal(req.Delivery.At)
+		})).
+		Return(reservation.State{}, nil)
+
+	customerRepository := &infrastructure.MockCustomerRepository{}
+	customerRepository.
+		On("Save", mock.Anything, mock.Anything).
+		Return(customer.State{Id: uuid.New(), Name: req.Customer.Name}, nil)
+	handler.customerRepository = customerRepository
+
+	_, err := handler.Handle(context.Background(), req)
+
+	assert.Nil(t, err)
+	reservationRepository.AssertExpectations(t)
+}
+
+func TestCreateReservationHandlerWithCustomerId(t *testing.T) {
+	id := uuid.New()
+	stored := customer.State{Id: uuid.New(), Name: common.RandString(10)}
+	req := newCompleteCreateRequest(id)
+	req.CustomerId = stored.Id
+	req.Customer = reservation.Customer{}
+
+	handler, reservationRepository := newCreateReservationHandler(infrastructure.PricingOptions{}, product.State{Id: id, Price: 50})
+	reservationRepository.
+		On("GetByPeriod", mock.Anything, mock.Anything, mock.Anything).
+		Return([]reservation.State{}, nil)
+	reservationRepository.
+		On("Save", mock.Anything, mock.MatchedBy(func(state reservation.State) bool {
+			return state.CustomerId == stored.Id && state.Customer.Name == stored.Name
+		})).
+		Return(reservation.State{}, nil)
+
+	customerRepository := &infrastructure.MockCustomerRepository{}
+	customerRepository.
+		On("GetById", mock.Anything, stored.Id).
+		Return(stored, nil)
+	handler.customerRepository = customerRepository
+
+	_, err := handler.Handle(context.Background(), req)
+
+	assert.Nil(t, err)
+	customerRepository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestCreateReservationHandlerWhenLeadTimeIsTooShort(t *testing.T) {
+	handler := CreateHandler{reservationOptions: infrastructure.ReservationOptions{MinimumLeadTime: 72 * time.Hour}}
+	_, err := handler.Handle(context.Background(), newCompleteCreateRequest(uuid.New()))
+
+	assert.Equal(t, infrastructure.ErrReservationLeadTimeIsTooShort, err)
+}
@@ -0,0 +1,96 @@
+package reservation
+
+import (
+	"context"
+	"time"
+
+	"happy_day/common"
+	"happy_day/domain/reservation"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+)
+
+// ensureCrew checks the staff newly assigned to the delivery or to the pick up, they must be active and free at that time
+func ensureCrew(
+	ctx context.Context,
+	options infrastructure.ReservationOptions,
+	staffRepository infrastructure.StaffRepository,
+	reservationRepository infrastructure.ReservationRepository,
+	current reservation.State,
+	state *reservation.State) error {
+	state.Delivery.By = common.Distinct(state.Delivery.By)
+	state.PickUp.By = common.Distinct(state.PickUp.By)
+
+	ids := common.Distinct(append(append([]uuid.UUID{}, state.Delivery.By...), state.PickUp.By...))
+	if len(ids) == 0 {
+		return nil
+	}
+
+	members, err := staffRepository.GetByIds(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	active := map[uuid.UUID]bool{}
+	for _, member := range members {
+		active[member.Id] = member.Active
+	}
+
+	assignments := [][2]reservation.DeliveryOrPickUp{
+		{current.Delivery, state.Delivery},
+		{current.PickUp, state.PickUp},
+	}
+
+	for _, assignment := range assignments {
+		previous, changed := assignment[0], assignment[1]
+		for _, id := range changed.By {
+			if changed.At.Equal(previous.At) && common.Contains(previous.By, id) {
+				continue
+			}
+
+			if !active[id] {
+				return infrastructure.ErrStaffIsInactive
+			}
+
+			if changed.At.IsZero() {
+				continue
+			}
+
+			busy, err := isStaffBusy(ctx, options, reservationRepository, state.Id, id, changed.At)
+			if err != nil {
+				return err
+			}
+
+			if busy {
+				return infrastructure.ErrStaffScheduleConflict
+			}
+		}
+	}
+
+	return nil
+}
+
+// isStaffBusy looks for a delivery or a pick up of another reservation closer than the assignment duration
+func isStaffBusy(
+	ctx context.Context,
+	options infrastructure.ReservationOptions,
+	repository infrastructure.ReservationRepository,
+	reservationId, staffId uuid.UUID,
+	at time.Time) (bool, error) {
+	reservations, err := repository.GetByStaff(ctx, staffId, at.Add(-options.AssignmentDuration), at.Add(options.AssignmentDuration))
+	if err != nil {
+		return false, err
+	}
+
+	return common.Any(reservations, func(item reservation.State) bool {
+		return item.Id != reservationId &&
+			(overlaps(item.Delivery, staffId, at, options.AssignmentDuration) ||
+				overlaps(item.PickUp, staffId, at, options.AssignmentDuration))
+	}), nil
+}
+
+func overlaps(assignment reservation.DeliveryOrPickUp, staffId uuid.UUID, at time.Time, duration time.Duration) bool {
+	gap := assignment.At.Sub(at)
+	return common.Contains(assignment.By, staffId) && gap < duration && gap > -duration
+}
@@ -0,0 +1,107 @@
+package reservation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"happy_day/domain/reservation"
+	"happy_day/domain/staff"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newCrewRepositories(members ...staff.State) (*infrastructure.MockStaffRepository, *infrastructure.MockReservationRepository) {
+	staffRepository := &infrastructure.MockStaffRepository{}
+	staffRepository.
+		On("GetByIds", mock.Anything, mock.Anything).
+		Return(members, nil)
+
+	return staffRepository, &infrastructure.MockReservationRepository{}
+}
+
+func TestEnsureCrewWhenStaffIsInactive(t *testing.T) {
+	member := staff.State{Id: uuid.New()}
+	staffRepository, reservationRepository := newCrewRepositories(member)
+
+	state := reservation.State{Delivery: reservation.DeliveryOrPickUp{At: time.Now(), By: []uuid.UUID{member.Id}}}
+	err := ensureCrew(context.Background(), infrastructure.ReservationOptions{AssignmentDuration: time.Hour},
+		staffRepository, reservationRepository, reservation.State{}, &state)
+
+	assert.Equal(t, infrastructure.ErrStaffIsInactive, err)
+}
+
+func TestEnsureCrewWhenStaffIsAssignedToAnotherDeliveryAtTheSameTime(t *testing.T) {
+	member := staff.State{Id: uuid.New(), Active: true}
+	staffRepository, reservationRepository := newCrewRepositories(member)
+
+	at := time.Date(2030, 1, 10, 10, 0, 0, 0, time.UTC)
+	reservationRepository.
+		On("GetByStaff", mock.Anything, member.Id, at.Add(-2*time.Hour), at.Add(2*time.Hour)).
+		Return([]reservation.State{{
+			Id:       uuid.New(),
+			Delivery: reservation.DeliveryOrPickUp{At: at.Add(90 * time.Minute), By: []uuid.UUID{member.Id}},
+		}}, nil)
+
+	state := reservation.State{Delivery: reservation.DeliveryOrPickUp{At: at, By: []uuid.UUID{member.Id, member.Id}}}
+	err := ensureCrew(context.Background(), infrastructure.ReservationOptions{AssignmentDuration: 2 * time.Hour},
+		staffRepository, reservationRepository, reservation.State{}, &state)
+
+	assert.Equal(t, infrastructure.ErrStaffScheduleConflict, err)
+	assert.Equal(t, []uuid.UUID{member.Id}, state.Delivery.By)
+}
+
+func TestEnsureCrewWhenOtherAssignmentEndsBefore(t *testing.T) {
+	member := staff.State{Id: uuid.New(), Active: true}
+	staffRepository, reservationRepository := newCrewRepositories(member)
+
+	at := time.Date(2030, 1, 10, 10, 0, 0, 0, time.UTC)
+	state := reservation.State{
+		Id:     uuid.New(),
+		PickUp: reservation.DeliveryOrPickUp{At: at, By: []uuid.UUID{member.Id}},
+	}
+
+	reservationRepository.
+		On("GetByStaff", mock.Anything, member.Id, mock.Anything, mock.Anything).
+		Return([]reservation.State{
+			{Id: uuid.New(), PickUp: reservation.DeliveryOrPickUp{At: at.Add(-time.Hour), By: []uuid.UUID{member.Id}}},
+			{Id: uuid.New(), Delivery: reservation.DeliveryOrPickUp{At: at, By: []uuid.UUID{uuid.New()}}},
+			{Id: state.Id, PickUp: reservation.DeliveryOrPickUp{At: at, By: []uuid.UUID{member.Id}}},
+		}, nil)
+
+	err := ensureCrew(context.Background(), infrastructure.ReservationOptions{AssignmentDuration: time.Hour},
+		staffRepository, reservationRepository, reservation.State{}, &state)
+
+	assert.Nil(t, err)
+}
+
+func TestEnsureCrewWhenAssignmentIsUnchanged(t *testing.T) {
+	// Deactivating a staff keeps the assignments already made
+	member := staff.State{Id: uuid.New()}
+	staffRepository, reservationRepository := newCrewRepositories(member)
+
+	at := time.Date(2030, 1, 10, 10, 0, 0, 0, time.UTC)
+	current := reservation.State{Delivery: reservation.DeliveryOrPickUp{At: at, By: []uuid.UUID{member.Id}}}
+	state := current
+	err := ensureCrew(context.Background(), infrastructure.ReservationOptions{AssignmentDuration: time.Hour},
+		staffRepository, reservationRepository, current, &state)
+
+	assert.Nil(t, err)
+	reservationRepository.AssertNotCalled(t, "GetByStaff", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestEnsureCrewWhenStaffNotFound(t *testing.T) {
+	staffRepository := &infrastructure.MockStaffRepository{}
+	staffRepository.
+		On("GetByIds", mock.Anything, mock.Anything).
+		Return([]staff.State{}, infrastructure.ErrOneStaffNotFound)
+
+	state := reservation.State{PickUp: reservation.DeliveryOrPickUp{By: []uuid.UUID{uuid.New()}}}
+	err := ensureCrew(context.Background(), infrastructure.ReservationOptions{},
+		staffRepository, &infrastructure.MockReservationRepository{}, reservation.State{}, &state)
+
+	assert.Equal(t, infrastructure.ErrOneStaffNotFound, err)
+}
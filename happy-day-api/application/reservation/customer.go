@@ -0,0 +1,30 @@
+package reservation
+
+import (
+	"context"
+
+	"happy_day/domain/reservation"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+)
+
+// resolveCustomer loads the referenced customer or creates the inline one, created tells the caller to clean it up
+// when the reservation is not saved
+func resolveCustomer(
+	ctx context.Context,
+	repository infrastructure.CustomerRepository,
+	id uuid.UUID,
+	inline reservation.Customer) (res reservation.Customer, created bool, err error) {
+	if id == uuid.Nil {
+		id = inline.Id
+	}
+
+	if id != uuid.Nil {
+		res.State, err = repository.GetById(ctx, id)
+		return res, false, err
+	}
+
+	res.State, err = repository.Save(ctx, inline.State)
+	return res, err == nil, err
+}
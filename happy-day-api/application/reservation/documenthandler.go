@@ -0,0 +1,110 @@
+package reservation
+
+import (
+	"context"
+	"time"
+
+	"happy_day/common"
+	"happy_day/domain/reservation"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+)
+
+const (
+	Contract DocumentKind = "contract"
+	Receipt  DocumentKind = "receipt"
+
+	removedProductName = "Produto removido"
+)
+
+type (
+	DocumentKind string
+
+	DocumentRequest struct {
+		Id   uuid.UUID
+		Kind DocumentKind
+	}
+
+	DocumentData struct {
+		Reservation reservation.State
+		Products    []DocumentProduct
+		IssuedAt    time.Time
+	}
+
+	DocumentProduct struct {
+		Name     string
+		Quantity int64
+		Price    float64
+		Total    float64
+	}
+
+	DocumentHandler struct {
+		options               infrastructure.DocumentOptions
+		productRepository     infrastructure.ProductRepository
+		reservationRepository infrastructure.ReservationRepository
+	}
+)
+
+func (handler DocumentHandler) Handle(ctx context.Context, req DocumentRequest) ([]byte, error) {
+	if req.Kind != Contract && req.Kind != Receipt {
+		return nil, infrastructure.ErrDocumentNotFound
+	}
+
+	state, err := handler.reservationRepository.GetById(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := documentProductNames(ctx, handler.productRepository, common.Distinct(reservationProductIds(state)))
+	if err != nil {
+		return nil, err
+	}
+
+	data := DocumentData{
+		Reservation: state,
+		IssuedAt:    time.Now().UTC(),
+		Products: common.Map(state.Products, func(item reservation.Product) DocumentProduct {
+			return DocumentProduct{
+				Name:     names[item.Id],
+				Quantity: item.Quantity,
+				Price:    item.Price,
+				Total:    item.Price * float64(item.Quantity),
+			}
+		}),
+	}
+
+	return infrastructure.RenderPdf(handler.options, string(req.Kind)+".tmpl", data)
+}
+
+// documentProductNames keeps the documents of a reservation available after its products are deleted or purged
+func documentProductNames(ctx context.Context, repository infrastructure.ProductRepository, ids []uuid.UUID) (map[uuid.UUID]string, error) {
+	products, err := getKnownProducts(ctx, repository, ids, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	names := productNames(products)
+	if len(names) == len(ids) {
+		return names, nil
+	}
+
+	deleted, err := repository.GetDeleted(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, state := range deleted {
+		if common.Contains(ids, state.Id) {
+			names[state.Id] = state.Name
+		}
+	}
+
+	for _, id := range ids {
+		if _, exists := names[id]; !exists {
+			names[id] = removedProductName
+		}
+	}
+
+	return names, nil
+}
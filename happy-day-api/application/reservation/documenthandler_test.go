@@ -0,0 +1,156 @@
+package reservation
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"happy_day/common"
+	"happy_day/domain/customer"
+	"happy_day/domain/product"
+	"happy_day/domain/reservation"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var documentOptions = infrastructure.DocumentOptions{TemplatesPath: "../../templates"}
+
+func TestDocumentWhenKindIsInvalid(t *testing.T) {
+	handler := DocumentHandler{options: documentOptions}
+	_, err := handler.Handle(context.Background(), DocumentRequest{Id: uuid.New(), Kind: DocumentKind(common.RandString(10))})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, infrastructure.ErrDocumentNotFound, err)
+}
+
+func TestDocumentWhenErrToGetById(t *testing.T) {
+	expectedErr := errors.New(common.RandString(10))
+	reservationRepository := &infrastructure.MockReservationRepository{}
+	reservationRepository.
+		On("GetById", mock.Anything, mock.Anything).
+		Return(reservation.State{}, expectedErr)
+
+	handler := DocumentHandler{options: documentOptions, reservationRepository: reservationRepository}
+	_, err := handler.Handle(context.Background(), DocumentRequest{Id: uuid.New(), Kind: Contract})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, expectedErr, err)
+}
+
+func TestDocument(t *testing.T) {
+	paidAt := time.Now().UTC()
+	state := reservation.State{
+		Id:         uuid.New(),
+		Price:      1250,
+		Discount:   50,
+		FinalPrice: 1200,
+		AmountPaid: 600,
+		BalanceDue: 600,
+		Comment:    "Festa da Júlia",
+		Products: []reservation.Product{
+			{Id: uuid.New(), Price: 450, Quantity: 1},
+			{Id: uuid.New(), Price: 20, Quantity: 40},
+		},
+		Delivery: reservation.DeliveryOrPickUp{At: time.Now().UTC()},
+		PickUp:   reservation.DeliveryOrPickUp{At: time.Now().UTC().Add(8 * time.Hour)},
+		PaymentInstallments: []reservation.PaymentInstallment{
+			{Amount: 600, Method: reservation.Pix, At: time.Now().UTC(), Status: reservation.Paid, PaidAt: &paidAt},
+			{Amount: 600, Method: reservation.Cash, At: time.Now().UTC(), Status: reservation.Scheduled},
+		},
+		Customer: reservation.Customer{
+			State: customer.State{
+				Name:   "João Conceição",
+				Phones: []customer.Phone{{Number: "11987654321"}},
+			},
+		},
+		Address: reservation.Address{
+			Street:       "Rua das Flores",
+			Number:       "10",
+			Neighborhood: "Centro",
+			PostalCode:   "01000-000",
+			City:         "São Paulo",
+		},
+	}
+
+	productRepository := &infrastructure.MockProductRepository{}
+	productRepository.
+		On("GetByProducts", mock.Anything, mock.Anything).
+		Return([]product.State{
+			{Id: state.Products[0].Id, Name: "Pula-pula"},
+			{Id: state.Products[1].Id, Name: "Cadeira"},
+		}, nil)
+
+	reservationRepository := &infrastructure.MockReservationRepository{}
+	reservationRepository.
+		On("GetById", mock.Anything, state.Id).
+		Return(state, nil)
+
+	handler := DocumentHandler{
+		options:               documentOptions,
+		productRepository:     productRepository,
+		reservationRepository: reservationRepository,
+	}
+
+	for _, kind := range []DocumentKind{Contract, Receipt} {
+		res, err := handler.Handle(context.Background(), DocumentRequest{Id: state.Id, Kind: kind})
+
+		assert.Nil(t, err)
+		assert.True(t, bytes.HasPrefix(res, []byte("%PDF")))
+	}
+}
+
+func TestDocumentWhenProductWasDeleted(t *testing.T) {
+	kept := product.State{Id: uuid.New(), Name: "Pula-pula"}
+	deleted := product.State{Id: uuid.New(), Name: "Cadeira"}
+	purged := uuid.New()
+	state := reservation.State{
+		Id: uuid.New(),
+		Products: []reservation.Product{
+			{Id: kept.Id, Price: 450, Quantity: 1},
+			{Id: deleted.Id, Price: 20, Quantity: 40},
+			{Id: purged, Price: 10, Quantity: 2},
+		},
+	}
+
+	productRepository := &infrastructure.MockProductRepository{}
+	productRepository.
+		On("GetByProducts", mock.Anything, mock.Anything).
+		Return([]product.State(nil), infrastructure.ErrOneProductNotFound)
+	productRepository.
+		On("GetById", mock.Anything, kept.Id).
+		Return(kept, nil)
+	productRepository.
+		On("GetById", mock.Anything, mock.Anything).
+		Return(product.State{}, infrastructure.ErrProductNotFound)
+	productRepository.
+		On("GetDeleted", mock.Anything).
+		Return([]product.State{deleted, {Id: uuid.New(), Name: common.RandString(10)}}, nil)
+
+	names, err := documentProductNames(context.Background(), productRepository, reservationProductIds(state))
+	assert.Nil(t, err)
+	assert.Equal(t, map[uuid.UUID]string{
+		kept.Id:    kept.Name,
+		deleted.Id: deleted.Name,
+		purged:     removedProductName,
+	}, names)
+
+	reservationRepository := &infrastructure.MockReservationRepository{}
+	reservationRepository.
+		On("GetById", mock.Anything, state.Id).
+		Return(state, nil)
+
+	handler := DocumentHandler{
+		options:               documentOptions,
+		productRepository:     productRepository,
+		reservationRepository: reservationRepository,
+	}
+
+	res, err := handler.Handle(context.Background(), DocumentRequest{Id: state.Id, Kind: Contract})
+	assert.Nil(t, err)
+	assert.True(t, bytes.HasPrefix(res, []byte("%PDF")))
+}
@@ -2,6 +2,7 @@ package reservation
 
 import (
 	"context"
+	"time"
 
 	"happy_day/domain/reservation"
 	"happy_day/infrastructure"
@@ -22,5 +23,13 @@ func (handler GetAllHandler) Handle(ctx context.Context, req infrastructure.Rese
 		req.Page = 1
 	}
 
+	if isInvalidPeriod(req.DeliveryFrom, req.DeliveryTo) || isInvalidPeriod(req.PickUpFrom, req.PickUpTo) {
+		return infrastructure.Page[reservation.State]{}, infrastructure.ErrReservationFilterIsInvalid
+	}
+
 	return handler.repository.GetAll(ctx, req)
 }
+
+func isInvalidPeriod(from, to time.Time) bool {
+	return !from.IsZero() && !to.IsZero() && !to.After(from)
+}
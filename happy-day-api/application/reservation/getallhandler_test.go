@@ -3,6 +3,7 @@ package reservation
 import (
 	"context"
 	"testing"
+	"time"
 
 	"happy_day/common"
 	"happy_day/domain/reservation"
@@ -31,3 +32,20 @@ func TestGetAllReservations(t *testing.T) {
 	_, err := handler.Handle(context.Background(), req)
 	assert.Nil(t, err)
 }
+
+func TestGetAllReservationsWhenDeliveryPeriodIsInvalid(t *testing.T) {
+	at := time.Now()
+	req := infrastructure.ReservationFilter{
+		DeliveryFrom: at,
+		DeliveryTo:   at.Add(-time.Hour),
+	}
+
+	repo := &infrastructure.MockReservationRepository{}
+	handler := GetAllHandler{
+		repository: repo,
+	}
+
+	_, err := handler.Handle(context.Background(), req)
+	assert.Equal(t, infrastructure.ErrReservationFilterIsInvalid, err)
+	repo.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything)
+}
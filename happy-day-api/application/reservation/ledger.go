@@ -0,0 +1,67 @@
+package reservation
+
+import (
+	"math"
+
+	"happy_day/domain/reservation"
+	"happy_day/infrastructure"
+)
+
+func validateInstallments(state reservation.State) error {
+	if len(state.PaymentInstallments) == 0 {
+		return nil
+	}
+
+	var total float64
+	for _, item := range state.PaymentInstallments {
+		total += item.Amount
+	}
+
+	if roundPrice(total) != roundPrice(state.FinalPrice) {
+		return infrastructure.ErrReservationInstallmentsMismatch
+	}
+
+	return nil
+}
+
+// mergeInstallments keeps the stored payments, an installment is only paid through the pay handler
+func mergeInstallments(current, requested []reservation.PaymentInstallment) ([]reservation.PaymentInstallment, error) {
+	merged := make([]reservation.PaymentInstallment, len(requested))
+	for i, item := range requested {
+		item.Status = reservation.Scheduled
+		item.PaidAt = nil
+		if i < len(current) && current[i].Status == reservation.Paid {
+			item = current[i]
+		}
+
+		merged[i] = item
+	}
+
+	for i := len(requested); i < len(current); i++ {
+		if current[i].Status == reservation.Paid {
+			return nil, infrastructure.ErrReservationInstallmentAlreadyPaid
+		}
+	}
+
+	return merged, nil
+}
+
+func updateBalance(state *reservation.State) {
+	var paid float64
+	for i, item := range state.PaymentInstallments {
+		if len(item.Status) == 0 {
+			state.PaymentInstallments[i].Status = reservation.Scheduled
+		}
+
+		if item.Status == reservation.Paid {
+			paid += item.Amount
+		}
+	}
+
+	state.AmountPaid = roundPrice(paid)
+	state.BalanceDue = roundPrice(state.FinalPrice - paid)
+}
+
+func roundPrice(value float64) float64 {
+	return math.Round(value*100) / 100
+}
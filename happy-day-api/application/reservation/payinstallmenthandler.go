@@ -0,0 +1,54 @@
+package reservation
+
+import (
+	"context"
+	"time"
+
+	"happy_day/domain/reservation"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+)
+
+type (
+	PayInstallmentRequest struct {
+		Id          uuid.UUID                 `json:"id"`
+		Installment int                       `json:"installment"`
+		Method      reservation.PaymentMethod `json:"method,omitempty"`
+		PaidAt      time.Time                 `json:"paidAt,omitempty"`
+	}
+
+	PayInstallmentHandler struct {
+		repository infrastructure.ReservationRepository
+	}
+)
+
+func (handler PayInstallmentHandler) Handle(ctx context.Context, req PayInstallmentRequest) (reservation.State, error) {
+	state, err := handler.repository.GetById(ctx, req.Id)
+	if err != nil {
+		return reservation.State{}, err
+	}
+
+	if req.Installment < 1 || req.Installment > len(state.PaymentInstallments) {
+		return reservation.State{}, infrastructure.ErrReservationInstallmentNotFound
+	}
+
+	installment := &state.PaymentInstallments[req.Installment-1]
+	if installment.Status == reservation.Paid {
+		return reservation.State{}, infrastructure.ErrReservationInstallmentAlreadyPaid
+	}
+
+	if req.PaidAt.IsZero() {
+		req.PaidAt = time.Now().UTC()
+	}
+
+	if len(req.Method) > 0 {
+		installment.Method = req.Method
+	}
+
+	installment.Status = reservation.Paid
+	installment.PaidAt = &req.PaidAt
+
+	updateBalance(&state)
+	return handler.repository.Save(ctx, state)
+}
@@ -0,0 +1,99 @@
+package reservation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"happy_day/common"
+	"happy_day/domain/reservation"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPayInstallmentWhenErrToGetById(t *testing.T) {
+	expectedErr := errors.New(common.RandString(10))
+	repository := &infrastructure.MockReservationRepository{}
+	repository.
+		On("GetById", mock.Anything, mock.Anything).
+		Return(reservation.State{}, expectedErr)
+
+	handler := PayInstallmentHandler{repository: repository}
+	_, err := handler.Handle(context.Background(), PayInstallmentRequest{Id: uuid.New(), Installment: 1})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, expectedErr, err)
+}
+
+func TestPayInstallmentWhenInstallmentNotFound(t *testing.T) {
+	for _, installment := range []int{0, 2} {
+		repository := &infrastructure.MockReservationRepository{}
+		repository.
+			On("GetById", mock.Anything, mock.Anything).
+			Return(reservation.State{
+				PaymentInstallments: []reservation.PaymentInstallment{{Amount: 10}},
+			}, nil)
+
+		handler := PayInstallmentHandler{repository: repository}
+		_, err := handler.Handle(context.Background(), PayInstallmentRequest{Id: uuid.New(), Installment: installment})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, infrastructure.ErrReservationInstallmentNotFound, err)
+	}
+}
+
+func TestPayInstallmentWhenAlreadyPaid(t *testing.T) {
+	repository := &infrastructure.MockReservationRepository{}
+	repository.
+		On("GetById", mock.Anything, mock.Anything).
+		Return(reservation.State{
+			PaymentInstallments: []reservation.PaymentInstallment{{Amount: 10, Status: reservation.Paid}},
+		}, nil)
+
+	handler := PayInstallmentHandler{repository: repository}
+	_, err := handler.Handle(context.Background(), PayInstallmentRequest{Id: uuid.New(), Installment: 1})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, infrastructure.ErrReservationInstallmentAlreadyPaid, err)
+}
+
+func TestPayInstallment(t *testing.T) {
+	paidAt := time.Now().UTC()
+	repository := &infrastructure.MockReservationRepository{}
+	repository.
+		On("GetById", mock.Anything, mock.Anything).
+		Return(reservation.State{
+			FinalPrice: 100,
+			PaymentInstallments: []reservation.PaymentInstallment{
+				{Amount: 40, Method: reservation.Pix, Status: reservation.Paid},
+				{Amount: 60, Method: reservation.Pix, Status: reservation.Scheduled},
+			},
+		}, nil)
+
+	var res reservation.State
+	repository.
+		On("Save", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			res = args.Get(1).(reservation.State)
+		}).
+		Return(reservation.State{}, nil)
+
+	handler := PayInstallmentHandler{repository: repository}
+	_, err := handler.Handle(context.Background(), PayInstallmentRequest{
+		Id:          uuid.New(),
+		Installment: 2,
+		Method:      reservation.Cash,
+		PaidAt:      paidAt,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 100.0, res.AmountPaid)
+	assert.Equal(t, 0.0, res.BalanceDue)
+	assert.Equal(t, reservation.Paid, res.PaymentInstallments[1].Status)
+	assert.Equal(t, reservation.Cash, res.PaymentInstallments[1].Method)
+	assert.Equal(t, paidAt, *res.PaymentInstallments[1].PaidAt)
+}
@@ -0,0 +1,71 @@
+package reservation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"happy_day/domain/reservation"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+	"github.com/skip2/go-qrcode"
+)
+
+const pixQrCodeSize = 512
+
+type (
+	PixRequest struct {
+		Id          uuid.UUID `json:"id"`
+		Installment int       `json:"installment"`
+	}
+
+	PixResponse struct {
+		Amount        float64 `json:"amount"`
+		TransactionId string  `json:"transactionId"`
+		Payload       string  `json:"payload"`
+		QrCode        []byte  `json:"qrCode"`
+	}
+
+	PixHandler struct {
+		options    infrastructure.PixOptions
+		repository infrastructure.ReservationRepository
+	}
+)
+
+func (handler PixHandler) Handle(ctx context.Context, req PixRequest) (PixResponse, error) {
+	if !handler.options.IsValid() {
+		return PixResponse{}, infrastructure.ErrPixIsNotConfigured
+	}
+
+	state, err := handler.repository.GetById(ctx, req.Id)
+	if err != nil {
+		return PixResponse{}, err
+	}
+
+	if req.Installment < 1 || req.Installment > len(state.PaymentInstallments) {
+		return PixResponse{}, infrastructure.ErrReservationInstallmentNotFound
+	}
+
+	installment := state.PaymentInstallments[req.Installment-1]
+	if installment.Status == reservation.Paid {
+		return PixResponse{}, infrastructure.ErrReservationInstallmentAlreadyPaid
+	}
+
+	if installment.Method != reservation.Pix {
+		return PixResponse{}, infrastructure.ErrReservationInstallmentIsNotPix
+	}
+
+	res := PixResponse{
+		Amount:        installment.Amount,
+		TransactionId: pixTransactionId(state.Id, req.Installment),
+	}
+
+	res.Payload = infrastructure.BuildPixPayload(handler.options, res.Amount, res.TransactionId)
+	res.QrCode, err = qrcode.Encode(res.Payload, qrcode.Medium, pixQrCodeSize)
+	return res, err
+}
+
+func pixTransactionId(id uuid.UUID, installment int) string {
+	return fmt.Sprintf("%s%02d", strings.ReplaceAll(id.String(), "-", "")[:23], installment%100)
+}
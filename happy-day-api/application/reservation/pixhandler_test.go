@@ -0,0 +1,98 @@
+package reservation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"happy_day/common"
+	"happy_day/domain/reservation"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var pixOptions = infrastructure.PixOptions{
+	Key:          common.RandString(10),
+	MerchantName: common.RandString(10),
+	MerchantCity: common.RandString(10),
+}
+
+func TestPixWhenIsNotConfigured(t *testing.T) {
+	handler := PixHandler{}
+	_, err := handler.Handle(context.Background(), PixRequest{Id: uuid.New(), Installment: 1})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, infrastructure.ErrPixIsNotConfigured, err)
+}
+
+func TestPixWhenErrToGetById(t *testing.T) {
+	expectedErr := errors.New(common.RandString(10))
+	repository := &infrastructure.MockReservationRepository{}
+	repository.
+		On("GetById", mock.Anything, mock.Anything).
+		Return(reservation.State{}, expectedErr)
+
+	handler := PixHandler{options: pixOptions, repository: repository}
+	_, err := handler.Handle(context.Background(), PixRequest{Id: uuid.New(), Installment: 1})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, expectedErr, err)
+}
+
+func TestPixWhenInstallmentNotFound(t *testing.T) {
+	repository := &infrastructure.MockReservationRepository{}
+	repository.
+		On("GetById", mock.Anything, mock.Anything).
+		Return(reservation.State{}, nil)
+
+	handler := PixHandler{options: pixOptions, repository: repository}
+	_, err := handler.Handle(context.Background(), PixRequest{Id: uuid.New(), Installment: 1})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, infrastructure.ErrReservationInstallmentNotFound, err)
+}
+
+func TestPixWhenInstallmentIsNotPix(t *testing.T) {
+	repository := &infrastructure.MockReservationRepository{}
+	repository.
+		On("GetById", mock.Anything, mock.Anything).
+		Return(reservation.State{
+			PaymentInstallments: []reservation.PaymentInstallment{
+				{Amount: 100, Method: reservation.Cash, Status: reservation.Scheduled},
+			},
+		}, nil)
+
+	handler := PixHandler{options: pixOptions, repository: repository}
+	_, err := handler.Handle(context.Background(), PixRequest{Id: uuid.New(), Installment: 1})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, infrastructure.ErrReservationInstallmentIsNotPix, err)
+}
+
+func TestPix(t *testing.T) {
+	id := uuid.MustParse("1fd49fff-e5c6-4878-9c42-f2fa77bdc2fe")
+	repository := &infrastructure.MockReservationRepository{}
+	repository.
+		On("GetById", mock.Anything, id).
+		Return(reservation.State{
+			Id: id,
+			PaymentInstallments: []reservation.PaymentInstallment{
+				{Amount: 100, Method: reservation.Pix, Status: reservation.Paid},
+				{Amount: 250.9, Method: reservation.Pix, Status: reservation.Scheduled},
+			},
+		}, nil)
+
+	handler := PixHandler{options: pixOptions, repository: repository}
+	res, err := handler.Handle(context.Background(), PixRequest{Id: id, Installment: 2})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 250.9, res.Amount)
+	assert.Equal(t, "1fd49fffe5c648789c42f2f02", res.TransactionId)
+	assert.True(t, strings.Contains(res.Payload, "5406250.90"))
+	assert.True(t, strings.Contains(res.Payload, "05251fd49fffe5c648789c42f2f02"))
+	assert.NotEmpty(t, res.QrCode)
+}
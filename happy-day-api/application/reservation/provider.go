@@ -13,6 +13,14 @@ var ProvideSet = wire.NewSet(
 	ProvideChangeHandler,
 	ProvideQuoteHandler,
 	ProvideDeleteHandler,
+	ProvideRestoreHandler,
+	ProvideHistoryHandler,
+	ProvideRevertHandler,
+	ProvideAvailabilityHandler,
+	ProvideChangeStatusHandler,
+	ProvidePayInstallmentHandler,
+	ProvidePixHandler,
+	ProvideDocumentHandler,
 )
 
 func ProvideGetAllHandler(repository infrastructure.ReservationRepository) GetAllHandler {
@@ -24,16 +32,37 @@ func ProvideGetByIdHandler(repository infrastructure.ReservationRepository) GetB
 }
 
 func ProvideCreateHandler(
+	pricingOptions infrastructure.PricingOptions,
+	reservationOptions infrastructure.ReservationOptions,
+	customerRepository infrastructure.CustomerRepository,
 	productRepository infrastructure.ProductRepository,
-	reservationRepository infrastructure.ReservationRepository) CreateHandler {
+	reservationRepository infrastructure.ReservationRepository,
+	staffRepository infrastructure.StaffRepository) CreateHandler {
 	return CreateHandler{
+		pricingOptions:        pricingOptions,
+		reservationOptions:    reservationOptions,
+		customerRepository:    customerRepository,
 		productRepository:     productRepository,
 		reservationRepository: reservationRepository,
+		staffRepository:       staffRepository,
 	}
 }
 
-func ProvideChangeHandler(repository infrastructure.ReservationRepository) ChangeHandler {
-	return ChangeHandler{repository: repository}
+func ProvideChangeHandler(
+	pricingOptions infrastructure.PricingOptions,
+	reservationOptions infrastructure.ReservationOptions,
+	customerRepository infrastructure.CustomerRepository,
+	productRepository infrastructure.ProductRepository,
+	reservationRepository infrastructure.ReservationRepository,
+	staffRepository infrastructure.StaffRepository) ChangeHandler {
+	return ChangeHandler{
+		pricingOptions:        pricingOptions,
+		reservationOptions:    reservationOptions,
+		customerRepository:    customerRepository,
+		productRepository:     productRepository,
+		reservationRepository: reservationRepository,
+		staffRepository:       staffRepository,
+	}
 }
 
 func ProvideDeleteHandler(repository infrastructure.ReservationRepository) DeleteHandler {
@@ -45,3 +74,63 @@ func ProvideQuoteHandler(repository infrastructure.ProductRepository) QuoteHandl
 		repository: repository,
 	}
 }
+
+func ProvideAvailabilityHandler(
+	options infrastructure.ReservationOptions,
+	productRepository infrastructure.ProductRepository,
+	reservationRepository infrastructure.ReservationRepository) AvailabilityHandler {
+	return AvailabilityHandler{
+		options:               options,
+		productRepository:     productRepository,
+		reservationRepository: reservationRepository,
+	}
+}
+
+func ProvideChangeStatusHandler(repository infrastructure.ReservationRepository) ChangeStatusHandler {
+	return ChangeStatusHandler{repository: repository}
+}
+
+func ProvidePayInstallmentHandler(repository infrastructure.ReservationRepository) PayInstallmentHandler {
+	return PayInstallmentHandler{repository: repository}
+}
+
+func ProvidePixHandler(options infrastructure.PixOptions, repository infrastructure.ReservationRepository) PixHandler {
+	return PixHandler{
+		options:    options,
+		repository: repository,
+	}
+}
+
+func ProvideDocumentHandler(
+	options infrastructure.DocumentOptions,
+	productRepository infrastructure.ProductRepository,
+	reservationRepository infrastructure.ReservationRepository) DocumentHandler {
+	return DocumentHandler{
+		options:               options,
+		productRepository:     productRepository,
+		reservationRepository: reservationRepository,
+	}
+}
+
+func ProvideRestoreHandler(repository infrastructure.ReservationRepository) RestoreHandler {
+	return RestoreHandler{repository: repository}
+}
+
+func ProvideHistoryHandler(repository infrastructure.ReservationRepository) HistoryHandler {
+	return HistoryHandler{repository: repository}
+}
+
+func ProvideRevertHandler(
+	reservationOptions infrastructure.ReservationOptions,
+	customerRepository infrastructure.CustomerRepository,
+	productRepository infrastructure.ProductRepository,
+	reservationRepository infrastructure.ReservationRepository,
+	staffRepository infrastructure.StaffRepository) RevertHandler {
+	return RevertHandler{
+		reservationOptions:    reservationOptions,
+		customerRepository:    customerRepository,
+		productRepository:     productRepository,
+		reservationRepository: reservationRepository,
+		staffRepository:       staffRepository,
+	}
+}
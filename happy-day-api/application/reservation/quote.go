@@ -0,0 +1,313 @@
+package reservation
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"happy_day/domain/product"
+
+	"github.com/google/uuid"
+)
+
+const (
+	LowestPrice     QuoteStrategy = "lowestPrice"
+	BestForBusiness QuoteStrategy = "bestForBusiness"
+
+	// maxQuoteQuantity bounds the quantity of each product, the kit search grows with the quantities
+	maxQuoteQuantity = 10_000
+	// maxPackingStates bounds the exhaustive kit search, larger quotes are packed greedily and marked approximate
+	maxPackingStates = 100_000
+)
+
+type (
+	QuoteStrategy string
+
+	packing struct {
+		Price       float64
+		Kits        []int64
+		Leftover    []int64
+		Approximate bool
+	}
+
+	kitPacker struct {
+		strategy   QuoteStrategy
+		products   []uuid.UUID
+		prices     []float64
+		amounts    []int64
+		kits       []product.State
+		components [][]int64
+		memo       map[string]packing
+	}
+)
+
+func (strategy QuoteStrategy) IsValid() bool {
+	return strategy == LowestPrice || strategy == BestForBusiness
+}
+
+func newKitPacker(strategy QuoteStrategy, kits []product.State, products []product.State, amounts map[uuid.UUID]int64) *kitPacker {
+	packer := &kitPacker{
+		strategy: strategy,
+		memo:     map[string]packing{},
+	}
+
+	for id := range amounts {
+		packer.products = append(packer.products, id)
+	}
+
+	sort.Slice(packer.products, func(i, j int) bool {
+		return packer.products[i].String() < packer.products[j].String()
+	})
+
+	index := map[uuid.UUID]int{}
+	packer.prices = make([]float64, len(packer.products))
+	packer.amounts = make([]int64, len(packer.products))
+	for i, id := range packer.products {
+		index[id] = i
+		packer.amounts[i] = amounts[id]
+	}
+
+	for _, item := range products {
+		if i, exists := index[item.Id]; exists {
+			packer.prices[i] = item.Price
+		}
+	}
+
+	kits = append([]product.State{}, kits...)
+	sort.Slice(kits, func(i, j int) bool {
+		return kits[i].Id.String() < kits[j].Id.String()
+	})
+
+	for _, kit := range kits {
+		components, ok := kitComponents(kit, index)
+		if !ok {
+			continue
+		}
+
+		packer.kits = append(packer.kits, kit)
+		packer.components = append(packer.components, components)
+	}
+
+	return packer
+}
+
+func kitComponents(kit product.State, index map[uuid.UUID]int) ([]int64, bool) {
+	if len(kit.Products) == 0 {
+		return nil, false
+	}
+
+	components := make([]int64, len(index))
+	for _, item := range kit.Products {
+		i, exists := index[item.Id]
+		if !exists || item.Quantity <= 0 {
+			return nil, false
+		}
+
+		components[i] += item.Quantity
+	}
+
+	return components, true
+}
+
+func (packer *kitPacker) Pack() packing {
+	if packer.searchSize() > maxPackingStates {
+		return packer.greedy()
+	}
+
+	return packer.pack(0, packer.amounts)
+}
+
+func (packer *kitPacker) searchSize() int64 {
+	size := int64(1)
+	for kit := range packer.kits {
+		size *= packer.maxCount(kit, packer.amounts) + 1
+		if size > maxPackingStates {
+			break
+		}
+	}
+
+	return size
+}
+
+func (packer *kitPacker) maxCount(kit int, remaining []int64) int64 {
+	max := int64(math.MaxInt64)
+	for i, quantity := range packer.components[kit] {
+		if quantity > 0 && remaining[i]/quantity < max {
+			max = remaining[i] / quantity
+		}
+	}
+
+	if max < 0 {
+		return 0
+	}
+
+	return max
+}
+
+// greedy takes the kits with the largest gain for the strategy first
+func (packer *kitPacker) greedy() packing {
+	gains := make([]float64, len(packer.kits))
+	order := make([]int, 0, len(packer.kits))
+	for kit, components := range packer.components {
+		var loose float64
+		for i, quantity := range components {
+			loose += packer.prices[i] * float64(quantity)
+		}
+
+		gains[kit] = loose - packer.kits[kit].Price
+		if packer.strategy == BestForBusiness {
+			gains[kit] = -gains[kit]
+		}
+
+		if gains[kit] > 0 {
+			order = append(order, kit)
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return gains[order[i]] > gains[order[j]]
+	})
+
+	res := packing{
+		Kits:        make([]int64, len(packer.kits)),
+		Leftover:    append([]int64{}, packer.amounts...),
+		Approximate: true,
+	}
+
+	for _, kit := range order {
+		count := packer.maxCount(kit, res.Leftover)
+		for i, quantity := range packer.components[kit] {
+			res.Leftover[i] -= quantity * count
+		}
+
+		res.Kits[kit] = count
+		res.Price += float64(count) * packer.kits[kit].Price
+	}
+
+	for i, amount := range res.Leftover {
+		res.Price += packer.prices[i] * float64(amount)
+	}
+
+	return res
+}
+
+func (packer *kitPacker) pack(kit int, remaining []int64) packing {
+	if kit == len(packer.kits) {
+		var price float64
+		for i, amount := range remaining {
+			price += packer.prices[i] * float64(amount)
+		}
+
+		return packing{
+			Price:    price,
+			Kits:     make([]int64, len(packer.kits)),
+			Leftover: append([]int64{}, remaining...),
+		}
+	}
+
+	key := packer.key(kit, remaining)
+	if res, exists := packer.memo[key]; exists {
+		return res
+	}
+
+	components := packer.components[kit]
+	max := packer.maxCount(kit, remaining)
+
+	var best packing
+	found := false
+	next := make([]int64, len(remaining))
+	for count := max; count >= 0; count-- {
+		for i, quantity := range components {
+			next[i] = remaining[i] - quantity*count
+		}
+
+		res := packer.pack(kit+1, next)
+		price := res.Price + float64(count)*packer.kits[kit].Price
+		if !found || packer.isBetter(price, best.Price) {
+			best = packing{
+				Price:    price,
+				Kits:     append([]int64{}, res.Kits...),
+				Leftover: res.Leftover,
+			}
+
+			best.Kits[kit] = count
+			found = true
+		}
+	}
+
+	packer.memo[key] = best
+	return best
+}
+
+func (packer *kitPacker) isBetter(price, current float64) bool {
+	price = math.Round(price * 100)
+	current = math.Round(current * 100)
+	if packer.strategy == BestForBusiness {
+		return price > current
+	}
+
+	return price < current
+}
+
+func (packer *kitPacker) key(kit int, remaining []int64) string {
+	var builder strings.Builder
+	builder.WriteString(strconv.Itoa(kit))
+	for _, amount := range remaining {
+		builder.WriteByte(':')
+		builder.WriteString(strconv.FormatInt(amount, 10))
+	}
+
+	return builder.String()
+}
+
+func (packer *kitPacker) Response(res packing, products []product.State) QuoteProductResponse {
+	names := productNames(products)
+	quote := QuoteProductResponse{
+		Price:       res.Price,
+		Approximate: res.Approximate,
+		Kits:        make([]QuoteItemResponse, 0),
+		Products:    make([]QuoteItemResponse, 0),
+	}
+
+	for i, kit := range packer.kits {
+		if i >= len(res.Kits) || res.Kits[i] <= 0 {
+			continue
+		}
+
+		quote.Kits = append(quote.Kits, QuoteItemResponse{
+			Id:        kit.Id,
+			Name:      kit.Name,
+			Quantity:  res.Kits[i],
+			UnitPrice: kit.Price,
+			Price:     kit.Price * float64(res.Kits[i]),
+		})
+	}
+
+	for i, id := range packer.products {
+		quote.LoosePrice += packer.prices[i] * float64(packer.amounts[i])
+		if i >= len(res.Leftover) || res.Leftover[i] <= 0 {
+			continue
+		}
+
+		quote.Products = append(quote.Products, QuoteItemResponse{
+			Id:        id,
+			Name:      names[id],
+			Quantity:  res.Leftover[i],
+			UnitPrice: packer.prices[i],
+			Price:     packer.prices[i] * float64(res.Leftover[i]),
+		})
+	}
+
+	quote.Savings = quote.LoosePrice - quote.Price
+	return quote
+}
+
+func productNames(products []product.State) map[uuid.UUID]string {
+	names := map[uuid.UUID]string{}
+	for _, item := range products {
+		names[item.Id] = item.Name
+	}
+
+	return names
+}
@@ -2,9 +2,9 @@ package reservation
 
 import (
 	"context"
-	"math"
 
 	"happy_day/common"
+	"happy_day/domain/product"
 	"happy_day/infrastructure"
 
 	"github.com/google/uuid"
@@ -13,6 +13,7 @@ import (
 type (
 	QuoteRequest struct {
 		Products []QuoteProductRequest `json:"products"`
+		Strategy QuoteStrategy         `json:"strategy,omitempty"`
 	}
 
 	QuoteProductRequest struct {
@@ -21,7 +22,21 @@ type (
 	}
 
 	QuoteProductResponse struct {
-		Price float64 `json:"price"`
+		Price      float64 `json:"price"`
+		LoosePrice float64 `json:"loosePrice"`
+		Savings    float64 `json:"savings"`
+		// Approximate is set when the quote was too large to search every kit combination
+		Approximate bool                `json:"approximate"`
+		Kits        []QuoteItemResponse `json:"kits"`
+		Products    []QuoteItemResponse `json:"products"`
+	}
+
+	QuoteItemResponse struct {
+		Id        uuid.UUID `json:"id"`
+		Name      string    `json:"name"`
+		Quantity  int64     `json:"quantity"`
+		UnitPrice float64   `json:"unitPrice"`
+		Price     float64   `json:"price"`
 	}
 
 	QuoteHandler struct {
@@ -30,56 +45,59 @@ type (
 )
 
 func (handler QuoteHandler) Handler(ctx context.Context, req QuoteRequest) (QuoteProductResponse, error) {
-	ids := common.Map(req.Products, func(item QuoteProductRequest) uuid.UUID {
-		return item.Id
-	})
-
-	composed, err := handler.repository.GetComposed(ctx, ids)
-	if err != nil {
-		return QuoteProductResponse{}, err
+	if len(req.Strategy) == 0 {
+		req.Strategy = LowestPrice
 	}
 
-	productAmount := map[uuid.UUID]int64{}
-	for _, product := range req.Products {
-		productAmount[product.Id] = product.Quantity
+	if !req.Strategy.IsValid() {
+		return QuoteProductResponse{}, infrastructure.ErrQuoteStrategyIsInvalid
 	}
 
-	var price float64
-	for _, box := range composed {
-		min := math.MaxFloat64
-		for _, product := range box.Products {
-			if product.Quantity == 0 {
-				break
-			}
-
-			amount, exists := productAmount[product.Id]
-			if !exists {
-				min = 0
-				break
-			}
-			min = math.Min(min, math.Floor(float64(amount/product.Quantity)))
-		}
+	quote, _, err := quoteProducts(ctx, handler.repository, req.Strategy, req.Products)
+	return quote, err
+}
 
-		if min == 0 || min == math.MaxFloat64 {
-			continue
+// quoteProducts prices the products with the kit packer, it is shared with the creation so both always agree
+func quoteProducts(
+	ctx context.Context,
+	repository infrastructure.ProductRepository,
+	strategy QuoteStrategy,
+	items []QuoteProductRequest) (QuoteProductResponse, []product.State, error) {
+	productAmount := map[uuid.UUID]int64{}
+	for _, item := range items {
+		err := validateQuantity(item.Quantity)
+		if err != nil {
+			return QuoteProductResponse{}, nil, err
 		}
 
-		price += min * box.Price
-		for _, product := range box.Products {
-			productAmount[product.Id] -= product.Quantity * int64(min)
+		productAmount[item.Id] += item.Quantity
+		if productAmount[item.Id] > maxQuoteQuantity {
+			return QuoteProductResponse{}, nil, infrastructure.ErrProductAmountIsInvalid
 		}
 	}
 
-	products, err := handler.repository.GetByProducts(ctx, ids)
+	ids := common.Distinct(common.Map(items, func(item QuoteProductRequest) uuid.UUID {
+		return item.Id
+	}))
+
+	composed, err := repository.GetComposed(ctx, ids)
+	if err != nil {
+		return QuoteProductResponse{}, nil, err
+	}
+
+	products, err := repository.GetByProducts(ctx, ids)
 	if err != nil {
-		return QuoteProductResponse{}, err
+		return QuoteProductResponse{}, nil, err
 	}
 
-	for _, product := range products {
-		price += product.Price * float64(productAmount[product.Id])
+	packer := newKitPacker(strategy, composed, products, productAmount)
+	return packer.Response(packer.Pack(), products), products, nil
+}
+
+func validateQuantity(quantity int64) error {
+	if quantity <= 0 || quantity > maxQuoteQuantity {
+		return infrastructure.ErrProductAmountIsInvalid
 	}
 
-	return QuoteProductResponse{
-		Price: price,
-	}, nil
+	return nil
 }
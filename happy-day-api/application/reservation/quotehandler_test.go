@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"happy_day/common"
 	"happy_day/domain/product"
@@ -103,7 +104,10 @@ func TestQuoteReservation(t *testing.T) {
 						},
 					},
 				},
-				dbProducts: []product.State{},
+				dbProducts: []product.State{
+					{Id: uuid.MustParse("1fd49fff-e5c6-4878-9c42-f2fa77bdc2fe"), Price: 1.5},
+					{Id: uuid.MustParse("4491c392-4b6e-4a6e-aa64-c2897a258451"), Price: 4},
+				},
 			},
 			expected: 25,
 		},
@@ -181,3 +185,251 @@ func TestQuoteReservation(t *testing.T) {
 		})
 	}
 }
+
+func TestQuoteReservationWhenStrategyIsInvalid(t *testing.T) {
+	handler := QuoteHandler{}
+	_, err := handler.Handler(context.Background(), QuoteRequest{Strategy: QuoteStrategy(common.RandString(10))})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, infrastructure.ErrQuoteStrategyIsInvalid, err)
+}
+
+func TestQuoteReservationWithOverlappingKits(t *testing.T) {
+	tent := uuid.MustParse("1fd49fff-e5c6-4878-9c42-f2fa77bdc2fe")
+	table := uuid.MustParse("4491c392-4b6e-4a6e-aa64-c2897a258451")
+	chair := uuid.MustParse("b387b182-e12a-4757-99c8-31b6596d102d")
+
+	tentAndTable := product.State{
+		Id:    uuid.MustParse("0a6f1e2a-38a4-4d5e-bf0e-6f3c1f1b1a01"),
+		Price: 15,
+		Products: []product.Product{
+			{Id: tent, Quantity: 1},
+			{Id: table, Quantity: 1},
+		},
+	}
+
+	tentAndChair := product.State{
+		Id:    uuid.MustParse("0a6f1e2a-38a4-4d5e-bf0e-6f3c1f1b1a02"),
+		Price: 15,
+		Products: []product.Product{
+			{Id: tent, Quantity: 1},
+			{Id: chair, Quantity: 1},
+		},
+	}
+
+	party := product.State{
+		Id:    uuid.MustParse("0a6f1e2a-38a4-4d5e-bf0e-6f3c1f1b1a03"),
+		Price: 22,
+		Products: []product.Product{
+			{Id: tent, Quantity: 1},
+			{Id: table, Quantity: 1},
+			{Id: chair, Quantity: 1},
+		},
+	}
+
+	dbProducts := []product.State{
+		{Id: tent, Price: 10},
+		{Id: table, Price: 10},
+		{Id: chair, Price: 10},
+	}
+
+	type param struct {
+		name     string
+		strategy QuoteStrategy
+		products []QuoteProductRequest
+		boxes    []product.State
+		expected float64
+	}
+
+	cases := []param{
+		{
+			name: "Shared components prefer the bigger kit",
+			products: []QuoteProductRequest{
+				{Id: tent, Quantity: 1},
+				{Id: table, Quantity: 1},
+				{Id: chair, Quantity: 1},
+			},
+			boxes:    []product.State{tentAndTable, tentAndChair, party},
+			expected: 22,
+		},
+		{
+			name: "Shared components prefer the bigger kit in any order",
+			products: []QuoteProductRequest{
+				{Id: tent, Quantity: 1},
+				{Id: table, Quantity: 1},
+				{Id: chair, Quantity: 1},
+			},
+			boxes:    []product.State{party, tentAndChair, tentAndTable},
+			expected: 22,
+		},
+		{
+			name: "Shared components prefer two smaller kits",
+			products: []QuoteProductRequest{
+				{Id: tent, Quantity: 2},
+				{Id: table, Quantity: 1},
+				{Id: chair, Quantity: 1},
+			},
+			boxes:    []product.State{party, tentAndTable, tentAndChair},
+			expected: 30,
+		},
+		{
+			name:     "Best for business",
+			strategy: BestForBusiness,
+			products: []QuoteProductRequest{
+				{Id: tent, Quantity: 2},
+				{Id: table, Quantity: 1},
+				{Id: chair, Quantity: 1},
+			},
+			boxes:    []product.State{party, tentAndTable, tentAndChair},
+			expected: 40,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(k *testing.T) {
+			repository := &infrastructure.MockProductRepository{}
+			repository.On("GetComposed", mock.Anything, mock.Anything).Return(c.boxes, nil)
+			repository.On("GetByProducts", mock.Anything, mock.Anything).Return(dbProducts, nil)
+
+			handler := QuoteHandler{
+				repository: repository,
+			}
+
+			res, err := handler.Handler(context.Background(), QuoteRequest{
+				Products: c.products,
+				Strategy: c.strategy,
+			})
+
+			assert.Nil(k, err)
+			assert.Equal(k, c.expected, res.Price)
+		})
+	}
+}
+
+func TestQuoteReservationBreakdown(t *testing.T) {
+	chair := uuid.MustParse("1fd49fff-e5c6-4878-9c42-f2fa77bdc2fe")
+	table := uuid.MustParse("4491c392-4b6e-4a6e-aa64-c2897a258451")
+	balloon := uuid.MustParse("b387b182-e12a-4757-99c8-31b6596d102d")
+
+	kit := product.State{
+		Id:    uuid.New(),
+		Name:  common.RandString(10),
+		Price: 2.5,
+		Products: []product.Product{
+			{Id: chair, Quantity: 4},
+			{Id: table, Quantity: 1},
+		},
+	}
+
+	dbProducts := []product.State{
+		{Id: chair, Name: common.RandString(10), Price: 1.5},
+		{Id: table, Name: common.RandString(10), Price: 4},
+		{Id: balloon, Name: common.RandString(10), Price: 2},
+	}
+
+	repository := &infrastructure.MockProductRepository{}
+	repository.On("GetComposed", mock.Anything, mock.Anything).Return([]product.State{kit}, nil)
+	repository.On("GetByProducts", mock.Anything, mock.Anything).Return(dbProducts, nil)
+
+	handler := QuoteHandler{
+		repository: repository,
+	}
+
+	res, err := handler.Handler(context.Background(), QuoteRequest{
+		Products: []QuoteProductRequest{
+			{Id: chair, Quantity: 45},
+			{Id: table, Quantity: 10},
+			{Id: balloon, Quantity: 3},
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 38.5, res.Price)
+	assert.Equal(t, 113.5, res.LoosePrice)
+	assert.Equal(t, 75.0, res.Savings)
+	assert.Equal(t, []QuoteItemResponse{
+		{Id: kit.Id, Name: kit.Name, Quantity: 10, UnitPrice: 2.5, Price: 25},
+	}, res.Kits)
+	assert.ElementsMatch(t, []QuoteItemResponse{
+		{Id: chair, Name: dbProducts[0].Name, Quantity: 5, UnitPrice: 1.5, Price: 7.5},
+		{Id: balloon, Name: dbProducts[2].Name, Quantity: 3, UnitPrice: 2, Price: 6},
+	}, res.Products)
+}
+
+func TestQuoteReservationWhenQuantitiesAreLarge(t *testing.T) {
+	products := []product.State{
+		{Id: uuid.New(), Price: 1},
+		{Id: uuid.New(), Price: 1},
+		{Id: uuid.New(), Price: 1},
+	}
+
+	var kits []product.State
+	var req QuoteRequest
+	for _, item := range products {
+		kits = append(kits, product.State{
+			Id:       uuid.New(),
+			Price:    1.5,
+			Products: []product.Product{{Id: item.Id, Quantity: 2}},
+		})
+		req.Products = append(req.Products, QuoteProductRequest{Id: item.Id, Quantity: 301})
+	}
+
+	repository := &infrastructure.MockProductRepository{}
+	repository.On("GetComposed", mock.Anything, mock.Anything).Return(kits, nil)
+	repository.On("GetByProducts", mock.Anything, mock.Anything).Return(products, nil)
+
+	handler := QuoteHandler{repository: repository}
+	start := time.Now()
+	res, err := handler.Handler(context.Background(), req)
+
+	assert.Nil(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, float64(3*150*1.5+3), res.Price)
+	assert.True(t, res.Approximate)
+	assert.Len(t, res.Kits, 3)
+	assert.Len(t, res.Products, 3)
+}
+
+func TestQuoteReservationWhenQuantityIsInvalid(t *testing.T) {
+	id := uuid.New()
+	requests := [][]QuoteProductRequest{
+		{{Id: id, Quantity: 1}, {Id: uuid.New(), Quantity: -1}},
+		{{Id: id, Quantity: 0}},
+		{{Id: id, Quantity: -5}},
+		{{Id: id, Quantity: maxQuoteQuantity + 1}},
+		{{Id: id, Quantity: maxQuoteQuantity}, {Id: id, Quantity: 1}},
+	}
+
+	for _, products := range requests {
+		repository := &infrastructure.MockProductRepository{}
+		handler := QuoteHandler{repository: repository}
+		_, err := handler.Handler(context.Background(), QuoteRequest{Products: products})
+
+		assert.ErrorIs(t, err, infrastructure.ErrProductAmountIsInvalid)
+		repository.AssertNotCalled(t, "GetComposed", mock.Anything, mock.Anything)
+	}
+}
+
+func TestQuoteReservationWhenPackingIsEmpty(t *testing.T) {
+	products := []product.State{{Id: uuid.New(), Price: 10}}
+	kits := []product.State{{Id: uuid.New(), Price: 15, Products: []product.Product{{Id: products[0].Id, Quantity: 2}}}}
+
+	packer := newKitPacker(LowestPrice, kits, products, map[uuid.UUID]int64{products[0].Id: 1})
+	res := packer.Response(packing{}, products)
+
+	assert.Empty(t, res.Kits)
+	assert.Empty(t, res.Products)
+}
+
+func TestQuoteReservationWhenSearchReachesMaxPackingStates(t *testing.T) {
+	products := []product.State{{Id: uuid.New(), Price: 1}}
+	kits := []product.State{{Id: uuid.New(), Price: 0.5, Products: []product.Product{{Id: products[0].Id, Quantity: 1}}}}
+
+	for amount, approximate := range map[int64]bool{maxPackingStates - 1: false, maxPackingStates: true} {
+		packer := newKitPacker(LowestPrice, kits, products, map[uuid.UUID]int64{products[0].Id: amount})
+		res := packer.Response(packer.Pack(), products)
+
+		assert.Equal(t, approximate, res.Approximate)
+		assert.Equal(t, float64(amount)*0.5, res.Price)
+	}
+}
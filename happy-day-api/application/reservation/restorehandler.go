@@ -0,0 +1,17 @@
+package reservation
+
+import (
+	"context"
+
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+)
+
+type RestoreHandler struct {
+	repository infrastructure.ReservationRepository
+}
+
+func (handler RestoreHandler) Handle(ctx context.Context, req uuid.UUID) error {
+	return handler.repository.Restore(ctx, req)
+}
@@ -0,0 +1,34 @@
+package reservation
+
+import (
+	"context"
+	"testing"
+
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRestoreReservationHandlerWhenNotFound(t *testing.T) {
+	repo := &infrastructure.MockReservationRepository{}
+	repo.
+		On("Restore", mock.Anything, mock.Anything).
+		Return(infrastructure.ErrReservationNotFound)
+
+	handler := RestoreHandler{repository: repo}
+	err := handler.Handle(context.Background(), uuid.New())
+	assert.Equal(t, infrastructure.ErrReservationNotFound, err)
+}
+
+func TestRestoreReservationHandler(t *testing.T) {
+	repo := &infrastructure.MockReservationRepository{}
+	repo.
+		On("Restore", mock.Anything, mock.Anything).
+		Return(nil)
+
+	handler := RestoreHandler{repository: repo}
+	err := handler.Handle(context.Background(), uuid.New())
+	assert.Nil(t, err)
+}
@@ -0,0 +1,70 @@
+package reservation
+
+import (
+	"context"
+
+	"happy_day/domain/reservation"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+)
+
+type (
+	RevertRequest struct {
+		Id         uuid.UUID
+		RevisionId uuid.UUID
+	}
+
+	RevertHandler struct {
+		reservationOptions    infrastructure.ReservationOptions
+		customerRepository    infrastructure.CustomerRepository
+		productRepository     infrastructure.ProductRepository
+		reservationRepository infrastructure.ReservationRepository
+		staffRepository       infrastructure.StaffRepository
+	}
+)
+
+func (handler RevertHandler) Handle(ctx context.Context, req RevertRequest) (reservation.State, error) {
+	current, err := handler.reservationRepository.GetById(ctx, req.Id)
+	if err != nil {
+		return current, err
+	}
+
+	state, err := handler.reservationRepository.GetRevision(ctx, req.Id, req.RevisionId)
+	if err != nil {
+		return current, err
+	}
+
+	state.Id = current.Id
+	state.Status = current.Status
+	state.StatusHistory = current.StatusHistory
+	state.CreatedAt = current.CreatedAt
+	state.ModifiedAt = current.ModifiedAt
+
+	// A revision goes through the same checks as a change, the staff or the schedule may no longer be free
+	err = validateDetails(state.PaymentInstallments, state.CustomerId, state.Customer, state.Address)
+	if err != nil {
+		return current, err
+	}
+
+	if currentStatus(state) != reservation.Draft || !state.Delivery.At.IsZero() || !state.PickUp.At.IsZero() {
+		err = validateSchedule(handler.reservationOptions, current, state.Delivery, state.PickUp)
+		if err != nil {
+			return current, err
+		}
+	}
+
+	// Payments are only recorded through the pay handler, a revision never undoes them
+	state.PaymentInstallments, err = mergeInstallments(current.PaymentInstallments, state.PaymentInstallments)
+	if err != nil {
+		return current, err
+	}
+
+	err = ensureCrew(ctx, handler.reservationOptions, handler.staffRepository, handler.reservationRepository, current, &state)
+	if err != nil {
+		return current, err
+	}
+
+	return saveDetails(ctx, handler.customerRepository, handler.productRepository, handler.reservationRepository,
+		state, state.CustomerId, state.Customer)
+}
@@ -0,0 +1,180 @@
+package reservation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"happy_day/common"
+	"happy_day/domain/customer"
+	"happy_day/domain/reservation"
+	"happy_day/domain/staff"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRevertReservationWhenNotFound(t *testing.T) {
+	req := RevertRequest{Id: uuid.New(), RevisionId: uuid.New()}
+	reservationRepo := &infrastructure.MockReservationRepository{}
+	reservationRepo.
+		On("GetById", mock.Anything, req.Id).
+		Return(reservation.State{}, infrastructure.ErrReservationNotFound)
+
+	handler := RevertHandler{reservationRepository: reservationRepo}
+	_, err := handler.Handle(context.Background(), req)
+	assert.Equal(t, infrastructure.ErrReservationNotFound, err)
+}
+
+func TestRevertReservationKeepCurrentStatus(t *testing.T) {
+	req := RevertRequest{Id: uuid.New(), RevisionId: uuid.New()}
+	current := newConfirmableState(reservation.Confirmed)
+	current.Id = req.Id
+	current.Discount = 50
+	current.FinalPrice = 50
+	current.StatusHistory = []reservation.StatusChange{
+		{Status: reservation.Draft},
+		{Status: reservation.Confirmed},
+	}
+	current.ModifiedAt = time.Now()
+
+	revision := current
+	revision.Discount = 0
+	revision.Price = 100
+	revision.FinalPrice = 100
+	revision.Status = reservation.Draft
+	revision.StatusHistory = []reservation.StatusChange{{Status: reservation.Draft}}
+	revision.ModifiedAt = time.Time{}
+
+	var saved reservation.State
+	reservationRepo := &infrastructure.MockReservationRepository{}
+	reservationRepo.
+		On("GetById", mock.Anything, req.Id).
+		Return(current, nil)
+	reservationRepo.
+		On("GetRevision", mock.Anything, req.Id, req.RevisionId).
+		Return(revision, nil)
+	reservationRepo.
+		On("Save", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			saved = args.Get(1).(reservation.State)
+		}).
+		Return(reservation.State{}, nil)
+
+	handler := newRevertHandler(reservationRepo, nil)
+	_, err := handler.Handle(context.Background(), req)
+	assert.Nil(t, err)
+	assert.Equal(t, reservation.Confirmed, saved.Status)
+	assert.Len(t, saved.StatusHistory, 2)
+	assert.Equal(t, float64(100), saved.FinalPrice)
+	assert.Equal(t, float64(100), saved.BalanceDue)
+	assert.Equal(t, current.ModifiedAt, saved.ModifiedAt)
+}
+
+func TestRevertReservationKeepCurrentPayments(t *testing.T) {
+	req := RevertRequest{Id: uuid.New(), RevisionId: uuid.New()}
+	paidAt := time.Now().UTC()
+	current := newConfirmableState(reservation.Confirmed)
+	current.Id = req.Id
+	current.FinalPrice = 100
+	current.PaymentInstallments = []reservation.PaymentInstallment{
+		{Amount: 50, Method: reservation.Pix, Status: reservation.Paid, PaidAt: &paidAt},
+		{Amount: 50, Method: reservation.Cash, Status: reservation.Scheduled},
+	}
+
+	revision := current
+	revision.PaymentInstallments = []reservation.PaymentInstallment{
+		{Amount: 50, Method: reservation.Pix, Status: reservation.Scheduled},
+		{Amount: 50, Method: reservation.Cash, Status: reservation.Paid, PaidAt: &paidAt},
+	}
+
+	var saved reservation.State
+	reservationRepo := &infrastructure.MockReservationRepository{}
+	reservationRepo.
+		On("GetById", mock.Anything, req.Id).
+		Return(current, nil)
+	reservationRepo.
+		On("GetRevision", mock.Anything, req.Id, req.RevisionId).
+		Return(revision, nil)
+	reservationRepo.
+		On("Save", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			saved = args.Get(1).(reservation.State)
+		}).
+		Return(reservation.State{}, nil)
+
+	handler := newRevertHandler(reservationRepo, nil)
+	_, err := handler.Handle(context.Background(), req)
+	assert.Nil(t, err)
+	assert.Equal(t, reservation.Paid, saved.PaymentInstallments[0].Status)
+	assert.Equal(t, reservation.Scheduled, saved.PaymentInstallments[1].Status)
+	assert.Nil(t, saved.PaymentInstallments[1].PaidAt)
+	assert.Equal(t, float64(50), saved.AmountPaid)
+	assert.Equal(t, float64(50), saved.BalanceDue)
+}
+
+func TestRevertReservationWhenInstallmentsMismatchFinalPrice(t *testing.T) {
+	req := RevertRequest{Id: uuid.New(), RevisionId: uuid.New()}
+	current := newConfirmableState(reservation.Confirmed)
+	current.Id = req.Id
+	current.FinalPrice = 100
+
+	revision := current
+	revision.FinalPrice = 80
+	revision.PaymentInstallments = []reservation.PaymentInstallment{{Amount: 100, Method: reservation.Pix}}
+
+	reservationRepo := &infrastructure.MockReservationRepository{}
+	reservationRepo.
+		On("GetById", mock.Anything, req.Id).
+		Return(current, nil)
+	reservationRepo.
+		On("GetRevision", mock.Anything, req.Id, req.RevisionId).
+		Return(revision, nil)
+
+	handler := newRevertHandler(reservationRepo, nil)
+	_, err := handler.Handle(context.Background(), req)
+	assert.Equal(t, infrastructure.ErrReservationInstallmentsMismatch, err)
+	reservationRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestRevertReservationWhenStaffIsInactive(t *testing.T) {
+	req := RevertRequest{Id: uuid.New(), RevisionId: uuid.New()}
+	current := newConfirmableState(reservation.Confirmed)
+	current.Id = req.Id
+
+	revision := current
+	revision.Delivery.By = []uuid.UUID{uuid.New()}
+
+	reservationRepo := &infrastructure.MockReservationRepository{}
+	reservationRepo.
+		On("GetById", mock.Anything, req.Id).
+		Return(current, nil)
+	reservationRepo.
+		On("GetRevision", mock.Anything, req.Id, req.RevisionId).
+		Return(revision, nil)
+
+	staffRepo := &infrastructure.MockStaffRepository{}
+	staffRepo.
+		On("GetByIds", mock.Anything, revision.Delivery.By).
+		Return([]staff.State{{Id: revision.Delivery.By[0], Active: false}}, nil)
+
+	handler := newRevertHandler(reservationRepo, staffRepo)
+	_, err := handler.Handle(context.Background(), req)
+	assert.Equal(t, infrastructure.ErrStaffIsInactive, err)
+	reservationRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func newRevertHandler(reservationRepo *infrastructure.MockReservationRepository, staffRepo *infrastructure.MockStaffRepository) RevertHandler {
+	customerRepo := &infrastructure.MockCustomerRepository{}
+	customerRepo.
+		On("GetById", mock.Anything, mock.Anything).
+		Return(customer.State{Id: uuid.New(), Name: common.RandString(10)}, nil)
+
+	return RevertHandler{
+		customerRepository:    customerRepo,
+		reservationRepository: reservationRepo,
+		staffRepository:       staffRepo,
+	}
+}
@@ -0,0 +1,70 @@
+package staff
+
+import (
+	"context"
+	"strings"
+
+	"happy_day/domain/staff"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+)
+
+type (
+	ChangeOrCreateRequest struct {
+		staff.State
+	}
+
+	ChangeOrCreateHandler struct {
+		repository            infrastructure.StaffRepository
+		reservationRepository infrastructure.ReservationRepository
+	}
+)
+
+func (handler ChangeOrCreateHandler) Handle(ctx context.Context, req ChangeOrCreateRequest) (staff.State, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	err := Validate(req.State)
+	if err != nil {
+		return req.State, err
+	}
+
+	if req.Id != uuid.Nil {
+		state, err := handler.repository.GetById(ctx, req.Id)
+		if err != nil {
+			return state, err
+		}
+
+		// A member with open assignments must be replaced on them before leaving the crew
+		if state.Active && !req.Active {
+			exists, err := handler.reservationRepository.ExistsOpenWithStaff(ctx, req.Id)
+			if err != nil {
+				return state, err
+			}
+
+			if exists {
+				return state, infrastructure.ErrStaffInUse
+			}
+		}
+
+		req.CreatedAt = state.CreatedAt
+		req.ModifiedAt = state.ModifiedAt
+	}
+
+	return handler.repository.Save(ctx, req.State)
+}
+
+func Validate(state staff.State) error {
+	if len(state.Name) == 0 {
+		return infrastructure.ErrStaffNameIsEmpty
+	}
+
+	if len(strings.TrimSpace(state.Phone)) == 0 {
+		return infrastructure.ErrStaffPhoneIsEmpty
+	}
+
+	if state.Role != staff.Driver && state.Role != staff.Helper {
+		return infrastructure.ErrStaffRoleIsInvalid
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package staff
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"happy_day/common"
+	"happy_day/domain/staff"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestChangeOrCreateStaffWhenInvalid(t *testing.T) {
+	tests := map[string]struct {
+		state    staff.State
+		expected error
+	}{
+		"name":  {staff.State{Name: "  ", Phone: "11987654321", Role: staff.Driver}, infrastructure.ErrStaffNameIsEmpty},
+		"phone": {staff.State{Name: common.RandString(10), Role: staff.Driver}, infrastructure.ErrStaffPhoneIsEmpty},
+		"role":  {staff.State{Name: common.RandString(10), Phone: "11987654321", Role: staff.Role(common.RandString(5))}, infrastructure.ErrStaffRoleIsInvalid},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			handler := ChangeOrCreateHandler{}
+			_, err := handler.Handle(context.Background(), ChangeOrCreateRequest{State: test.state})
+			assert.Equal(t, test.expected, err)
+		})
+	}
+}
+
+func TestChangeOrCreateStaffWhenNotFound(t *testing.T) {
+	req := ChangeOrCreateRequest{State: staff.State{
+		Id:    uuid.New(),
+		Name:  common.RandString(10),
+		Phone: "11987654321",
+		Role:  staff.Helper,
+	}}
+
+	repo := &infrastructure.MockStaffRepository{}
+	repo.
+		On("GetById", mock.Anything, req.Id).
+		Return(staff.State{}, infrastructure.ErrStaffNotFound)
+
+	handler := ChangeOrCreateHandler{repository: repo}
+	_, err := handler.Handle(context.Background(), req)
+
+	assert.Equal(t, infrastructure.ErrStaffNotFound, err)
+}
+
+func TestChangeOrCreateStaffWhenChanging(t *testing.T) {
+	req := ChangeOrCreateRequest{State: staff.State{
+		Id:    uuid.New(),
+		Name:  " " + common.RandString(10),
+		Phone: "11987654321",
+		Role:  staff.Driver,
+	}}
+
+	stored := staff.State{
+		Id:         req.Id,
+		CreatedAt:  time.Now().Add(-time.Hour),
+		ModifiedAt: time.Now(),
+	}
+
+	repo := &infrastructure.MockStaffRepository{}
+	repo.
+		On("GetById", mock.Anything, req.Id).
+		Return(stored, nil)
+	repo.
+		On("Save", mock.Anything, mock.MatchedBy(func(state staff.State) bool {
+			return state.Name == req.Name[1:] && state.CreatedAt.Equal(stored.CreatedAt) && state.ModifiedAt.Equal(stored.ModifiedAt)
+		})).
+		Return(staff.State{}, nil)
+
+	handler := ChangeOrCreateHandler{repository: repo}
+	_, err := handler.Handle(context.Background(), req)
+
+	assert.Nil(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestChangeOrCreateStaffWhenDeactivatingWithOpenReservations(t *testing.T) {
+	req := ChangeOrCreateRequest{State: staff.State{
+		Id:     uuid.New(),
+		Name:   common.RandString(10),
+		Phone:  "11987654321",
+		Role:   staff.Driver,
+		Active: false,
+	}}
+
+	repo := &infrastructure.MockStaffRepository{}
+	repo.
+		On("GetById", mock.Anything, req.Id).
+		Return(staff.State{Id: req.Id, Active: true}, nil)
+
+	reservationRepo := &infrastructure.MockReservationRepository{}
+	reservationRepo.
+		On("ExistsOpenWithStaff", mock.Anything, req.Id).
+		Return(true, nil)
+
+	handler := ChangeOrCreateHandler{repository: repo, reservationRepository: reservationRepo}
+	_, err := handler.Handle(context.Background(), req)
+
+	assert.Equal(t, infrastructure.ErrStaffInUse, err)
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestChangeOrCreateStaffWhenDeactivating(t *testing.T) {
+	req := ChangeOrCreateRequest{State: staff.State{
+		Id:     uuid.New(),
+		Name:   common.RandString(10),
+		Phone:  "11987654321",
+		Role:   staff.Driver,
+		Active: false,
+	}}
+
+	repo := &infrastructure.MockStaffRepository{}
+	repo.
+		On("GetById", mock.Anything, req.Id).
+		Return(staff.State{Id: req.Id, Active: true}, nil)
+	repo.
+		On("Save", mock.Anything, mock.MatchedBy(func(state staff.State) bool {
+			return !state.Active
+		})).
+		Return(staff.State{}, nil)
+
+	reservationRepo := &infrastructure.MockReservationRepository{}
+	reservationRepo.
+		On("ExistsOpenWithStaff", mock.Anything, req.Id).
+		Return(false, nil)
+
+	handler := ChangeOrCreateHandler{repository: repo, reservationRepository: reservationRepo}
+	_, err := handler.Handle(context.Background(), req)
+
+	assert.Nil(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestChangeOrCreateStaffWhenMigratedFromCrewName(t *testing.T) {
+	// The crew migration creates staff without knowing their phone
+	req := ChangeOrCreateRequest{State: staff.State{
+		Id:     uuid.New(),
+		Name:   common.RandString(10),
+		Phone:  infrastructure.MigratedStaffPhone,
+		Role:   staff.Driver,
+		Active: true,
+	}}
+
+	repo := &infrastructure.MockStaffRepository{}
+	repo.
+		On("GetById", mock.Anything, req.Id).
+		Return(staff.State{Id: req.Id, Active: true}, nil)
+	repo.
+		On("Save", mock.Anything, mock.Anything).
+		Return(staff.State{}, nil)
+
+	handler := ChangeOrCreateHandler{repository: repo}
+	_, err := handler.Handle(context.Background(), req)
+
+	assert.Nil(t, err)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,36 @@
+package staff
+
+import (
+	"context"
+
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+)
+
+type (
+	DeleteRequest struct {
+		Id    uuid.UUID
+		Force bool
+	}
+
+	DeleteHandler struct {
+		repository            infrastructure.StaffRepository
+		reservationRepository infrastructure.ReservationRepository
+	}
+)
+
+func (handler DeleteHandler) Handle(ctx context.Context, req DeleteRequest) error {
+	if !req.Force {
+		exists, err := handler.reservationRepository.ExistsOpenWithStaff(ctx, req.Id)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			return infrastructure.ErrStaffInUse
+		}
+	}
+
+	return handler.repository.Delete(ctx, req.Id)
+}
@@ -0,0 +1,44 @@
+package staff
+
+import (
+	"context"
+	"testing"
+
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDeleteStaffWhenExistOpenReservation(t *testing.T) {
+	req := DeleteRequest{Id: uuid.New()}
+
+	repo := &infrastructure.MockStaffRepository{}
+	reservationRepo := &infrastructure.MockReservationRepository{}
+	reservationRepo.
+		On("ExistsOpenWithStaff", mock.Anything, req.Id).
+		Return(true, nil)
+
+	handler := DeleteHandler{repository: repo, reservationRepository: reservationRepo}
+	err := handler.Handle(context.Background(), req)
+
+	assert.Equal(t, infrastructure.ErrStaffInUse, err)
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestDeleteStaffWhenForce(t *testing.T) {
+	req := DeleteRequest{Id: uuid.New(), Force: true}
+
+	repo := &infrastructure.MockStaffRepository{}
+	repo.
+		On("Delete", mock.Anything, req.Id).
+		Return(nil)
+
+	reservationRepo := &infrastructure.MockReservationRepository{}
+	handler := DeleteHandler{repository: repo, reservationRepository: reservationRepo}
+	err := handler.Handle(context.Background(), req)
+
+	assert.Nil(t, err)
+	reservationRepo.AssertNotCalled(t, "ExistsOpenWithStaff", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,24 @@
+package staff
+
+import (
+	"context"
+
+	"happy_day/domain/staff"
+	"happy_day/infrastructure"
+)
+
+type GetAllHandler struct {
+	repository infrastructure.StaffRepository
+}
+
+func (handler GetAllHandler) Handle(ctx context.Context, req infrastructure.StaffFilter) (infrastructure.Page[staff.State], error) {
+	if req.Size <= 0 {
+		req.Size = 50
+	}
+
+	if req.Page <= 1 {
+		req.Page = 1
+	}
+
+	return handler.repository.GetAll(ctx, req)
+}
@@ -0,0 +1,18 @@
+package staff
+
+import (
+	"context"
+
+	"happy_day/domain/staff"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+)
+
+type GetByIdHandler struct {
+	repository infrastructure.StaffRepository
+}
+
+func (handler GetByIdHandler) Handle(ctx context.Context, req uuid.UUID) (staff.State, error) {
+	return handler.repository.GetById(ctx, req)
+}
@@ -0,0 +1,57 @@
+package staff
+
+import (
+	"happy_day/infrastructure"
+
+	"github.com/google/wire"
+)
+
+var ProviderSet = wire.NewSet(
+	ProvideGetAllHandler,
+	ProvideGetByIdHandler,
+	ProvideChangeOrCreateHandler,
+	ProvideDeleteHandler,
+	ProvideRestoreHandler,
+	ProvideScheduleHandler,
+)
+
+func ProvideGetAllHandler(repository infrastructure.StaffRepository) GetAllHandler {
+	return GetAllHandler{repository: repository}
+}
+
+func ProvideGetByIdHandler(repository infrastructure.StaffRepository) GetByIdHandler {
+	return GetByIdHandler{repository: repository}
+}
+
+func ProvideChangeOrCreateHandler(
+	repository infrastructure.StaffRepository,
+	reservationRepository infrastructure.ReservationRepository) ChangeOrCreateHandler {
+	return ChangeOrCreateHandler{
+		repository:            repository,
+		reservationRepository: reservationRepository,
+	}
+}
+
+func ProvideDeleteHandler(
+	repository infrastructure.StaffRepository,
+	reservationRepository infrastructure.ReservationRepository) DeleteHandler {
+	return DeleteHandler{
+		repository:            repository,
+		reservationRepository: reservationRepository,
+	}
+}
+
+func ProvideRestoreHandler(repository infrastructure.StaffRepository) RestoreHandler {
+	return RestoreHandler{repository: repository}
+}
+
+func ProvideScheduleHandler(
+	options infrastructure.ReservationOptions,
+	repository infrastructure.StaffRepository,
+	reservationRepository infrastructure.ReservationRepository) ScheduleHandler {
+	return ScheduleHandler{
+		options:               options,
+		repository:            repository,
+		reservationRepository: reservationRepository,
+	}
+}
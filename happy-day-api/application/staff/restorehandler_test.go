@@ -0,0 +1,34 @@
+package staff
+
+import (
+	"context"
+	"testing"
+
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRestoreStaffHandlerWhenNotFound(t *testing.T) {
+	repo := &infrastructure.MockStaffRepository{}
+	repo.
+		On("Restore", mock.Anything, mock.Anything).
+		Return(infrastructure.ErrStaffNotFound)
+
+	handler := RestoreHandler{repository: repo}
+	err := handler.Handle(context.Background(), uuid.New())
+	assert.Equal(t, infrastructure.ErrStaffNotFound, err)
+}
+
+func TestRestoreStaffHandler(t *testing.T) {
+	repo := &infrastructure.MockStaffRepository{}
+	repo.
+		On("Restore", mock.Anything, mock.Anything).
+		Return(nil)
+
+	handler := RestoreHandler{repository: repo}
+	err := handler.Handle(context.Background(), uuid.New())
+	assert.Nil(t, err)
+}
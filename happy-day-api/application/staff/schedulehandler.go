@@ -0,0 +1,121 @@
+package staff
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"happy_day/common"
+	"happy_day/domain/reservation"
+	"happy_day/domain/staff"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+)
+
+const (
+	Delivery AssignmentKind = "delivery"
+	PickUp   AssignmentKind = "pickUp"
+
+	defaultScheduleDays = 7
+	maxScheduleDays     = 366
+)
+
+type (
+	AssignmentKind string
+
+	ScheduleRequest struct {
+		Id   uuid.UUID
+		From time.Time
+		To   time.Time
+	}
+
+	ScheduleResponse struct {
+		Staff       staff.State  `json:"staff"`
+		From        time.Time    `json:"from"`
+		To          time.Time    `json:"to"`
+		Assignments []Assignment `json:"assignments"`
+	}
+
+	Assignment struct {
+		ReservationId uuid.UUID           `json:"reservationId"`
+		Kind          AssignmentKind      `json:"kind"`
+		At            time.Time           `json:"at"`
+		Until         time.Time           `json:"until"`
+		Status        reservation.Status  `json:"status"`
+		Customer      string              `json:"customer"`
+		Address       reservation.Address `json:"address"`
+		Crew          []uuid.UUID         `json:"crew"`
+	}
+
+	ScheduleHandler struct {
+		options               infrastructure.ReservationOptions
+		repository            infrastructure.StaffRepository
+		reservationRepository infrastructure.ReservationRepository
+	}
+)
+
+// Handle lists the deliveries and pick ups of the staff in the period, by default the next seven days
+func (handler ScheduleHandler) Handle(ctx context.Context, req ScheduleRequest) (ScheduleResponse, error) {
+	if req.From.IsZero() {
+		req.From = handler.options.StartOfDay(time.Now())
+	}
+
+	if req.To.IsZero() {
+		req.To = req.From.AddDate(0, 0, defaultScheduleDays)
+	}
+
+	if !req.To.After(req.From) || req.To.After(req.From.AddDate(0, 0, maxScheduleDays)) {
+		return ScheduleResponse{}, infrastructure.ErrStaffSchedulePeriodIsInvalid
+	}
+
+	state, err := handler.repository.GetById(ctx, req.Id)
+	if err != nil {
+		return ScheduleResponse{}, err
+	}
+
+	reservations, err := handler.reservationRepository.GetByStaff(ctx, req.Id, req.From, req.To)
+	if err != nil {
+		return ScheduleResponse{}, err
+	}
+
+	res := ScheduleResponse{
+		Staff:       state,
+		From:        req.From,
+		To:          req.To,
+		Assignments: make([]Assignment, 0),
+	}
+
+	for _, item := range reservations {
+		if handler.assigned(req, item.Delivery) {
+			res.Assignments = append(res.Assignments, handler.assignment(item, Delivery, item.Delivery))
+		}
+
+		if handler.assigned(req, item.PickUp) {
+			res.Assignments = append(res.Assignments, handler.assignment(item, PickUp, item.PickUp))
+		}
+	}
+
+	sort.SliceStable(res.Assignments, func(i, j int) bool {
+		return res.Assignments[i].At.Before(res.Assignments[j].At)
+	})
+
+	return res, nil
+}
+
+func (handler ScheduleHandler) assigned(req ScheduleRequest, item reservation.DeliveryOrPickUp) bool {
+	return common.Contains(item.By, req.Id) && !item.At.Before(req.From) && item.At.Before(req.To)
+}
+
+func (handler ScheduleHandler) assignment(state reservation.State, kind AssignmentKind, item reservation.DeliveryOrPickUp) Assignment {
+	return Assignment{
+		ReservationId: state.Id,
+		Kind:          kind,
+		At:            item.At,
+		Until:         item.At.Add(handler.options.AssignmentDuration),
+		Status:        state.Status,
+		Customer:      state.Customer.Name,
+		Address:       state.Address,
+		Crew:          item.By,
+	}
+}
@@ -0,0 +1,111 @@
+package staff
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"happy_day/domain/reservation"
+	"happy_day/domain/staff"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestScheduleWhenPeriodIsInvalid(t *testing.T) {
+	from := time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC)
+	periods := map[string]ScheduleRequest{
+		"reversed": {Id: uuid.New(), From: from, To: from.Add(-time.Hour)},
+		"tooLong":  {Id: uuid.New(), From: from, To: from.AddDate(2, 0, 0)},
+	}
+
+	for name, req := range periods {
+		t.Run(name, func(t *testing.T) {
+			_, err := ScheduleHandler{}.Handle(context.Background(), req)
+			assert.Equal(t, infrastructure.ErrStaffSchedulePeriodIsInvalid, err)
+		})
+	}
+}
+
+func TestScheduleWhenPeriodIsEmpty(t *testing.T) {
+	location, err := time.LoadLocation("America/Sao_Paulo")
+	assert.Nil(t, err)
+
+	member := staff.State{Id: uuid.New()}
+	repo := &infrastructure.MockStaffRepository{}
+	repo.
+		On("GetById", mock.Anything, member.Id).
+		Return(member, nil)
+
+	reservationRepo := &infrastructure.MockReservationRepository{}
+	reservationRepo.
+		On("GetByStaff", mock.Anything, member.Id, mock.Anything, mock.Anything).
+		Return([]reservation.State{}, nil)
+
+	handler := ScheduleHandler{
+		options:               infrastructure.ReservationOptions{Location: location},
+		repository:            repo,
+		reservationRepository: reservationRepo,
+	}
+	res, err := handler.Handle(context.Background(), ScheduleRequest{Id: member.Id})
+
+	assert.Nil(t, err)
+	assert.Empty(t, res.Assignments)
+	assert.Equal(t, 0, res.From.In(location).Hour())
+	assert.Equal(t, res.From.AddDate(0, 0, 7), res.To)
+}
+
+func TestScheduleWhenStaffHasDeliveriesAndPickUps(t *testing.T) {
+	member := staff.State{Id: uuid.New(), Name: "Ana"}
+	from := time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+
+	repo := &infrastructure.MockStaffRepository{}
+	repo.
+		On("GetById", mock.Anything, member.Id).
+		Return(member, nil)
+
+	first := reservation.State{
+		Id:       uuid.New(),
+		Delivery: reservation.DeliveryOrPickUp{At: from.Add(14 * time.Hour), By: []uuid.UUID{member.Id}},
+		PickUp:   reservation.DeliveryOrPickUp{At: from.Add(20 * time.Hour), By: []uuid.UUID{member.Id}},
+	}
+	second := reservation.State{
+		Id:       uuid.New(),
+		Delivery: reservation.DeliveryOrPickUp{At: from.Add(-20 * time.Hour), By: []uuid.UUID{member.Id}},
+		PickUp:   reservation.DeliveryOrPickUp{At: from.Add(9 * time.Hour), By: []uuid.UUID{member.Id, uuid.New()}},
+	}
+	third := reservation.State{
+		Id:       uuid.New(),
+		Delivery: reservation.DeliveryOrPickUp{At: from.Add(12 * time.Hour), By: []uuid.UUID{uuid.New()}},
+		PickUp:   reservation.DeliveryOrPickUp{At: from.Add(16 * time.Hour), By: []uuid.UUID{member.Id}},
+	}
+
+	reservationRepo := &infrastructure.MockReservationRepository{}
+	reservationRepo.
+		On("GetByStaff", mock.Anything, member.Id, from, to).
+		Return([]reservation.State{first, second, third}, nil)
+
+	handler := ScheduleHandler{
+		options:               infrastructure.ReservationOptions{AssignmentDuration: time.Hour},
+		repository:            repo,
+		reservationRepository: reservationRepo,
+	}
+	res, err := handler.Handle(context.Background(), ScheduleRequest{Id: member.Id, From: from, To: to})
+
+	assert.Nil(t, err)
+	assert.Equal(t, member, res.Staff)
+	assert.Len(t, res.Assignments, 4)
+
+	expected := []struct {
+		id   uuid.UUID
+		kind AssignmentKind
+	}{{second.Id, PickUp}, {first.Id, Delivery}, {third.Id, PickUp}, {first.Id, PickUp}}
+	for i, item := range expected {
+		assert.Equal(t, item.id, res.Assignments[i].ReservationId)
+		assert.Equal(t, item.kind, res.Assignments[i].Kind)
+		assert.Equal(t, res.Assignments[i].At.Add(time.Hour), res.Assignments[i].Until)
+	}
+}
@@ -0,0 +1,160 @@
+package trash
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"happy_day/domain/customer"
+	"happy_day/domain/product"
+	"happy_day/domain/reservation"
+	"happy_day/domain/staff"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+)
+
+const (
+	Customer    Kind = "customer"
+	Product     Kind = "product"
+	Reservation Kind = "reservation"
+	Staff       Kind = "staff"
+)
+
+type (
+	Kind string
+
+	GetAllRequest struct {
+		Kind Kind
+	}
+
+	Item struct {
+		Id        uuid.UUID `json:"id"`
+		Kind      Kind      `json:"kind"`
+		Name      string    `json:"name"`
+		DeletedAt time.Time `json:"deletedAt"`
+		DeletedBy string    `json:"deletedBy"`
+	}
+
+	GetAllHandler struct {
+		customerRepository    infrastructure.CustomerRepository
+		productRepository     infrastructure.ProductRepository
+		reservationRepository infrastructure.ReservationRepository
+		staffRepository       infrastructure.StaffRepository
+	}
+)
+
+// IsValid accepts the empty kind, which lists every kind
+func (kind Kind) IsValid() bool {
+	return kind == "" || kind == Customer || kind == Product || kind == Reservation || kind == Staff
+}
+
+func (handler GetAllHandler) Handle(ctx context.Context, req GetAllRequest) ([]Item, error) {
+	if !req.Kind.IsValid() {
+		return nil, infrastructure.ErrTrashKindIsInvalid
+	}
+
+	items := make([]Item, 0)
+	if req.Kind == "" || req.Kind == Customer {
+		customers, err := handler.customerRepository.GetDeleted(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, state := range customers {
+			items = append(items, customerItem(state))
+		}
+	}
+
+	if req.Kind == "" || req.Kind == Product {
+		products, err := handler.productRepository.GetDeleted(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, state := range products {
+			items = append(items, productItem(state))
+		}
+	}
+
+	if req.Kind == "" || req.Kind == Reservation {
+		reservations, err := handler.reservationRepository.GetDeleted(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, state := range reservations {
+			items = append(items, reservationItem(state))
+		}
+	}
+
+	if req.Kind == "" || req.Kind == Staff {
+		members, err := handler.staffRepository.GetDeleted(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, state := range members {
+			items = append(items, staffItem(state))
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].DeletedAt.After(items[j].DeletedAt)
+	})
+
+	return items, nil
+}
+
+func customerItem(state customer.State) Item {
+	return Item{
+		Id:        state.Id,
+		Kind:      Customer,
+		Name:      state.Name,
+		DeletedAt: deletedAt(state.DeletedAt),
+		DeletedBy: state.DeletedBy,
+	}
+}
+
+func productItem(state product.State) Item {
+	return Item{
+		Id:        state.Id,
+		Kind:      Product,
+		Name:      state.Name,
+		DeletedAt: deletedAt(state.DeletedAt),
+		DeletedBy: state.DeletedBy,
+	}
+}
+
+func reservationItem(state reservation.State) Item {
+	name := state.Customer.Name
+	if !state.Delivery.At.IsZero() {
+		name += " - " + state.Delivery.At.Format("2006-01-02")
+	}
+
+	return Item{
+		Id:        state.Id,
+		Kind:      Reservation,
+		Name:      name,
+		DeletedAt: deletedAt(state.DeletedAt),
+		DeletedBy: state.DeletedBy,
+	}
+}
+
+func staffItem(state staff.State) Item {
+	return Item{
+		Id:        state.Id,
+		Kind:      Staff,
+		Name:      state.Name,
+		DeletedAt: deletedAt(state.DeletedAt),
+		DeletedBy: state.DeletedBy,
+	}
+}
+
+func deletedAt(at *time.Time) time.Time {
+	if at == nil {
+		return time.Time{}
+	}
+
+	return *at
+}
@@ -0,0 +1,94 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"happy_day/common"
+	"happy_day/domain/customer"
+	"happy_day/domain/product"
+	"happy_day/domain/reservation"
+	"happy_day/domain/staff"
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetAllTrashWhenErrToGetDeletedCustomers(t *testing.T) {
+	expectedErr := errors.New(common.RandString(10))
+	customerRepo := &infrastructure.MockCustomerRepository{}
+	customerRepo.
+		On("GetDeleted", mock.Anything).
+		Return([]customer.State{}, expectedErr)
+
+	handler := GetAllHandler{customerRepository: customerRepo}
+	_, err := handler.Handle(context.Background(), GetAllRequest{})
+	assert.Equal(t, expectedErr, err)
+}
+
+func TestGetAllTrashWhenKindIsInvalid(t *testing.T) {
+	handler := GetAllHandler{}
+	_, err := handler.Handle(context.Background(), GetAllRequest{Kind: Kind(common.RandString(10))})
+	assert.Equal(t, infrastructure.ErrTrashKindIsInvalid, err)
+}
+
+func TestGetAllTrashWhenFilterByKind(t *testing.T) {
+	deletedAt := time.Now().UTC()
+	productRepo := &infrastructure.MockProductRepository{}
+	productRepo.
+		On("GetDeleted", mock.Anything).
+		Return([]product.State{{Id: uuid.New(), Name: common.RandString(10), DeletedAt: &deletedAt}}, nil)
+
+	handler := GetAllHandler{productRepository: productRepo}
+	res, err := handler.Handle(context.Background(), GetAllRequest{Kind: Product})
+	assert.Nil(t, err)
+	assert.Len(t, res, 1)
+	assert.Equal(t, Product, res[0].Kind)
+}
+
+func TestGetAllTrash(t *testing.T) {
+	now := time.Now().UTC()
+	older := now.Add(-time.Hour)
+	oldest := now.Add(-2 * time.Hour)
+
+	customerRepo := &infrastructure.MockCustomerRepository{}
+	customerRepo.
+		On("GetDeleted", mock.Anything).
+		Return([]customer.State{{Id: uuid.New(), Name: common.RandString(10), DeletedAt: &older, DeletedBy: "alice"}}, nil)
+
+	productRepo := &infrastructure.MockProductRepository{}
+	productRepo.
+		On("GetDeleted", mock.Anything).
+		Return([]product.State{{Id: uuid.New(), Name: common.RandString(10), DeletedAt: &oldest}}, nil)
+
+	reservationRepo := &infrastructure.MockReservationRepository{}
+	reservationRepo.
+		On("GetDeleted", mock.Anything).
+		Return([]reservation.State{{Id: uuid.New(), DeletedAt: &now}}, nil)
+
+	deletedMember := now.Add(-3 * time.Hour)
+	staffRepo := &infrastructure.MockStaffRepository{}
+	staffRepo.
+		On("GetDeleted", mock.Anything).
+		Return([]staff.State{{Id: uuid.New(), Name: common.RandString(10), DeletedAt: &deletedMember}}, nil)
+
+	handler := GetAllHandler{
+		customerRepository:    customerRepo,
+		productRepository:     productRepo,
+		reservationRepository: reservationRepo,
+		staffRepository:       staffRepo,
+	}
+
+	res, err := handler.Handle(context.Background(), GetAllRequest{})
+	assert.Nil(t, err)
+	assert.Len(t, res, 4)
+	assert.Equal(t, Reservation, res[0].Kind)
+	assert.Equal(t, Customer, res[1].Kind)
+	assert.Equal(t, "alice", res[1].DeletedBy)
+	assert.Equal(t, Product, res[2].Kind)
+	assert.Equal(t, Staff, res[3].Kind)
+}
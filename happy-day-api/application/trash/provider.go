@@ -0,0 +1,40 @@
+package trash
+
+import (
+	"happy_day/infrastructure"
+
+	"github.com/google/wire"
+)
+
+var ProviderSet = wire.NewSet(
+	ProvideGetAllHandler,
+	ProvidePurgeHandler,
+)
+
+func ProvideGetAllHandler(
+	customerRepository infrastructure.CustomerRepository,
+	productRepository infrastructure.ProductRepository,
+	reservationRepository infrastructure.ReservationRepository,
+	staffRepository infrastructure.StaffRepository) GetAllHandler {
+	return GetAllHandler{
+		customerRepository:    customerRepository,
+		productRepository:     productRepository,
+		reservationRepository: reservationRepository,
+		staffRepository:       staffRepository,
+	}
+}
+
+func ProvidePurgeHandler(
+	options infrastructure.TrashOptions,
+	customerRepository infrastructure.CustomerRepository,
+	productRepository infrastructure.ProductRepository,
+	reservationRepository infrastructure.ReservationRepository,
+	staffRepository infrastructure.StaffRepository) PurgeHandler {
+	return PurgeHandler{
+		options:               options,
+		customerRepository:    customerRepository,
+		productRepository:     productRepository,
+		reservationRepository: reservationRepository,
+		staffRepository:       staffRepository,
+	}
+}
@@ -0,0 +1,62 @@
+package trash
+
+import (
+	"context"
+	"time"
+
+	"happy_day/infrastructure"
+)
+
+type (
+	PurgeRequest struct {
+		Before time.Time
+	}
+
+	PurgeResponse struct {
+		Before       time.Time `json:"before"`
+		Customers    int64     `json:"customers"`
+		Products     int64     `json:"products"`
+		Reservations int64     `json:"reservations"`
+		Staff        int64     `json:"staff"`
+	}
+
+	PurgeHandler struct {
+		options               infrastructure.TrashOptions
+		customerRepository    infrastructure.CustomerRepository
+		productRepository     infrastructure.ProductRepository
+		reservationRepository infrastructure.ReservationRepository
+		staffRepository       infrastructure.StaffRepository
+	}
+)
+
+func (handler PurgeHandler) Handle(ctx context.Context, req PurgeRequest) (PurgeResponse, error) {
+	if req.Before.IsZero() {
+		req.Before = time.Now().UTC().Add(-handler.options.Retention)
+	}
+
+	var err error
+	res := PurgeResponse{Before: req.Before}
+	res.Reservations, err = handler.reservationRepository.Purge(ctx, req.Before)
+	if err != nil {
+		return res, err
+	}
+
+	// Customers, products and staff still referenced by a reservation stay in the trash
+	references, err := handler.reservationRepository.GetReferences(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	res.Customers, err = handler.customerRepository.Purge(ctx, req.Before, references.Customers)
+	if err != nil {
+		return res, err
+	}
+
+	res.Products, err = handler.productRepository.Purge(ctx, req.Before, references.Products)
+	if err != nil {
+		return res, err
+	}
+
+	res.Staff, err = handler.staffRepository.Purge(ctx, req.Before, references.Staff)
+	return res, err
+}
@@ -0,0 +1,101 @@
+package trash
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"happy_day/infrastructure"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPurgeTrashWhenBeforeIsEmpty(t *testing.T) {
+	customerRepo := &infrastructure.MockCustomerRepository{}
+	customerRepo.
+		On("Purge", mock.Anything, mock.Anything, mock.Anything).
+		Return(int64(1), nil)
+
+	productRepo := &infrastructure.MockProductRepository{}
+	productRepo.
+		On("Purge", mock.Anything, mock.Anything, mock.Anything).
+		Return(int64(2), nil)
+
+	reservationRepo := &infrastructure.MockReservationRepository{}
+	reservationRepo.
+		On("Purge", mock.Anything, mock.Anything).
+		Return(int64(3), nil)
+
+	staffRepo := &infrastructure.MockStaffRepository{}
+	staffRepo.
+		On("Purge", mock.Anything, mock.Anything, mock.Anything).
+		Return(int64(4), nil)
+	reservationRepo.
+		On("GetReferences", mock.Anything).
+		Return(infrastructure.ReservationReferences{}, nil)
+
+	handler := PurgeHandler{
+		options:               infrastructure.TrashOptions{Retention: 24 * time.Hour},
+		customerRepository:    customerRepo,
+		productRepository:     productRepo,
+		reservationRepository: reservationRepo,
+		staffRepository:       staffRepo,
+	}
+
+	res, err := handler.Handle(context.Background(), PurgeRequest{})
+	assert.Nil(t, err)
+	assert.WithinDuration(t, time.Now().UTC().Add(-24*time.Hour), res.Before, time.Minute)
+	assert.Equal(t, int64(1), res.Customers)
+	assert.Equal(t, int64(2), res.Products)
+	assert.Equal(t, int64(3), res.Reservations)
+	assert.Equal(t, int64(4), res.Staff)
+}
+
+func TestPurgeTrash(t *testing.T) {
+	before := time.Now().UTC().AddDate(0, 0, -7)
+	references := infrastructure.ReservationReferences{
+		Customers: []uuid.UUID{uuid.New()},
+		Products:  []uuid.UUID{uuid.New(), uuid.New()},
+		Staff:     []uuid.UUID{uuid.New()},
+	}
+
+	customerRepo := &infrastructure.MockCustomerRepository{}
+	customerRepo.
+		On("Purge", mock.Anything, before, references.Customers).
+		Return(int64(0), nil)
+
+	productRepo := &infrastructure.MockProductRepository{}
+	productRepo.
+		On("Purge", mock.Anything, before, references.Products).
+		Return(int64(0), nil)
+
+	reservationRepo := &infrastructure.MockReservationRepository{}
+	reservationRepo.
+		On("Purge", mock.Anything, before).
+		Return(int64(0), nil)
+
+	staffRepo := &infrastructure.MockStaffRepository{}
+	staffRepo.
+		On("Purge", mock.Anything, before, references.Staff).
+		Return(int64(0), nil)
+	reservationRepo.
+		On("GetReferences", mock.Anything).
+		Return(references, nil)
+
+	handler := PurgeHandler{
+		customerRepository:    customerRepo,
+		productRepository:     productRepo,
+		reservationRepository: reservationRepo,
+		staffRepository:       staffRepo,
+	}
+
+	res, err := handler.Handle(context.Background(), PurgeRequest{Before: before})
+	assert.Nil(t, err)
+	assert.Equal(t, before, res.Before)
+	customerRepo.AssertExpectations(t)
+	productRepo.AssertExpectations(t)
+	reservationRepo.AssertExpectations(t)
+	staffRepo.AssertExpectations(t)
+}
@@ -39,3 +39,31 @@ func First[TSource any](source []TSource, filter func(TSource) bool, defaultValu
 
 	return defaultValue, false
 }
+
+func Any[TSource any](source []TSource, filter func(TSource) bool) bool {
+	for _, item := range source {
+		if filter(item) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func Contains[TSource comparable](source []TSource, value TSource) bool {
+	return Any(source, func(item TSource) bool {
+		return item == value
+	})
+}
+
+func Distinct[TSource comparable](source []TSource) []TSource {
+	seen := map[TSource]bool{}
+	return Filter(source, func(item TSource) bool {
+		if seen[item] {
+			return false
+		}
+
+		seen[item] = true
+		return true
+	})
+}
@@ -8,12 +8,14 @@ import (
 
 type (
 	State struct {
-		Id         uuid.UUID `json:"id,omitempty" bson:"id"`
-		Name       string    `json:"name" bson:"name"`
-		Comment    string    `json:"comment,omitempty" bson:"comment"`
-		Phones     []Phone   `json:"phones" bson:"phones"`
-		CreatedAt  time.Time `json:"createdAt" bson:"createdAt"`
-		ModifiedAt time.Time `json:"modifiedAt" bson:"modifiedAt"`
+		Id         uuid.UUID  `json:"id,omitempty" bson:"id"`
+		Name       string     `json:"name" bson:"name"`
+		Comment    string     `json:"comment,omitempty" bson:"comment"`
+		Phones     []Phone    `json:"phones" bson:"phones"`
+		CreatedAt  time.Time  `json:"createdAt" bson:"createdAt"`
+		ModifiedAt time.Time  `json:"modifiedAt" bson:"modifiedAt"`
+		DeletedAt  *time.Time `json:"deletedAt,omitempty" bson:"deletedAt,omitempty"`
+		DeletedBy  string     `json:"deletedBy,omitempty" bson:"deletedBy,omitempty"`
 	}
 
 	Phone struct {
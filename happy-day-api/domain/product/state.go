@@ -8,12 +8,15 @@ import (
 
 type (
 	State struct {
-		Id         uuid.UUID `bson:"id" json:"id,omitempty"`
-		Name       string    `bson:"name" json:"name"`
-		Price      float64   `bson:"price" json:"price"`
-		Products   []Product `bson:"products,omitempty" json:"products"`
-		CreatedAt  time.Time `bson:"createdAt" json:"createdAt"`
-		ModifiedAt time.Time `bson:"modifiedAt" json:"modifiedAt"`
+		Id         uuid.UUID  `bson:"id" json:"id,omitempty"`
+		Name       string     `bson:"name" json:"name"`
+		Price      float64    `bson:"price" json:"price"`
+		Stock      int64      `bson:"stock" json:"stock"`
+		Products   []Product  `bson:"products,omitempty" json:"products"`
+		CreatedAt  time.Time  `bson:"createdAt" json:"createdAt"`
+		ModifiedAt time.Time  `bson:"modifiedAt" json:"modifiedAt"`
+		DeletedAt  *time.Time `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
+		DeletedBy  string     `bson:"deletedBy,omitempty" json:"deletedBy,omitempty"`
 	}
 
 	Product struct {
@@ -11,6 +11,16 @@ const (
 	Pix          PaymentMethod = "pix"
 	BankTransfer PaymentMethod = "bankTransfer"
 	Cash         PaymentMethod = "cash"
+
+	Draft     Status = "draft"
+	Confirmed Status = "confirmed"
+	Delivered Status = "delivered"
+	PickedUp  Status = "pickedUp"
+	Closed    Status = "closed"
+	Cancelled Status = "cancelled"
+
+	Scheduled InstallmentStatus = "scheduled"
+	Paid      InstallmentStatus = "paid"
 )
 
 type (
@@ -19,15 +29,22 @@ type (
 		Price               float64              `bson:"price" json:"price"`
 		Discount            float64              `bson:"discount" json:"discount"`
 		FinalPrice          float64              `bson:"finalPrice" json:"finalPrice"`
+		AmountPaid          float64              `bson:"amountPaid" json:"amountPaid"`
+		BalanceDue          float64              `bson:"balanceDue" json:"balanceDue"`
 		Products            []Product            `bson:"products" json:"products"`
 		Delivery            DeliveryOrPickUp     `bson:"delivery" json:"delivery"`
 		PickUp              DeliveryOrPickUp     `bson:"pickUp" json:"pickUp"`
 		PaymentInstallments []PaymentInstallment `bson:"paymentInstallments" json:"paymentInstallments"`
 		Comment             string               `bson:"comment" json:"comment,omitempty"`
+		CustomerId          uuid.UUID            `bson:"customerId" json:"customerId"`
 		Customer            Customer             `bson:"customer" json:"customer"`
 		Address             Address              `bson:"address" json:"address"`
+		Status              Status               `bson:"status" json:"status"`
+		StatusHistory       []StatusChange       `bson:"statusHistory" json:"statusHistory"`
 		CreatedAt           time.Time            `bson:"createdAt" json:"createdAt"`
 		ModifiedAt          time.Time            `bson:"modifiedAt" json:"modifiedAt"`
+		DeletedAt           *time.Time           `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
+		DeletedBy           string               `bson:"deletedBy,omitempty" json:"deletedBy,omitempty"`
 	}
 
 	Product struct {
@@ -37,12 +54,12 @@ type (
 	}
 
 	DeliveryOrPickUp struct {
-		At time.Time `bson:"at" json:"at"`
-		By []string  `bson:"by" json:"by"`
+		At time.Time   `bson:"at" json:"at"`
+		By []uuid.UUID `bson:"by" json:"by"`
 	}
 
 	Customer struct {
-		customer.State
+		customer.State `bson:",inline"`
 	}
 
 	Address struct {
@@ -54,10 +71,19 @@ type (
 		City         string `bson:"city" json:"city"`
 	}
 
+	Status       string
+	StatusChange struct {
+		Status Status    `bson:"status" json:"status"`
+		At     time.Time `bson:"at" json:"at"`
+	}
+
 	PaymentMethod      string
+	InstallmentStatus  string
 	PaymentInstallment struct {
-		Amount float64       `bson:"amount" json:"amount"`
-		Method PaymentMethod `bson:"method" json:"method"`
-		At     time.Time     `bson:"at" json:"at"`
+		Amount float64           `bson:"amount" json:"amount"`
+		Method PaymentMethod     `bson:"method" json:"method"`
+		At     time.Time         `bson:"at" json:"at"`
+		Status InstallmentStatus `bson:"status" json:"status"`
+		PaidAt *time.Time        `bson:"paidAt,omitempty" json:"paidAt,omitempty"`
 	}
 )
@@ -0,0 +1,28 @@
+package staff
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	Driver Role = "driver"
+	Helper Role = "helper"
+)
+
+type (
+	Role string
+
+	State struct {
+		Id         uuid.UUID  `bson:"id" json:"id,omitempty"`
+		Name       string     `bson:"name" json:"name"`
+		Phone      string     `bson:"phone" json:"phone"`
+		Role       Role       `bson:"role" json:"role"`
+		Active     bool       `bson:"active" json:"active"`
+		CreatedAt  time.Time  `bson:"createdAt" json:"createdAt"`
+		ModifiedAt time.Time  `bson:"modifiedAt" json:"modifiedAt"`
+		DeletedAt  *time.Time `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
+		DeletedBy  string     `bson:"deletedBy,omitempty" json:"deletedBy,omitempty"`
+	}
+)
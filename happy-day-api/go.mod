@@ -5,11 +5,15 @@ go 1.19
 require (
 	github.com/google/uuid v1.3.0
 	github.com/google/wire v0.5.0
+	github.com/jung-kurt/gofpdf v1.16.2
 	github.com/labstack/echo/v4 v4.9.1
+	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
 	github.com/spf13/viper v1.14.0
 	github.com/stretchr/testify v1.8.1
+	go.etcd.io/bbolt v1.3.6
 	go.mongodb.org/mongo-driver v1.11.0
 	golang.org/x/net v0.2.0
+	golang.org/x/text v0.4.0
 )
 
 require (
@@ -44,7 +48,6 @@ require (
 	golang.org/x/crypto v0.3.0 // indirect
 	golang.org/x/sync v0.1.0 // indirect
 	golang.org/x/sys v0.2.0 // indirect
-	golang.org/x/text v0.4.0 // indirect
 	golang.org/x/time v0.2.0 // indirect
 	gopkg.in/ini.v1 v1.67.0 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
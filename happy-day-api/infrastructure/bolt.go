@@ -0,0 +1,483 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"happy_day/common"
+	"happy_day/domain/staff"
+
+	"github.com/google/uuid"
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var (
+	_ embeddedCollection[any] = (*boltCollection[any])(nil)
+
+	boltBuckets = []string{CustomersCollection, ProductCollection, ReservationCollection, StaffCollection, RevisionCollection}
+
+	ErrBoltDatabaseIsNotEmpty = errors.New("bolt database is not empty")
+)
+
+type boltCollection[T any] struct {
+	db     *bbolt.DB
+	name   string
+	fields func(state *T) embeddedFields
+}
+
+func OpenBoltDatabase(path string) (*bbolt.DB, error) {
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range boltBuckets {
+			if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+				return err
+			}
+		}
+
+		return replaceBoltCrewNames(tx)
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// replaceBoltCrewNames does for bolt files what the crew migration does for MongoDB, they were written with names before
+func replaceBoltCrewNames(tx *bbolt.Tx) error {
+	members := tx.Bucket([]byte(StaffCollection))
+	existing := make([]staff.State, 0)
+	var withoutPhone []staff.State
+	err := members.ForEach(func(_, raw []byte) error {
+		var state staff.State
+		if err := bson.UnmarshalWithRegistry(mongoDbRegistry, raw, &state); err != nil {
+			return err
+		}
+
+		// Staff created from crew names before they had a placeholder phone
+		if len(strings.TrimSpace(state.Phone)) == 0 {
+			state.Phone = MigratedStaffPhone
+			withoutPhone = append(withoutPhone, state)
+		}
+
+		if state.DeletedAt == nil {
+			existing = append(existing, state)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	crew := newCrewByName(existing)
+	reservations, err := boltCrewNames(tx.Bucket([]byte(ReservationCollection)), crew, func(raw []byte) (bson.Raw, error) {
+		return raw, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// The revert decodes old revisions, so they are converted as well
+	revisions, err := boltCrewNames(tx.Bucket([]byte(RevisionCollection)), crew, func(raw []byte) (bson.Raw, error) {
+		var revision Revision
+		if err := bson.UnmarshalWithRegistry(mongoDbRegistry, raw, &revision); err != nil {
+			return nil, err
+		}
+
+		if revision.Collection != ReservationCollection {
+			return nil, nil
+		}
+
+		return revision.State, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for key, raw := range reservations {
+		if err = tx.Bucket([]byte(ReservationCollection)).Put([]byte(key), raw); err != nil {
+			return err
+		}
+	}
+
+	for key, state := range revisions {
+		var revision Revision
+		if err = bson.UnmarshalWithRegistry(mongoDbRegistry, tx.Bucket([]byte(RevisionCollection)).Get([]byte(key)), &revision); err != nil {
+			return err
+		}
+
+		revision.State = state
+		raw, err := bson.MarshalWithRegistry(mongoDbRegistry, revision)
+		if err != nil {
+			return err
+		}
+
+		if err = tx.Bucket([]byte(RevisionCollection)).Put([]byte(key), raw); err != nil {
+			return err
+		}
+	}
+
+	for _, state := range append(withoutPhone, crew.created...) {
+		raw, err := bson.MarshalWithRegistry(mongoDbRegistry, state)
+		if err != nil {
+			return err
+		}
+
+		if err = members.Put(state.Id[:], raw); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// boltCrewNames returns, by key, the reservation states of the bucket rewritten with staff ids, a bucket can not be changed while iterated
+func boltCrewNames(bucket *bbolt.Bucket, crew *crewByName, stateOf func(raw []byte) (bson.Raw, error)) (map[string]bson.Raw, error) {
+	converted := map[string]bson.Raw{}
+	err := bucket.ForEach(func(key, raw []byte) error {
+		state, err := stateOf(raw)
+		if err != nil || state == nil {
+			return err
+		}
+
+		state, changed, err := replaceCrewNames(state, crew)
+		if err == nil && changed {
+			converted[string(key)] = state
+		}
+
+		return err
+	})
+
+	return converted, err
+}
+
+func newBoltCollection[T any](db *bbolt.DB, name string, fields func(state *T) embeddedFields) *boltCollection[T] {
+	return &boltCollection[T]{
+		db:     db,
+		name:   name,
+		fields: fields,
+	}
+}
+
+func (collection *boltCollection[T]) find(filter func(state T) bool) ([]T, error) {
+	states := make([]T, 0)
+	err := collection.db.View(func(tx *bbolt.Tx) error {
+		return collection.forEach(tx, func(state T) error {
+			if *collection.fields(&state).DeletedAt == nil && filter(state) {
+				states = append(states, state)
+			}
+
+			return nil
+		})
+	})
+
+	return states, err
+}
+
+func (collection *boltCollection[T]) get(id uuid.UUID) (T, bool, error) {
+	var state T
+	var exists bool
+	err := collection.db.View(func(tx *bbolt.Tx) error {
+		var err error
+		state, exists, err = collection.load(tx, id)
+		if err == nil && exists && *collection.fields(&state).DeletedAt != nil {
+			var empty T
+			state, exists = empty, false
+		}
+
+		return err
+	})
+
+	return state, exists, err
+}
+
+func (collection *boltCollection[T]) save(ctx context.Context, state T, concurrencyErr error) (T, error) {
+	err := collection.db.Update(func(tx *bbolt.Tx) error {
+		fields := collection.fields(&state)
+		// Only softDelete and restore change the deleted fields
+		*fields.DeletedAt = nil
+		*fields.DeletedBy = ""
+		if *fields.Id == uuid.Nil {
+			*fields.Id = uuid.New()
+			*fields.CreatedAt = now()
+			*fields.ModifiedAt = *fields.CreatedAt
+		} else {
+			current, exists, err := collection.load(tx, *fields.Id)
+			if err != nil {
+				return err
+			}
+
+			currentFields := collection.fields(&current)
+			if !exists || *currentFields.DeletedAt != nil || !currentFields.ModifiedAt.Equal(*fields.ModifiedAt) {
+				return concurrencyErr
+			}
+
+			*fields.ModifiedAt = now()
+		}
+
+		return collection.storeWithRevision(ctx, tx, state)
+	})
+
+	return state, err
+}
+
+func (collection *boltCollection[T]) update(ctx context.Context, filter func(state T) bool, change func(state *T)) error {
+	return collection.db.Update(func(tx *bbolt.Tx) error {
+		states := make([]T, 0)
+		err := collection.forEach(tx, func(state T) error {
+			if *collection.fields(&state).DeletedAt == nil && filter(state) {
+				states = append(states, state)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, state := range states {
+			change(&state)
+			*collection.fields(&state).ModifiedAt = now()
+			if err := collection.storeWithRevision(ctx, tx, state); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (collection *boltCollection[T]) softDelete(ctx context.Context, id uuid.UUID) (bool, error) {
+	return collection.change(ctx, id, func(fields embeddedFields) bool {
+		if *fields.DeletedAt != nil {
+			return false
+		}
+
+		at := now()
+		*fields.DeletedAt = &at
+		*fields.DeletedBy = common.Actor(ctx)
+		*fields.ModifiedAt = at
+		return true
+	})
+}
+
+func (collection *boltCollection[T]) restore(ctx context.Context, id uuid.UUID) (bool, error) {
+	return collection.change(ctx, id, func(fields embeddedFields) bool {
+		if *fields.DeletedAt == nil {
+			return false
+		}
+
+		*fields.DeletedAt = nil
+		*fields.DeletedBy = ""
+		*fields.ModifiedAt = now()
+		return true
+	})
+}
+
+func (collection *boltCollection[T]) change(ctx context.Context, id uuid.UUID, change func(fields embeddedFields) bool) (bool, error) {
+	changed := false
+	err := collection.db.Update(func(tx *bbolt.Tx) error {
+		state, exists, err := collection.load(tx, id)
+		if err != nil || !exists {
+			return err
+		}
+
+		if !change(collection.fields(&state)) {
+			return nil
+		}
+
+		changed = true
+		return collection.storeWithRevision(ctx, tx, state)
+	})
+
+	return changed, err
+}
+
+func (collection *boltCollection[T]) deleted() ([]T, error) {
+	states := make([]T, 0)
+	err := collection.db.View(func(tx *bbolt.Tx) error {
+		return collection.forEach(tx, func(state T) error {
+			if *collection.fields(&state).DeletedAt != nil {
+				states = append(states, state)
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(states, func(i, j int) bool {
+		return (*collection.fields(&states[i]).DeletedAt).After(**collection.fields(&states[j]).DeletedAt)
+	})
+
+	return states, nil
+}
+
+func (collection *boltCollection[T]) purge(before time.Time, keep []uuid.UUID) (int64, error) {
+	var count int64
+	err := collection.db.Update(func(tx *bbolt.Tx) error {
+		var ids []uuid.UUID
+		err := collection.forEach(tx, func(state T) error {
+			fields := collection.fields(&state)
+			if *fields.DeletedAt != nil && (*fields.DeletedAt).Before(before) && !common.Contains(keep, *fields.Id) {
+				ids = append(ids, *fields.Id)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		bucket := tx.Bucket([]byte(collection.name))
+		for _, id := range ids {
+			if err := bucket.Delete(id[:]); err != nil {
+				return err
+			}
+		}
+
+		count = int64(len(ids))
+		return nil
+	})
+
+	return count, err
+}
+
+func (collection *boltCollection[T]) history(id uuid.UUID) ([]Revision, error) {
+	revisions := make([]Revision, 0)
+	err := collection.db.View(func(tx *bbolt.Tx) error {
+		return collection.forEachRevision(tx, id, func(revision Revision) bool {
+			revisions = append(revisions, revision)
+			return true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return revisionChanges[T](revisions)
+}
+
+func (collection *boltCollection[T]) revision(id, revisionId uuid.UUID) (T, error) {
+	var raw bson.Raw
+	err := collection.db.View(func(tx *bbolt.Tx) error {
+		return collection.forEachRevision(tx, id, func(revision Revision) bool {
+			if revision.Id != revisionId {
+				return true
+			}
+
+			raw = revision.State
+			return false
+		})
+	})
+
+	var state T
+	if err != nil {
+		return state, err
+	}
+
+	if raw == nil {
+		return state, ErrRevisionNotFound
+	}
+
+	err = bson.UnmarshalWithRegistry(mongoDbRegistry, raw, &state)
+	return state, err
+}
+
+func (collection *boltCollection[T]) load(tx *bbolt.Tx, id uuid.UUID) (T, bool, error) {
+	var state T
+	raw := tx.Bucket([]byte(collection.name)).Get(id[:])
+	if raw == nil {
+		return state, false, nil
+	}
+
+	err := bson.UnmarshalWithRegistry(mongoDbRegistry, raw, &state)
+	return state, true, err
+}
+
+func (collection *boltCollection[T]) store(tx *bbolt.Tx, state T) (bson.Raw, error) {
+	raw, err := bson.MarshalWithRegistry(mongoDbRegistry, state)
+	if err != nil {
+		return nil, err
+	}
+
+	id := *collection.fields(&state).Id
+	return raw, tx.Bucket([]byte(collection.name)).Put(id[:], raw)
+}
+
+func (collection *boltCollection[T]) storeWithRevision(ctx context.Context, tx *bbolt.Tx, state T) error {
+	raw, err := collection.store(tx, state)
+	if err != nil {
+		return err
+	}
+
+	return putBoltRevision(tx, Revision{
+		Id:         uuid.New(),
+		EntityId:   *collection.fields(&state).Id,
+		Collection: collection.name,
+		Actor:      common.Actor(ctx),
+		At:         time.Now().UTC(),
+		State:      raw,
+	})
+}
+
+func (collection *boltCollection[T]) forEach(tx *bbolt.Tx, f func(state T) error) error {
+	return tx.Bucket([]byte(collection.name)).ForEach(func(_, raw []byte) error {
+		var state T
+		if err := bson.UnmarshalWithRegistry(mongoDbRegistry, raw, &state); err != nil {
+			return err
+		}
+
+		return f(state)
+	})
+}
+
+func (collection *boltCollection[T]) forEachRevision(tx *bbolt.Tx, id uuid.UUID, f func(revision Revision) bool) error {
+	cursor := tx.Bucket([]byte(RevisionCollection)).Cursor()
+	for key, raw := cursor.Seek(id[:]); key != nil && bytes.HasPrefix(key, id[:]); key, raw = cursor.Next() {
+		// Values are only valid during the transaction and the revision keeps the raw state
+		var revision Revision
+		if err := bson.UnmarshalWithRegistry(mongoDbRegistry, append([]byte{}, raw...), &revision); err != nil {
+			return err
+		}
+
+		if revision.Collection == collection.name && !f(revision) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// Revisions are keyed by entity id followed by a sequence, so the history of one entity is contiguous and ordered
+func putBoltRevision(tx *bbolt.Tx, revision Revision) error {
+	bucket := tx.Bucket([]byte(RevisionCollection))
+	sequence, err := bucket.NextSequence()
+	if err != nil {
+		return err
+	}
+
+	raw, err := bson.MarshalWithRegistry(mongoDbRegistry, revision)
+	if err != nil {
+		return err
+	}
+
+	key := make([]byte, len(revision.EntityId)+8)
+	copy(key, revision.EntityId[:])
+	binary.BigEndian.PutUint64(key[len(revision.EntityId):], sequence)
+	return bucket.Put(key, raw)
+}
@@ -0,0 +1,110 @@
+package infrastructure
+
+import (
+	"context"
+
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type BoltImport struct {
+	Customers    int64
+	Products     int64
+	Reservations int64
+	Staff        int64
+	Revisions    int64
+}
+
+// ImportMongoDbIntoBolt copies every document, including the deleted ones and their revisions, in one transaction
+func ImportMongoDbIntoBolt(ctx context.Context, client *mongo.Client, db *bbolt.DB) (BoltImport, error) {
+	var res BoltImport
+	err := db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range boltBuckets {
+			if key, _ := tx.Bucket([]byte(name)).Cursor().First(); key != nil {
+				return ErrBoltDatabaseIsNotEmpty
+			}
+		}
+
+		var err error
+		res.Customers, err = importMongoDbCollection(ctx, client, newBoltCollection(db, CustomersCollection, customerFields), tx)
+		if err != nil {
+			return err
+		}
+
+		res.Products, err = importMongoDbCollection(ctx, client, newBoltCollection(db, ProductCollection, productFields), tx)
+		if err != nil {
+			return err
+		}
+
+		res.Reservations, err = importMongoDbCollection(ctx, client, newBoltCollection(db, ReservationCollection, reservationFields), tx)
+		if err != nil {
+			return err
+		}
+
+		res.Staff, err = importMongoDbCollection(ctx, client, newBoltCollection(db, StaffCollection, staffFields), tx)
+		if err != nil {
+			return err
+		}
+
+		res.Revisions, err = importMongoDbRevisions(ctx, client, tx)
+		return err
+	})
+
+	return res, err
+}
+
+func importMongoDbCollection[T any](ctx context.Context, client *mongo.Client, collection *boltCollection[T], tx *bbolt.Tx) (int64, error) {
+	cursor, err := client.Database(Database).
+		Collection(collection.name).
+		Find(ctx, bson.M{})
+	if err != nil {
+		return 0, err
+	}
+
+	defer cursor.Close(ctx)
+
+	var count int64
+	for cursor.Next(ctx) {
+		var state T
+		if err = cursor.Decode(&state); err != nil {
+			return count, err
+		}
+
+		if _, err = collection.store(tx, state); err != nil {
+			return count, err
+		}
+
+		count++
+	}
+
+	return count, cursor.Err()
+}
+
+func importMongoDbRevisions(ctx context.Context, client *mongo.Client, tx *bbolt.Tx) (int64, error) {
+	cursor, err := client.Database(Database).
+		Collection(RevisionCollection).
+		Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "at", Value: 1}}))
+	if err != nil {
+		return 0, err
+	}
+
+	defer cursor.Close(ctx)
+
+	var count int64
+	for cursor.Next(ctx) {
+		var revision Revision
+		if err = cursor.Decode(&revision); err != nil {
+			return count, err
+		}
+
+		if err = putBoltRevision(tx, revision); err != nil {
+			return count, err
+		}
+
+		count++
+	}
+
+	return count, cursor.Err()
+}
@@ -3,7 +3,9 @@ package infrastructure
 const Database = "happy-day"
 
 type Page[T any] struct {
-	Items         []T   `json:"items"`
-	TotalPages    int64 `json:"totalPages"`
-	TotalElements int64 `json:"totalElements"`
+	Items         []T    `json:"items"`
+	TotalPages    int64  `json:"totalPages"`
+	TotalElements int64  `json:"totalElements"`
+	NextCursor    string `json:"nextCursor,omitempty"`
+	PrevCursor    string `json:"prevCursor,omitempty"`
 }
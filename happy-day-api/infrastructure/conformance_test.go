@@ -0,0 +1,1356 @@
+package infrastructure
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"happy_day/common"
+	"happy_day/domain/customer"
+	"happy_day/domain/product"
+	"happy_day/domain/reservation"
+	"happy_day/domain/staff"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// The conformance suites below describe the behaviour every repository backend must share.
+// A backend runs them passing a factory that returns an empty repository.
+
+const conformanceActor = "conformance"
+
+type (
+	customerRepositoryFactory    func(t *testing.T) CustomerRepository
+	productRepositoryFactory     func(t *testing.T) ProductRepository
+	reservationRepositoryFactory func(t *testing.T) ReservationRepository
+	staffRepositoryFactory       func(t *testing.T) StaffRepository
+
+	// legacyCrewFactory stores the staff, the reservation and one revision of it as they are and runs the conversion of the backend
+	legacyCrewFactory func(t *testing.T, members []staff.State, legacy bson.M) (ReservationRepository, StaffRepository)
+)
+
+func TestMemoryRepositoryConformance(t *testing.T) {
+	testCustomerRepositoryConformance(t, func(*testing.T) CustomerRepository {
+		return NewMemoryCustomerRepository()
+	})
+
+	testProductRepositoryConformance(t, func(*testing.T) ProductRepository {
+		return NewMemoryProductRepository()
+	})
+
+	testReservationRepositoryConformance(t, func(*testing.T) ReservationRepository {
+		return NewMemoryReservationRepository()
+	})
+
+	testStaffRepositoryConformance(t, func(*testing.T) StaffRepository {
+		return NewMemoryStaffRepository()
+	})
+}
+
+func TestBoltRepositoryConformance(t *testing.T) {
+	open := func(t *testing.T) *bbolt.DB {
+		db, err := OpenBoltDatabase(filepath.Join(t.TempDir(), "happy-day.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Cleanup(func() {
+			_ = db.Close()
+		})
+
+		return db
+	}
+
+	testCustomerRepositoryConformance(t, func(t *testing.T) CustomerRepository {
+		return NewBoltCustomerRepository(open(t))
+	})
+
+	testProductRepositoryConformance(t, func(t *testing.T) ProductRepository {
+		return NewBoltProductRepository(open(t))
+	})
+
+	testReservationRepositoryConformance(t, func(t *testing.T) ReservationRepository {
+		return NewBoltReservationRepository(open(t))
+	})
+
+	testStaffRepositoryConformance(t, func(t *testing.T) StaffRepository {
+		return NewBoltStaffRepository(open(t))
+	})
+
+	testLegacyCrewConformance(t, func(t *testing.T, members []staff.State, legacy bson.M) (ReservationRepository, StaffRepository) {
+		path := filepath.Join(t.TempDir(), "happy-day.db")
+		db, err := OpenBoltDatabase(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = db.Update(func(tx *bbolt.Tx) error {
+			for _, state := range members {
+				raw, err := bson.MarshalWithRegistry(mongoDbRegistry, state)
+				if err != nil {
+					return err
+				}
+
+				if err = tx.Bucket([]byte(StaffCollection)).Put(state.Id[:], raw); err != nil {
+					return err
+				}
+			}
+
+			raw, err := bson.MarshalWithRegistry(mongoDbRegistry, legacy)
+			if err != nil {
+				return err
+			}
+
+			id := legacy["id"].(uuid.UUID)
+			if err = tx.Bucket([]byte(ReservationCollection)).Put(id[:], raw); err != nil {
+				return err
+			}
+
+			return putBoltRevision(tx, Revision{Id: uuid.New(), EntityId: id, Collection: ReservationCollection, At: now(), State: raw})
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = db.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		// Opening the file again converts it
+		db, err = OpenBoltDatabase(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Cleanup(func() {
+			_ = db.Close()
+		})
+
+		return NewBoltReservationRepository(db), NewBoltStaffRepository(db)
+	})
+}
+
+// HAPPY_DAY_TEST_MONGO must point to a disposable server, the suite drops the happy-day collections
+func TestMongoDbRepositoryConformance(t *testing.T) {
+	uri := os.Getenv("HAPPY_DAY_TEST_MONGO")
+	if len(uri) == 0 {
+		t.Skip("HAPPY_DAY_TEST_MONGO is not set")
+	}
+
+	client, err := mongo.Connect(context.Background(), options.Client().
+		ApplyURI(uri).
+		SetRegistry(mongoDbRegistry))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = client.Disconnect(context.Background())
+	})
+
+	reset := func(t *testing.T) {
+		for _, collection := range []string{CustomersCollection, ProductCollection, ReservationCollection, StaffCollection, RevisionCollection} {
+			if err := client.Database(Database).Collection(collection).Drop(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	testCustomerRepositoryConformance(t, func(t *testing.T) CustomerRepository {
+		reset(t)
+		return NewMongoDbCustomerRepository(client)
+	})
+
+	testProductRepositoryConformance(t, func(t *testing.T) ProductRepository {
+		reset(t)
+		return NewMongoDbProductRepository(client)
+	})
+
+	testReservationRepositoryConformance(t, func(t *testing.T) ReservationRepository {
+		reset(t)
+		return NewMongoDbReservationRepository(client)
+	})
+
+	testStaffRepositoryConformance(t, func(t *testing.T) StaffRepository {
+		reset(t)
+		return NewMongoDbStaffRepository(client)
+	})
+
+	testLegacyCrewConformance(t, func(t *testing.T, members []staff.State, legacy bson.M) (ReservationRepository, StaffRepository) {
+		reset(t)
+		ctx := context.Background()
+		database := client.Database(Database)
+		for _, state := range members {
+			_, err := database.Collection(StaffCollection).InsertOne(ctx, searchDocument[staff.State]{State: state, Search: staffSearchTokens(state)})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		raw, err := bson.MarshalWithRegistry(mongoDbRegistry, legacy)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = database.Collection(ReservationCollection).InsertOne(ctx, raw); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = database.Collection(RevisionCollection).InsertOne(ctx, Revision{
+			Id:         uuid.New(),
+			EntityId:   legacy["id"].(uuid.UUID),
+			Collection: ReservationCollection,
+			At:         now(),
+			State:      raw,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, up := range []func(ctx context.Context, database *mongo.Database) error{replaceCrewNamesByStaff, replaceRevisionCrewNames} {
+			if err = up(ctx, database); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		return NewMongoDbReservationRepository(client), NewMongoDbStaffRepository(client)
+	})
+}
+
+// The memory backend starts empty on every run, so only the persistent backends run testLegacyCrewConformance
+func testLegacyCrewConformance(t *testing.T, factory legacyCrewFactory) {
+	ctx := conformanceContext()
+
+	t.Run("ReservationWhenCrewHasNames", func(t *testing.T) {
+		jose := staff.State{Id: uuid.New(), Name: "José", Phone: "11987654321", Role: staff.Driver, Active: true}
+		id := uuid.New()
+		at := now().Add(48 * time.Hour)
+		reservations, members := factory(t, []staff.State{jose}, bson.M{
+			"id":       id,
+			"delivery": bson.M{"at": at, "by": bson.A{"jose", "Ana"}},
+			"pickUp":   bson.M{"at": at.Add(time.Hour), "by": bson.A{" Ana "}},
+		})
+
+		state, err := reservations.GetById(ctx, id)
+		assert.Nil(t, err)
+		assert.True(t, at.Equal(state.Delivery.At))
+		if assert.Len(t, state.Delivery.By, 2) {
+			assert.Equal(t, jose.Id, state.Delivery.By[0])
+			assert.Equal(t, []uuid.UUID{state.Delivery.By[1]}, state.PickUp.By)
+		}
+
+		page, err := reservations.GetAll(ctx, ReservationFilter{Size: 10})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), page.TotalElements)
+
+		history, err := reservations.GetHistory(ctx, id)
+		assert.Nil(t, err)
+		if assert.Len(t, history, 1) {
+			revision, err := reservations.GetRevision(ctx, id, history[0].Id)
+			assert.Nil(t, err)
+			assert.Equal(t, state.Delivery.By, revision.Delivery.By)
+			assert.Equal(t, state.PickUp.By, revision.PickUp.By)
+		}
+
+		if len(state.PickUp.By) == 1 {
+			ana, err := members.GetById(ctx, state.PickUp.By[0])
+			assert.Nil(t, err)
+			assert.Equal(t, "Ana", ana.Name)
+			assert.Equal(t, MigratedStaffPhone, ana.Phone)
+		}
+	})
+
+	t.Run("StaffWhenPhoneIsEmpty", func(t *testing.T) {
+		// Staff created from crew names before the placeholder phone existed
+		ana := staff.State{Id: uuid.New(), Name: "Ana", Role: staff.Driver, Active: true}
+		_, members := factory(t, []staff.State{ana}, bson.M{"id": uuid.New()})
+
+		found, err := members.GetById(ctx, ana.Id)
+		assert.Nil(t, err)
+		assert.Equal(t, MigratedStaffPhone, found.Phone)
+	})
+}
+
+func conformanceContext() context.Context {
+	return common.WithActor(context.Background(), conformanceActor)
+}
+
+func assertPage[T any](t *testing.T, page Page[T], items int, totalElements, totalPages int64) {
+	assert.Len(t, page.Items, items)
+	assert.Equal(t, totalElements, page.TotalElements)
+	assert.Equal(t, totalPages, page.TotalPages)
+}
+
+// followCursors walks the pages from the first one in the direction given and returns every item in order
+func followCursors[T any](t *testing.T, first Page[T], backward bool, get func(cursor string) (Page[T], error)) []T {
+	items := append([]T{}, first.Items...)
+	page := first
+	for i := 0; i < 100; i++ {
+		cursor := page.NextCursor
+		if backward {
+			cursor = page.PrevCursor
+		}
+
+		if len(cursor) == 0 {
+			return items
+		}
+
+		var err error
+		page, err = get(cursor)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if backward {
+			items = append(append([]T{}, page.Items...), items...)
+		} else {
+			items = append(items, page.Items...)
+		}
+	}
+
+	t.Fatal("cursors did not stop")
+	return nil
+}
+
+func testCustomerRepositoryConformance(t *testing.T, factory customerRepositoryFactory) {
+	ctx := conformanceContext()
+	save := func(t *testing.T, repository CustomerRepository, name, comment, phone string) customer.State {
+		state, err := repository.Save(ctx, customer.State{
+			Name:    name,
+			Comment: comment,
+			Phones:  []customer.Phone{{Number: phone}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return state
+	}
+
+	names := func(page Page[customer.State]) []string {
+		return common.Map(page.Items, func(state customer.State) string {
+			return state.Name
+		})
+	}
+
+	t.Run("CustomerGetByIdWhenNotFound", func(t *testing.T) {
+		_, err := factory(t).GetById(ctx, uuid.New())
+		assert.Equal(t, ErrCustomerNotFound, err)
+	})
+
+	t.Run("CustomerSave", func(t *testing.T) {
+		repository := factory(t)
+		created := save(t, repository, "Maria", "", "11987654321")
+		assert.NotEqual(t, uuid.Nil, created.Id)
+		assert.False(t, created.CreatedAt.IsZero())
+		assert.True(t, created.CreatedAt.Equal(created.ModifiedAt))
+
+		created.Comment = "vip"
+		changed, err := repository.Save(ctx, created)
+		assert.Nil(t, err)
+		assert.False(t, changed.ModifiedAt.Before(created.ModifiedAt))
+
+		found, err := repository.GetById(ctx, created.Id)
+		assert.Nil(t, err)
+		assert.Equal(t, "vip", found.Comment)
+		assert.True(t, changed.ModifiedAt.Equal(found.ModifiedAt))
+	})
+
+	t.Run("CustomerSaveWhenDeletedAtIsSent", func(t *testing.T) {
+		repository := factory(t)
+		created := save(t, repository, "Maria", "", "11987654321")
+
+		deletedAt := time.Now().UTC()
+		created.DeletedAt = &deletedAt
+		created.DeletedBy = "someone"
+		changed, err := repository.Save(ctx, created)
+		assert.Nil(t, err)
+		assert.Nil(t, changed.DeletedAt)
+
+		found, err := repository.GetById(ctx, created.Id)
+		assert.Nil(t, err)
+		assert.Nil(t, found.DeletedAt)
+		assert.Empty(t, found.DeletedBy)
+	})
+
+	t.Run("CustomerSaveWhenModifiedAtIsStale", func(t *testing.T) {
+		repository := factory(t)
+		created := save(t, repository, "Maria", "", "11987654321")
+
+		stale := created
+		stale.ModifiedAt = stale.ModifiedAt.Add(-time.Second)
+		_, err := repository.Save(ctx, stale)
+		assert.Equal(t, ErrCustomerConcurrencyIssue, err)
+
+		created.Id = uuid.New()
+		_, err = repository.Save(ctx, created)
+		assert.Equal(t, ErrCustomerConcurrencyIssue, err)
+	})
+
+	t.Run("CustomerGetAllPaging", func(t *testing.T) {
+		repository := factory(t)
+		for i := 0; i < 5; i++ {
+			save(t, repository, common.RandString(10), "", "11987654321")
+		}
+
+		page, err := repository.GetAll(ctx, CustomerFilter{Page: 1, Size: 2})
+		assert.Nil(t, err)
+		assertPage(t, page, 2, 5, 3)
+
+		page, err = repository.GetAll(ctx, CustomerFilter{Page: 3, Size: 2})
+		assert.Nil(t, err)
+		assertPage(t, page, 1, 5, 3)
+
+		page, err = repository.GetAll(ctx, CustomerFilter{Page: 4, Size: 2})
+		assert.Nil(t, err)
+		assertPage(t, page, 0, 5, 3)
+	})
+
+	t.Run("CustomerGetAllSortBy", func(t *testing.T) {
+		repository := factory(t)
+		save(t, repository, "Carla", "b", "11987654321")
+		save(t, repository, "Ana", "c", "11987654321")
+		save(t, repository, "Bruno", "a", "11987654321")
+
+		expected := map[CustomerSortBy][]string{
+			CustomerNameAsc:     {"Ana", "Bruno", "Carla"},
+			CustomerNameDesc:    {"Carla", "Bruno", "Ana"},
+			CustomerCommentAsc:  {"Bruno", "Carla", "Ana"},
+			CustomerCommentDesc: {"Ana", "Carla", "Bruno"},
+		}
+
+		for sortBy, order := range expected {
+			page, err := repository.GetAll(ctx, CustomerFilter{Page: 1, Size: 10, SortBy: sortBy})
+			assert.Nil(t, err)
+			assert.Equal(t, order, names(page), sortBy)
+		}
+
+		asc, err := repository.GetAll(ctx, CustomerFilter{Page: 1, Size: 10, SortBy: CustomerIdAsc})
+		assert.Nil(t, err)
+		desc, err := repository.GetAll(ctx, CustomerFilter{Page: 1, Size: 10, SortBy: CustomerIdDesc})
+		assert.Nil(t, err)
+		assert.Len(t, asc.Items, 3)
+		assert.Less(t, asc.Items[0].Id.String(), asc.Items[1].Id.String())
+		assert.Less(t, asc.Items[1].Id.String(), asc.Items[2].Id.String())
+		assert.Equal(t, asc.Items[0].Id, desc.Items[2].Id)
+	})
+
+	t.Run("CustomerGetAllCursor", func(t *testing.T) {
+		repository := factory(t)
+		for _, name := range []string{"Carla", "Ana", "Bruno", "Ana", "Carla"} {
+			save(t, repository, name, "", "11987654321")
+		}
+
+		first, err := repository.GetAll(ctx, CustomerFilter{Page: 1, Size: 2, SortBy: CustomerNameAsc})
+		assert.Nil(t, err)
+		assert.Empty(t, first.PrevCursor)
+		assert.NotEmpty(t, first.NextCursor)
+
+		save(t, repository, "Aaron", "", "11987654321")
+		items := followCursors(t, first, false, func(cursor string) (Page[customer.State], error) {
+			return repository.GetAll(ctx, CustomerFilter{Size: 2, SortBy: CustomerNameAsc, Cursor: cursor})
+		})
+		assert.Equal(t, []string{"Ana", "Ana", "Bruno", "Carla", "Carla"}, names(Page[customer.State]{Items: items}))
+
+		last, err := repository.GetAll(ctx, CustomerFilter{Page: 3, Size: 2, SortBy: CustomerNameAsc})
+		assert.Nil(t, err)
+		assert.Empty(t, last.NextCursor)
+		items = followCursors(t, last, true, func(cursor string) (Page[customer.State], error) {
+			return repository.GetAll(ctx, CustomerFilter{Size: 2, SortBy: CustomerNameAsc, Cursor: cursor})
+		})
+		assert.Equal(t, []string{"Aaron", "Ana", "Ana", "Bruno", "Carla", "Carla"}, names(Page[customer.State]{Items: items}))
+		assert.Len(t, common.Distinct(common.Map(items, func(state customer.State) uuid.UUID {
+			return state.Id
+		})), 6)
+
+		_, err = repository.GetAll(ctx, CustomerFilter{Size: 2, Cursor: "invalid"})
+		assert.Equal(t, ErrCursorIsInvalid, err)
+	})
+
+	t.Run("CustomerGetAllText", func(t *testing.T) {
+		repository := factory(t)
+		save(t, repository, "Maria Silva", "", "11987654321")
+		save(t, repository, "João", "friend of MARIA", "11912345678")
+		save(t, repository, "Pedro", "", "(21) 3333-4444")
+
+		page, err := repository.GetAll(ctx, CustomerFilter{Page: 1, Size: 10, Text: "maria", SortBy: CustomerNameAsc})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"João", "Maria Silva"}, names(page))
+
+		page, err = repository.GetAll(ctx, CustomerFilter{Page: 1, Size: 10, Text: "joao"})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"João"}, names(page))
+
+		page, err = repository.GetAll(ctx, CustomerFilter{Page: 1, Size: 10, Text: "2133334444"})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"Pedro"}, names(page))
+
+		page, err = repository.GetAll(ctx, CustomerFilter{Page: 1, Size: 10, Text: "(3333"})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"Pedro"}, names(page))
+
+		page, err = repository.GetAll(ctx, CustomerFilter{Page: 1, Size: 10, Text: "mar sil"})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"Maria Silva"}, names(page))
+
+		page, err = repository.GetAll(ctx, CustomerFilter{Page: 1, Size: 10, Text: "nobody"})
+		assert.Nil(t, err)
+		assertPage(t, page, 0, 0, 0)
+	})
+
+	t.Run("CustomerGetAllTextRanking", func(t *testing.T) {
+		repository := factory(t)
+		save(t, repository, "Anabela", "", "11987654321")
+		save(t, repository, "Ana Souza", "", "11987654321")
+		save(t, repository, "Ana Silva", "", "11987654321")
+
+		page, err := repository.GetAll(ctx, CustomerFilter{Page: 1, Size: 10, Text: "ana silva"})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"Ana Silva"}, names(page))
+
+		page, err = repository.GetAll(ctx, CustomerFilter{Page: 1, Size: 10, Text: "ANA"})
+		assert.Nil(t, err)
+		assert.Len(t, page.Items, 3)
+		assert.Equal(t, "Anabela", names(page)[2])
+	})
+
+	t.Run("CustomerSoftDelete", func(t *testing.T) {
+		repository := factory(t)
+		created := save(t, repository, "Maria", "", "11987654321")
+		save(t, repository, "Joao", "", "11987654321")
+
+		assert.Nil(t, repository.Delete(ctx, created.Id))
+		assert.Equal(t, ErrCustomerNotFound, repository.Delete(ctx, created.Id))
+
+		_, err := repository.GetById(ctx, created.Id)
+		assert.Equal(t, ErrCustomerNotFound, err)
+
+		_, err = repository.Save(ctx, created)
+		assert.Equal(t, ErrCustomerConcurrencyIssue, err)
+
+		page, err := repository.GetAll(ctx, CustomerFilter{Page: 1, Size: 10})
+		assert.Nil(t, err)
+		assertPage(t, page, 1, 1, 1)
+
+		deleted, err := repository.GetDeleted(ctx)
+		assert.Nil(t, err)
+		assert.Len(t, deleted, 1)
+		assert.Equal(t, created.Id, deleted[0].Id)
+		assert.Equal(t, conformanceActor, deleted[0].DeletedBy)
+		assert.NotNil(t, deleted[0].DeletedAt)
+
+		assert.Nil(t, repository.Restore(ctx, created.Id))
+		assert.Equal(t, ErrCustomerNotFound, repository.Restore(ctx, created.Id))
+
+		found, err := repository.GetById(ctx, created.Id)
+		assert.Nil(t, err)
+		assert.Nil(t, found.DeletedAt)
+		assert.Empty(t, found.DeletedBy)
+	})
+
+	t.Run("CustomerPurge", func(t *testing.T) {
+		repository := factory(t)
+		created := save(t, repository, "Maria", "", "11987654321")
+		kept := save(t, repository, "Joao", "", "11987654321")
+		assert.Nil(t, repository.Delete(ctx, created.Id))
+
+		purged, err := repository.Purge(ctx, time.Now().Add(-time.Hour), nil)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), purged)
+
+		purged, err = repository.Purge(ctx, time.Now().Add(time.Hour), []uuid.UUID{created.Id})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), purged)
+
+		purged, err = repository.Purge(ctx, time.Now().Add(time.Hour), nil)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), purged)
+
+		deleted, err := repository.GetDeleted(ctx)
+		assert.Nil(t, err)
+		assert.Empty(t, deleted)
+
+		_, err = repository.GetById(ctx, kept.Id)
+		assert.Nil(t, err)
+	})
+
+	t.Run("CustomerHistory", func(t *testing.T) {
+		repository := factory(t)
+		created := save(t, repository, "Maria", "", "11987654321")
+		created.Name = "Maria Silva"
+		_, err := repository.Save(ctx, created)
+		assert.Nil(t, err)
+
+		history, err := repository.GetHistory(ctx, created.Id)
+		assert.Nil(t, err)
+		assert.Len(t, history, 2)
+		assert.Contains(t, history[0].Changes, Change{Field: "name", Before: "Maria", After: "Maria Silva"})
+
+		state, err := repository.GetRevision(ctx, created.Id, history[1].Id)
+		assert.Nil(t, err)
+		assert.Equal(t, "Maria", state.Name)
+
+		_, err = repository.GetRevision(ctx, created.Id, uuid.New())
+		assert.Equal(t, ErrRevisionNotFound, err)
+	})
+
+	t.Run("CustomerHistoryWhenDeletedAndRestored", func(t *testing.T) {
+		repository := factory(t)
+		created := save(t, repository, "Maria", "", "11987654321")
+		assert.Nil(t, repository.Delete(ctx, created.Id))
+		assert.Nil(t, repository.Restore(ctx, created.Id))
+
+		history, err := repository.GetHistory(ctx, created.Id)
+		assert.Nil(t, err)
+		assert.Len(t, history, 3)
+		assert.Equal(t, conformanceActor, history[1].Actor)
+
+		deleted, err := repository.GetRevision(ctx, created.Id, history[1].Id)
+		assert.Nil(t, err)
+		assert.NotNil(t, deleted.DeletedAt)
+		assert.Equal(t, "Maria", deleted.Name)
+
+		restored, err := repository.GetRevision(ctx, created.Id, history[0].Id)
+		assert.Nil(t, err)
+		assert.Nil(t, restored.DeletedAt)
+	})
+}
+
+func testProductRepositoryConformance(t *testing.T, factory productRepositoryFactory) {
+	ctx := conformanceContext()
+	save := func(t *testing.T, repository ProductRepository, name string, price float64, products ...product.Product) product.State {
+		state, err := repository.Save(ctx, product.State{
+			Name:     name,
+			Price:    price,
+			Products: products,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return state
+	}
+
+	names := func(products []product.State) []string {
+		return common.Map(products, func(state product.State) string {
+			return state.Name
+		})
+	}
+
+	t.Run("ProductGetByIdWhenNotFound", func(t *testing.T) {
+		repository := factory(t)
+		_, err := repository.GetById(ctx, uuid.New())
+		assert.Equal(t, ErrProductNotFound, err)
+
+		exists, err := repository.Exists(ctx, uuid.New())
+		assert.Nil(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("ProductSave", func(t *testing.T) {
+		repository := factory(t)
+		created := save(t, repository, "Table", 10)
+		assert.NotEqual(t, uuid.Nil, created.Id)
+		assert.True(t, created.CreatedAt.Equal(created.ModifiedAt))
+
+		deletedAt := time.Now().UTC()
+		created.Price = 15
+		created.DeletedAt = &deletedAt
+		_, err := repository.Save(ctx, created)
+		assert.Nil(t, err)
+
+		found, err := repository.GetById(ctx, created.Id)
+		assert.Nil(t, err)
+		assert.Equal(t, float64(15), found.Price)
+		assert.Nil(t, found.DeletedAt)
+
+		exists, err := repository.Exists(ctx, created.Id)
+		assert.Nil(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("ProductSaveWhenModifiedAtIsStale", func(t *testing.T) {
+		repository := factory(t)
+		created := save(t, repository, "Table", 10)
+
+		stale := created
+		stale.ModifiedAt = stale.ModifiedAt.Add(-time.Second)
+		_, err := repository.Save(ctx, stale)
+		assert.Equal(t, ErrProductConcurrencyIssue, err)
+
+		created.Id = uuid.New()
+		_, err = repository.Save(ctx, created)
+		assert.Equal(t, ErrProductConcurrencyIssue, err)
+	})
+
+	t.Run("ProductGetAllPaging", func(t *testing.T) {
+		repository := factory(t)
+		for i := 0; i < 4; i++ {
+			save(t, repository, common.RandString(10), 1)
+		}
+
+		page, err := repository.GetAll(ctx, ProductFilter{Page: 1, Size: 3})
+		assert.Nil(t, err)
+		assertPage(t, page, 3, 4, 2)
+
+		page, err = repository.GetAll(ctx, ProductFilter{Page: 2, Size: 3})
+		assert.Nil(t, err)
+		assertPage(t, page, 1, 4, 2)
+
+		page, err = repository.GetAll(ctx, ProductFilter{Page: 1, Size: 4})
+		assert.Nil(t, err)
+		assertPage(t, page, 4, 4, 1)
+	})
+
+	t.Run("ProductGetAllSortBy", func(t *testing.T) {
+		repository := factory(t)
+		save(t, repository, "Chair", 5)
+		save(t, repository, "Balloon", 20)
+		save(t, repository, "Table", 10)
+
+		expected := map[ProductSortBy][]string{
+			ProductNameAsc:   {"Balloon", "Chair", "Table"},
+			ProductNameDesc:  {"Table", "Chair", "Balloon"},
+			ProductPriceAsc:  {"Chair", "Table", "Balloon"},
+			ProductPriceDesc: {"Balloon", "Table", "Chair"},
+		}
+
+		for sortBy, order := range expected {
+			page, err := repository.GetAll(ctx, ProductFilter{Page: 1, Size: 10, SortBy: sortBy})
+			assert.Nil(t, err)
+			assert.Equal(t, order, names(page.Items), sortBy)
+		}
+
+		asc, err := repository.GetAll(ctx, ProductFilter{Page: 1, Size: 10, SortBy: ProductIdAsc})
+		assert.Nil(t, err)
+		desc, err := repository.GetAll(ctx, ProductFilter{Page: 1, Size: 10, SortBy: ProductIdDesc})
+		assert.Nil(t, err)
+		assert.Len(t, asc.Items, 3)
+		assert.Less(t, asc.Items[0].Id.String(), asc.Items[1].Id.String())
+		assert.Less(t, asc.Items[1].Id.String(), asc.Items[2].Id.String())
+		assert.Equal(t, asc.Items[0].Id, desc.Items[2].Id)
+	})
+
+	t.Run("ProductGetAllCursor", func(t *testing.T) {
+		repository := factory(t)
+		save(t, repository, "Chair", 5)
+		save(t, repository, "Balloon", 20)
+		save(t, repository, "Table", 10)
+		save(t, repository, "Bench", 10)
+
+		first, err := repository.GetAll(ctx, ProductFilter{Page: 1, Size: 3, SortBy: ProductPriceDesc})
+		assert.Nil(t, err)
+		items := followCursors(t, first, false, func(cursor string) (Page[product.State], error) {
+			return repository.GetAll(ctx, ProductFilter{Size: 3, SortBy: ProductPriceDesc, Cursor: cursor})
+		})
+
+		assert.Len(t, items, 4)
+		assert.Equal(t, "Balloon", items[0].Name)
+		assert.ElementsMatch(t, []string{"Table", "Bench"}, names(items[1:3]))
+		assert.Equal(t, "Chair", items[3].Name)
+	})
+
+	t.Run("ProductGetAllText", func(t *testing.T) {
+		repository := factory(t)
+		save(t, repository, "Round Table", 5)
+		save(t, repository, "Table cloth", 20)
+		chair := save(t, repository, "Chair", 10)
+
+		page, err := repository.GetAll(ctx, ProductFilter{Page: 1, Size: 10, Text: "TABLE", SortBy: ProductNameAsc})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"Round Table", "Table cloth"}, names(page.Items))
+
+		page, err = repository.GetAll(ctx, ProductFilter{Page: 1, Size: 10, Text: chair.Id.String()})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"Chair"}, names(page.Items))
+	})
+
+	t.Run("ProductGetByProducts", func(t *testing.T) {
+		repository := factory(t)
+		table := save(t, repository, "Table", 10)
+		chair := save(t, repository, "Chair", 5)
+		save(t, repository, "Balloon", 1)
+
+		products, err := repository.GetByProducts(ctx, []uuid.UUID{table.Id, chair.Id})
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []string{"Table", "Chair"}, names(products))
+
+		_, err = repository.GetByProducts(ctx, []uuid.UUID{table.Id, uuid.New()})
+		assert.Equal(t, ErrOneProductNotFound, err)
+
+		assert.Nil(t, repository.Delete(ctx, chair.Id))
+		_, err = repository.GetByProducts(ctx, []uuid.UUID{table.Id, chair.Id})
+		assert.Equal(t, ErrOneProductNotFound, err)
+	})
+
+	t.Run("ProductGetComposed", func(t *testing.T) {
+		repository := factory(t)
+		table := save(t, repository, "Table", 10)
+		chair := save(t, repository, "Chair", 5)
+		balloon := save(t, repository, "Balloon", 1)
+		save(t, repository, "Kit", 30,
+			product.Product{Id: table.Id, Quantity: 1},
+			product.Product{Id: chair.Id, Quantity: 4})
+		save(t, repository, "Party", 40,
+			product.Product{Id: table.Id, Quantity: 1},
+			product.Product{Id: balloon.Id, Quantity: 10})
+
+		composed, err := repository.GetComposed(ctx, []uuid.UUID{table.Id, chair.Id})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"Kit"}, names(composed))
+
+		exists, err := repository.ExistAnyWithProduct(ctx, balloon.Id)
+		assert.Nil(t, err)
+		assert.True(t, exists)
+
+		exists, err = repository.ExistAnyWithProduct(ctx, uuid.New())
+		assert.Nil(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("ProductSoftDelete", func(t *testing.T) {
+		repository := factory(t)
+		created := save(t, repository, "Table", 10)
+
+		assert.Nil(t, repository.Delete(ctx, created.Id))
+		assert.Equal(t, ErrProductNotFound, repository.Delete(ctx, created.Id))
+
+		_, err := repository.GetById(ctx, created.Id)
+		assert.Equal(t, ErrProductNotFound, err)
+
+		exists, err := repository.Exists(ctx, created.Id)
+		assert.Nil(t, err)
+		assert.False(t, exists)
+
+		deleted, err := repository.GetDeleted(ctx)
+		assert.Nil(t, err)
+		assert.Len(t, deleted, 1)
+		assert.Equal(t, conformanceActor, deleted[0].DeletedBy)
+
+		assert.Nil(t, repository.Restore(ctx, created.Id))
+		_, err = repository.GetById(ctx, created.Id)
+		assert.Nil(t, err)
+
+		purged, err := repository.Purge(ctx, time.Now().Add(time.Hour), nil)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), purged)
+	})
+}
+
+func testReservationRepositoryConformance(t *testing.T, factory reservationRepositoryFactory) {
+	ctx := conformanceContext()
+	day := time.Date(2030, 1, 10, 10, 0, 0, 0, time.UTC)
+	newReservation := func(customerId uuid.UUID, name string, delivery time.Time, hours int, status reservation.Status) reservation.State {
+		return reservation.State{
+			Products:   []reservation.Product{{Id: uuid.New(), Price: 10, Quantity: 1}},
+			Delivery:   reservation.DeliveryOrPickUp{At: delivery},
+			PickUp:     reservation.DeliveryOrPickUp{At: delivery.Add(time.Duration(hours) * time.Hour)},
+			CustomerId: customerId,
+			Customer: reservation.Customer{State: customer.State{
+				Id:     customerId,
+				Name:   name,
+				Phones: []customer.Phone{{Number: "11987654321"}},
+			}},
+			Address: reservation.Address{Street: "Rua " + name, Number: "10", PostalCode: "01000-000", City: "Sao Paulo"},
+			Status:  status,
+		}
+	}
+
+	save := func(t *testing.T, repository ReservationRepository, state reservation.State) reservation.State {
+		state, err := repository.Save(ctx, state)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return state
+	}
+
+	names := func(reservations []reservation.State) []string {
+		return common.Map(reservations, func(state reservation.State) string {
+			return state.Customer.Name
+		})
+	}
+
+	t.Run("ReservationGetByIdWhenNotFound", func(t *testing.T) {
+		_, err := factory(t).GetById(ctx, uuid.New())
+		assert.Equal(t, ErrReservationNotFound, err)
+	})
+
+	t.Run("ReservationSave", func(t *testing.T) {
+		repository := factory(t)
+		created := save(t, repository, newReservation(uuid.New(), "Maria", day, 4, reservation.Draft))
+		assert.NotEqual(t, uuid.Nil, created.Id)
+
+		deletedAt := time.Now().UTC()
+		created.Comment = "birthday"
+		created.DeletedAt = &deletedAt
+		_, err := repository.Save(ctx, created)
+		assert.Nil(t, err)
+
+		found, err := repository.GetById(ctx, created.Id)
+		assert.Nil(t, err)
+		assert.Equal(t, "birthday", found.Comment)
+		assert.Nil(t, found.DeletedAt)
+		assert.True(t, day.Equal(found.Delivery.At))
+		assert.Equal(t, created.Customer.Name, found.Customer.Name)
+	})
+
+	t.Run("ReservationSaveWhenModifiedAtIsStale", func(t *testing.T) {
+		repository := factory(t)
+		created := save(t, repository, newReservation(uuid.New(), "Maria", day, 4, reservation.Draft))
+
+		stale := created
+		stale.ModifiedAt = stale.ModifiedAt.Add(-time.Second)
+		_, err := repository.Save(ctx, stale)
+		assert.Equal(t, ErrReservationConcurrencyIssue, err)
+
+		created.Id = uuid.New()
+		_, err = repository.Save(ctx, created)
+		assert.Equal(t, ErrReservationConcurrencyIssue, err)
+	})
+
+	t.Run("ReservationGetAllPaging", func(t *testing.T) {
+		repository := factory(t)
+		for i := 0; i < 7; i++ {
+			save(t, repository, newReservation(uuid.New(), common.RandString(10), day.AddDate(0, 0, i), 4, reservation.Draft))
+		}
+
+		page, err := repository.GetAll(ctx, ReservationFilter{Page: 2, Size: 3})
+		assert.Nil(t, err)
+		assertPage(t, page, 3, 7, 3)
+
+		page, err = repository.GetAll(ctx, ReservationFilter{Page: 3, Size: 3})
+		assert.Nil(t, err)
+		assertPage(t, page, 1, 7, 3)
+	})
+
+	t.Run("ReservationGetAllSortBy", func(t *testing.T) {
+		repository := factory(t)
+		save(t, repository, newReservation(uuid.New(), "Second", day.AddDate(0, 0, 1), 1, reservation.Draft))
+		save(t, repository, newReservation(uuid.New(), "First", day, 48, reservation.Draft))
+		save(t, repository, newReservation(uuid.New(), "Third", day.AddDate(0, 0, 2), 1, reservation.Draft))
+
+		expected := map[ReservationOrderBy][]string{
+			DeliveryAsc:  {"First", "Second", "Third"},
+			DeliveryDesc: {"Third", "Second", "First"},
+			PickupAsc:    {"Second", "First", "Third"},
+			PickupDesc:   {"Third", "First", "Second"},
+		}
+
+		for sortBy, order := range expected {
+			page, err := repository.GetAll(ctx, ReservationFilter{Page: 1, Size: 10, SortBy: sortBy})
+			assert.Nil(t, err)
+			assert.Equal(t, order, names(page.Items), sortBy)
+		}
+	})
+
+	t.Run("ReservationGetAllCursor", func(t *testing.T) {
+		repository := factory(t)
+		save(t, repository, newReservation(uuid.New(), "Second", day.AddDate(0, 0, 1), 1, reservation.Draft))
+		save(t, repository, newReservation(uuid.New(), "First", day, 1, reservation.Draft))
+		save(t, repository, newReservation(uuid.New(), "Cancelled", day, 1, reservation.Cancelled))
+		save(t, repository, newReservation(uuid.New(), "Third", day.AddDate(0, 0, 2), 1, reservation.Draft))
+
+		filter := ReservationFilter{Page: 1, Size: 1, SortBy: DeliveryDesc, Status: []reservation.Status{reservation.Draft}}
+		first, err := repository.GetAll(ctx, filter)
+		assert.Nil(t, err)
+
+		filter.Page = 0
+		items := followCursors(t, first, false, func(cursor string) (Page[reservation.State], error) {
+			filter.Cursor = cursor
+			return repository.GetAll(ctx, filter)
+		})
+		assert.Equal(t, []string{"Third", "Second", "First"}, names(items))
+	})
+
+	t.Run("ReservationGetAllFilter", func(t *testing.T) {
+		repository := factory(t)
+		customerId := uuid.New()
+		save(t, repository, newReservation(customerId, "Maria", day, 4, reservation.Confirmed))
+		save(t, repository, newReservation(uuid.New(), "Joao", day.AddDate(0, 0, 1), 4, reservation.Draft))
+		save(t, repository, newReservation(uuid.New(), "Pedro", day.AddDate(0, 0, 2), 4, reservation.Cancelled))
+
+		page, err := repository.GetAll(ctx, ReservationFilter{Page: 1, Size: 10, Text: "MARIA"})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"Maria"}, names(page.Items))
+
+		page, err = repository.GetAll(ctx, ReservationFilter{Page: 1, Size: 10, Text: "rua joao"})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"Joao"}, names(page.Items))
+
+		page, err = repository.GetAll(ctx, ReservationFilter{
+			Page:   1,
+			Size:   10,
+			SortBy: DeliveryAsc,
+			Status: []reservation.Status{reservation.Draft, reservation.Cancelled},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"Joao", "Pedro"}, names(page.Items))
+
+		page, err = repository.GetAll(ctx, ReservationFilter{Page: 1, Size: 10, CustomerId: customerId})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"Maria"}, names(page.Items))
+
+		reservations, err := repository.GetByCustomer(ctx, customerId)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"Maria"}, names(reservations))
+	})
+
+	t.Run("ReservationGetAllStructuredFilter", func(t *testing.T) {
+		repository := factory(t)
+		ana := uuid.New()
+		maria := newReservation(uuid.New(), "Maria", day, 4, reservation.Confirmed)
+		maria.Address.Neighborhood = "Centro"
+		maria.Delivery.By = []uuid.UUID{ana}
+		maria.BalanceDue = 50
+		maria.PaymentInstallments = []reservation.PaymentInstallment{{Amount: 50, Method: reservation.Pix, At: day}}
+		maria = save(t, repository, maria)
+
+		joao := newReservation(uuid.New(), "Joao", day.AddDate(0, 0, 1), 4, reservation.Confirmed)
+		joao.Address.City = "Campinas"
+		joao.PickUp.By = []uuid.UUID{ana}
+		joao.PaymentInstallments = []reservation.PaymentInstallment{{Amount: 10, Method: reservation.Cash, At: day}}
+		save(t, repository, joao)
+
+		save(t, repository, newReservation(uuid.New(), "Pedro", day.AddDate(0, 0, 2), 4, reservation.Draft))
+
+		hasBalanceDue := true
+		noBalanceDue := false
+		filters := map[string]struct {
+			filter   ReservationFilter
+			expected []string
+		}{
+			"product":       {ReservationFilter{ProductId: maria.Products[0].Id}, []string{"Maria"}},
+			"delivery":      {ReservationFilter{DeliveryFrom: day.Add(time.Hour), DeliveryTo: day.AddDate(0, 0, 2)}, []string{"Joao"}},
+			"pickUp":        {ReservationFilter{PickUpFrom: day.AddDate(0, 0, 1)}, []string{"Joao", "Pedro"}},
+			"city":          {ReservationFilter{City: "campinas"}, []string{"Joao"}},
+			"neighborhood":  {ReservationFilter{Neighborhood: "CENTRO"}, []string{"Maria"}},
+			"paymentMethod": {ReservationFilter{PaymentMethod: reservation.Cash}, []string{"Joao"}},
+			"by":            {ReservationFilter{By: ana}, []string{"Maria", "Joao"}},
+			"balanceDue":    {ReservationFilter{HasBalanceDue: &hasBalanceDue}, []string{"Maria"}},
+			"noBalanceDue":  {ReservationFilter{HasBalanceDue: &noBalanceDue}, []string{"Joao", "Pedro"}},
+			"combined":      {ReservationFilter{By: ana, Text: "sao paulo", Status: []reservation.Status{reservation.Confirmed}}, []string{"Maria"}},
+		}
+
+		for name, item := range filters {
+			item.filter.Page = 1
+			item.filter.Size = 10
+			item.filter.SortBy = DeliveryAsc
+			page, err := repository.GetAll(ctx, item.filter)
+			assert.Nil(t, err, name)
+			assert.Equal(t, item.expected, names(page.Items), name)
+		}
+	})
+
+	t.Run("ReservationGetByPeriod", func(t *testing.T) {
+		repository := factory(t)
+		save(t, repository, newReservation(uuid.New(), "Before", day.Add(-4*time.Hour), 4, reservation.Confirmed))
+		save(t, repository, newReservation(uuid.New(), "Overlap", day.Add(-2*time.Hour), 4, reservation.Confirmed))
+		save(t, repository, newReservation(uuid.New(), "Inside", day.Add(time.Hour), 1, reservation.Draft))
+		save(t, repository, newReservation(uuid.New(), "Cancelled", day, 4, reservation.Cancelled))
+		save(t, repository, newReservation(uuid.New(), "After", day.Add(4*time.Hour), 4, reservation.Confirmed))
+
+		reservations, err := repository.GetByPeriod(ctx, day, day.Add(4*time.Hour))
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []string{"Overlap", "Inside"}, names(reservations))
+	})
+
+	t.Run("ReservationExistsOpen", func(t *testing.T) {
+		repository := factory(t)
+		open := save(t, repository, newReservation(uuid.New(), "Open", day, 4, reservation.Delivered))
+		closed := save(t, repository, newReservation(uuid.New(), "Closed", day, 4, reservation.Closed))
+
+		exists, err := repository.ExistsOpenWithProduct(ctx, open.Products[0].Id)
+		assert.Nil(t, err)
+		assert.True(t, exists)
+
+		exists, err = repository.ExistsOpenWithProduct(ctx, closed.Products[0].Id)
+		assert.Nil(t, err)
+		assert.False(t, exists)
+
+		exists, err = repository.ExistsOpenWithCustomer(ctx, open.CustomerId)
+		assert.Nil(t, err)
+		assert.True(t, exists)
+
+		exists, err = repository.ExistsOpenWithCustomer(ctx, closed.CustomerId)
+		assert.Nil(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("ReservationGetByStaff", func(t *testing.T) {
+		repository := factory(t)
+		ana := uuid.New()
+		delivery := newReservation(uuid.New(), "Delivery", day, 4, reservation.Confirmed)
+		delivery.Delivery.By = []uuid.UUID{ana, uuid.New()}
+		save(t, repository, delivery)
+
+		pickUp := newReservation(uuid.New(), "PickUp", day.Add(-8*time.Hour), 10, reservation.Confirmed)
+		pickUp.PickUp.By = []uuid.UUID{ana}
+		save(t, repository, pickUp)
+
+		outside := newReservation(uuid.New(), "Outside", day.AddDate(0, 0, 1), 4, reservation.Confirmed)
+		outside.Delivery.By = []uuid.UUID{ana}
+		save(t, repository, outside)
+
+		cancelled := newReservation(uuid.New(), "Cancelled", day, 4, reservation.Cancelled)
+		cancelled.Delivery.By = []uuid.UUID{ana}
+		save(t, repository, cancelled)
+
+		save(t, repository, newReservation(uuid.New(), "Other", day, 4, reservation.Confirmed))
+
+		reservations, err := repository.GetByStaff(ctx, ana, day, day.Add(12*time.Hour))
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []string{"Delivery", "PickUp"}, names(reservations))
+
+		exists, err := repository.ExistsOpenWithStaff(ctx, ana)
+		assert.Nil(t, err)
+		assert.True(t, exists)
+
+		exists, err = repository.ExistsOpenWithStaff(ctx, uuid.New())
+		assert.Nil(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("ReservationUpdateCustomer", func(t *testing.T) {
+		repository := factory(t)
+		customerId := uuid.New()
+		open := save(t, repository, newReservation(customerId, "Maria", day, 4, reservation.Confirmed))
+		pickedUp := save(t, repository, newReservation(customerId, "Maria", day, 4, reservation.PickedUp))
+
+		err := repository.UpdateCustomer(ctx, customer.State{
+			Id:     customerId,
+			Name:   "Maria Silva",
+			Phones: []customer.Phone{{Number: "11987654321"}},
+		})
+		assert.Nil(t, err)
+
+		found, err := repository.GetById(ctx, open.Id)
+		assert.Nil(t, err)
+		assert.Equal(t, "Maria Silva", found.Customer.Name)
+		assert.False(t, found.ModifiedAt.Before(open.ModifiedAt))
+
+		history, err := repository.GetHistory(ctx, open.Id)
+		assert.Nil(t, err)
+		assert.Len(t, history, 2)
+		assert.Contains(t, history[0].Changes, Change{Field: "customer.name", Before: "Maria", After: "Maria Silva"})
+
+		found, err = repository.GetById(ctx, pickedUp.Id)
+		assert.Nil(t, err)
+		assert.Equal(t, "Maria", found.Customer.Name)
+	})
+
+	t.Run("ReservationSoftDelete", func(t *testing.T) {
+		repository := factory(t)
+		created := save(t, repository, newReservation(uuid.New(), "Maria", day, 4, reservation.Confirmed))
+
+		assert.Nil(t, repository.Delete(ctx, created.Id))
+		assert.Equal(t, ErrReservationNotFound, repository.Delete(ctx, created.Id))
+
+		_, err := repository.GetById(ctx, created.Id)
+		assert.Equal(t, ErrReservationNotFound, err)
+
+		reservations, err := repository.GetByPeriod(ctx, day, day.Add(time.Hour))
+		assert.Nil(t, err)
+		assert.Empty(t, reservations)
+
+		deleted, err := repository.GetDeleted(ctx)
+		assert.Nil(t, err)
+		assert.Len(t, deleted, 1)
+		assert.Equal(t, conformanceActor, deleted[0].DeletedBy)
+
+		assert.Nil(t, repository.Restore(ctx, created.Id))
+		_, err = repository.GetById(ctx, created.Id)
+		assert.Nil(t, err)
+	})
+
+	t.Run("ReservationGetReferences", func(t *testing.T) {
+		repository := factory(t)
+		closed := save(t, repository, newReservation(uuid.New(), "Maria", day, 4, reservation.Closed))
+		deleted := save(t, repository, newReservation(uuid.New(), "Joao", day, 4, reservation.Cancelled))
+		assert.Nil(t, repository.Delete(ctx, deleted.Id))
+
+		references, err := repository.GetReferences(ctx)
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []uuid.UUID{closed.CustomerId, deleted.CustomerId}, references.Customers)
+		assert.ElementsMatch(t, []uuid.UUID{closed.Products[0].Id, deleted.Products[0].Id}, references.Products)
+		assert.Empty(t, references.Staff)
+
+		crew := newReservation(uuid.New(), "Ana", day.AddDate(0, 0, 7), 4, reservation.Confirmed)
+		crew.Delivery.By = []uuid.UUID{uuid.New()}
+		crew.PickUp.By = append([]uuid.UUID{uuid.New()}, crew.Delivery.By...)
+		save(t, repository, crew)
+
+		references, err = repository.GetReferences(ctx)
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, crew.PickUp.By, references.Staff)
+	})
+}
+
+func testStaffRepositoryConformance(t *testing.T, factory staffRepositoryFactory) {
+	ctx := conformanceContext()
+	save := func(t *testing.T, repository StaffRepository, name string, role staff.Role, active bool) staff.State {
+		state, err := repository.Save(ctx, staff.State{
+			Name:   name,
+			Phone:  "11987654321",
+			Role:   role,
+			Active: active,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return state
+	}
+
+	names := func(members []staff.State) []string {
+		return common.Map(members, func(state staff.State) string {
+			return state.Name
+		})
+	}
+
+	t.Run("StaffGetByIdWhenNotFound", func(t *testing.T) {
+		_, err := factory(t).GetById(ctx, uuid.New())
+		assert.Equal(t, ErrStaffNotFound, err)
+	})
+
+	t.Run("StaffSave", func(t *testing.T) {
+		repository := factory(t)
+		created := save(t, repository, "Ana", staff.Driver, true)
+		assert.NotEqual(t, uuid.Nil, created.Id)
+		assert.True(t, created.CreatedAt.Equal(created.ModifiedAt))
+
+		created.Active = false
+		_, err := repository.Save(ctx, created)
+		assert.Nil(t, err)
+
+		found, err := repository.GetById(ctx, created.Id)
+		assert.Nil(t, err)
+		assert.False(t, found.Active)
+
+		stale := found
+		stale.ModifiedAt = stale.ModifiedAt.Add(-time.Second)
+		_, err = repository.Save(ctx, stale)
+		assert.Equal(t, ErrStaffConcurrencyIssue, err)
+	})
+
+	t.Run("StaffSaveWhenDeletedAtIsSent", func(t *testing.T) {
+		repository := factory(t)
+		created := save(t, repository, "Ana", staff.Driver, true)
+
+		deletedAt := time.Now().UTC()
+		created.DeletedAt = &deletedAt
+		created.DeletedBy = "someone"
+		changed, err := repository.Save(ctx, created)
+		assert.Nil(t, err)
+		assert.Nil(t, changed.DeletedAt)
+
+		found, err := repository.GetById(ctx, created.Id)
+		assert.Nil(t, err)
+		assert.Nil(t, found.DeletedAt)
+		assert.Empty(t, found.DeletedBy)
+	})
+
+	t.Run("StaffGetByIds", func(t *testing.T) {
+		repository := factory(t)
+		ana := save(t, repository, "Ana", staff.Driver, true)
+		bruno := save(t, repository, "Bruno", staff.Helper, true)
+
+		members, err := repository.GetByIds(ctx, []uuid.UUID{ana.Id, bruno.Id})
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []string{"Ana", "Bruno"}, names(members))
+
+		_, err = repository.GetByIds(ctx, []uuid.UUID{ana.Id, uuid.New()})
+		assert.Equal(t, ErrOneStaffNotFound, err)
+	})
+
+	t.Run("StaffGetAllFilter", func(t *testing.T) {
+		repository := factory(t)
+		save(t, repository, "Ana Souza", staff.Driver, true)
+		save(t, repository, "Bruno", staff.Helper, true)
+		save(t, repository, "Carla", staff.Driver, false)
+
+		active := true
+		filters := map[string]struct {
+			filter   StaffFilter
+			expected []string
+		}{
+			"all":    {StaffFilter{}, []string{"Ana Souza", "Bruno", "Carla"}},
+			"active": {StaffFilter{Active: &active}, []string{"Ana Souza", "Bruno"}},
+			"role":   {StaffFilter{Role: staff.Driver}, []string{"Ana Souza", "Carla"}},
+			"text":   {StaffFilter{Text: "souza"}, []string{"Ana Souza"}},
+		}
+
+		for name, item := range filters {
+			item.filter.Page = 1
+			item.filter.Size = 10
+			item.filter.SortBy = StaffNameAsc
+			page, err := repository.GetAll(ctx, item.filter)
+			assert.Nil(t, err, name)
+			assert.Equal(t, item.expected, names(page.Items), name)
+		}
+
+		first, err := repository.GetAll(ctx, StaffFilter{Page: 1, Size: 2, SortBy: StaffNameDesc})
+		assert.Nil(t, err)
+		items := followCursors(t, first, false, func(cursor string) (Page[staff.State], error) {
+			return repository.GetAll(ctx, StaffFilter{Size: 2, SortBy: StaffNameDesc, Cursor: cursor})
+		})
+		assert.Equal(t, []string{"Carla", "Bruno", "Ana Souza"}, names(items))
+	})
+
+	t.Run("StaffSoftDelete", func(t *testing.T) {
+		repository := factory(t)
+		created := save(t, repository, "Ana", staff.Driver, true)
+
+		assert.Nil(t, repository.Delete(ctx, created.Id))
+		assert.Equal(t, ErrStaffNotFound, repository.Delete(ctx, created.Id))
+
+		_, err := repository.GetById(ctx, created.Id)
+		assert.Equal(t, ErrStaffNotFound, err)
+
+		page, err := repository.GetAll(ctx, StaffFilter{Page: 1, Size: 10})
+		assert.Nil(t, err)
+		assert.Empty(t, page.Items)
+
+		deleted, err := repository.GetDeleted(ctx)
+		assert.Nil(t, err)
+		assert.Len(t, deleted, 1)
+		assert.Equal(t, created.Id, deleted[0].Id)
+		assert.Equal(t, conformanceActor, deleted[0].DeletedBy)
+
+		assert.Nil(t, repository.Restore(ctx, created.Id))
+		assert.Equal(t, ErrStaffNotFound, repository.Restore(ctx, created.Id))
+
+		found, err := repository.GetById(ctx, created.Id)
+		assert.Nil(t, err)
+		assert.Nil(t, found.DeletedAt)
+	})
+
+	t.Run("StaffPurge", func(t *testing.T) {
+		repository := factory(t)
+		created := save(t, repository, "Ana", staff.Driver, true)
+		kept := save(t, repository, "Bruno", staff.Helper, true)
+		assert.Nil(t, repository.Delete(ctx, created.Id))
+
+		purged, err := repository.Purge(ctx, time.Now().Add(time.Hour), []uuid.UUID{created.Id})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), purged)
+
+		purged, err = repository.Purge(ctx, time.Now().Add(time.Hour), nil)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), purged)
+
+		deleted, err := repository.GetDeleted(ctx)
+		assert.Nil(t, err)
+		assert.Empty(t, deleted)
+
+		_, err = repository.GetById(ctx, kept.Id)
+		assert.Nil(t, err)
+	})
+}
@@ -0,0 +1,336 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrCursorIsInvalid = errors.New("cursor is invalid")
+
+type (
+	// sortKey describes the active sort, the id is always used as tie-breaker so the order is total
+	sortKey[T any] struct {
+		field      string
+		descending bool
+		value      func(state *T) any
+		fields     func(state *T) embeddedFields
+	}
+
+	pageCursor struct {
+		Value    any
+		Id       uuid.UUID
+		Backward bool
+	}
+
+	encodedCursor struct {
+		Value    bson.RawValue `bson:"v"`
+		Id       uuid.UUID     `bson:"id"`
+		Backward bool          `bson:"b"`
+	}
+)
+
+func idSortKey[T any](fields func(state *T) embeddedFields, descending bool) sortKey[T] {
+	return sortKey[T]{
+		field:      "id",
+		descending: descending,
+		value: func(state *T) any {
+			return *fields(state).Id
+		},
+		fields: fields,
+	}
+}
+
+func encodeCursor(value any, id uuid.UUID, backward bool) (string, error) {
+	raw, err := bson.MarshalWithRegistry(mongoDbRegistry, bson.M{"v": value, "id": id, "b": backward})
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeCursor(cursor string) (pageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return pageCursor{}, ErrCursorIsInvalid
+	}
+
+	var encoded encodedCursor
+	if err = bson.UnmarshalWithRegistry(mongoDbRegistry, raw, &encoded); err != nil {
+		return pageCursor{}, ErrCursorIsInvalid
+	}
+
+	res := pageCursor{Id: encoded.Id, Backward: encoded.Backward}
+	switch encoded.Value.Type {
+	case bsontype.String:
+		res.Value = encoded.Value.StringValue()
+	case bsontype.Double:
+		res.Value = encoded.Value.Double()
+	case bsontype.DateTime:
+		res.Value = encoded.Value.Time().UTC()
+	case bsontype.Binary:
+		_, data := encoded.Value.Binary()
+		res.Value, err = uuid.FromBytes(data)
+	default:
+		err = ErrCursorIsInvalid
+	}
+
+	if err != nil {
+		return pageCursor{}, ErrCursorIsInvalid
+	}
+
+	return res, nil
+}
+
+func compareValues(a, b any) int {
+	switch a := a.(type) {
+	case string:
+		return strings.Compare(a, b.(string))
+	case float64:
+		if a < b.(float64) {
+			return -1
+		} else if a > b.(float64) {
+			return 1
+		}
+	case time.Time:
+		if a.Before(b.(time.Time)) {
+			return -1
+		} else if a.After(b.(time.Time)) {
+			return 1
+		}
+	case uuid.UUID:
+		return strings.Compare(a.String(), b.(uuid.UUID).String())
+	}
+
+	return 0
+}
+
+func (key sortKey[T]) compare(state *T, value any, id uuid.UUID) int {
+	res, ok := key.safeCompare(key.value(state), value)
+	if !ok {
+		return 0
+	}
+
+	if res == 0 && key.field != "id" {
+		res = compareValues(*key.fields(state).Id, id)
+	}
+
+	if key.descending {
+		return -res
+	}
+
+	return res
+}
+
+// safeCompare protects against cursors built for another sort
+func (key sortKey[T]) safeCompare(a, b any) (res int, ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+
+	return compareValues(a, b), true
+}
+
+func (key sortKey[T]) cursor(state *T, backward bool) (string, error) {
+	return encodeCursor(key.value(state), *key.fields(state).Id, backward)
+}
+
+func (key sortKey[T]) sort(states []T) {
+	sort.SliceStable(states, func(i, j int) bool {
+		return key.compare(&states[i], key.value(&states[j]), *key.fields(&states[j]).Id) < 0
+	})
+}
+
+func (key sortKey[T]) mongoSort(backward bool) bson.D {
+	direction := 1
+	if key.descending != backward {
+		direction = -1
+	}
+
+	if key.field == "id" {
+		return bson.D{{Key: "id", Value: direction}}
+	}
+
+	return bson.D{{Key: key.field, Value: direction}, {Key: "id", Value: direction}}
+}
+
+func (key sortKey[T]) mongoQuery(cursor pageCursor) bson.M {
+	operator := "$gt"
+	if key.descending != cursor.Backward {
+		operator = "$lt"
+	}
+
+	if key.field == "id" {
+		return bson.M{"id": bson.M{operator: cursor.Value}}
+	}
+
+	return bson.M{"$or": bson.A{
+		bson.M{key.field: bson.M{operator: cursor.Value}},
+		bson.M{key.field: cursor.Value, "id": bson.M{operator: cursor.Id}},
+	}}
+}
+
+func (key sortKey[T]) withCursors(page *Page[T], hasPrev, hasNext bool) error {
+	if len(page.Items) == 0 {
+		return nil
+	}
+
+	var err error
+	if hasPrev {
+		page.PrevCursor, err = key.cursor(&page.Items[0], true)
+		if err != nil {
+			return err
+		}
+	}
+
+	if hasNext {
+		page.NextCursor, err = key.cursor(&page.Items[len(page.Items)-1], false)
+	}
+
+	return err
+}
+
+// keysetPage pages states already filtered and sorted by the key
+func keysetPage[T any](states []T, key sortKey[T], page, size int64, cursor string) (Page[T], error) {
+	if len(cursor) == 0 {
+		res := embeddedPage(states, page, size)
+		if page < 1 {
+			page = 1
+		}
+
+		return res, key.withCursors(&res, page > 1, size > 0 && page*size < res.TotalElements)
+	}
+
+	current, err := decodeCursor(cursor)
+	if err != nil {
+		return Page[T]{}, err
+	}
+
+	res := Page[T]{Items: make([]T, 0)}
+	if current.Backward {
+		end := len(states)
+		for end > 0 && key.compare(&states[end-1], current.Value, current.Id) >= 0 {
+			end--
+		}
+
+		start := int64(end) - size
+		if size <= 0 || start < 0 {
+			start = 0
+		}
+
+		res.Items = append(res.Items, states[start:end]...)
+		return res, key.withCursors(&res, start > 0, true)
+	}
+
+	start := 0
+	for start < len(states) && key.compare(&states[start], current.Value, current.Id) <= 0 {
+		start++
+	}
+
+	end := int64(len(states))
+	if size > 0 && int64(start)+size < end {
+		end = int64(start) + size
+	}
+
+	res.Items = append(res.Items, states[start:end]...)
+	return res, key.withCursors(&res, true, end < int64(len(states)))
+}
+
+// findPage sorts by relevance when ranked, otherwise it pages by offset or, when a cursor is given, by keyset
+func findPage[T any](
+	ctx context.Context,
+	collection *mongo.Collection,
+	query bson.M,
+	key sortKey[T],
+	terms []string,
+	ranked bool,
+	page, size int64,
+	cursor string) (Page[T], error) {
+	res := Page[T]{Items: make([]T, 0)}
+	if len(cursor) > 0 {
+		current, err := decodeCursor(cursor)
+		if err != nil {
+			return res, err
+		}
+
+		keyset := bson.M{"$and": bson.A{query, key.mongoQuery(current)}}
+		found, err := collection.Find(ctx, keyset, options.Find().
+			SetSort(key.mongoSort(current.Backward)).
+			SetLimit(size+1))
+		if err != nil {
+			return res, err
+		}
+
+		if err = found.All(ctx, &res.Items); err != nil {
+			return res, err
+		}
+
+		more := int64(len(res.Items)) > size
+		if more {
+			res.Items = res.Items[:size]
+		}
+
+		if current.Backward {
+			for i, j := 0, len(res.Items)-1; i < j; i, j = i+1, j-1 {
+				res.Items[i], res.Items[j] = res.Items[j], res.Items[i]
+			}
+
+			return res, key.withCursors(&res, more, true)
+		}
+
+		return res, key.withCursors(&res, true, more)
+	}
+
+	totalElements, err := collection.CountDocuments(ctx, query)
+	if err != nil {
+		return res, err
+	}
+
+	res.TotalElements = totalElements
+	if totalElements > 0 {
+		res.TotalPages = int64(math.Ceil(float64(totalElements) / float64(size)))
+	}
+
+	var found *mongo.Cursor
+	if ranked {
+		found, err = collection.Aggregate(ctx, mongo.Pipeline{
+			{{Key: "$match", Value: query}},
+			{{Key: "$addFields", Value: bson.M{"_score": bson.M{"$size": bson.M{"$setIntersection": bson.A{
+				bson.M{"$ifNull": bson.A{"$" + searchField, bson.A{}}},
+				terms,
+			}}}}}},
+			{{Key: "$sort", Value: bson.D{{Key: "_score", Value: -1}, {Key: "id", Value: 1}}}},
+			{{Key: "$skip", Value: (page - 1) * size}},
+			{{Key: "$limit", Value: size}},
+			{{Key: "$project", Value: bson.M{"_score": 0}}},
+		})
+	} else {
+		found, err = collection.Find(ctx, query, options.Find().
+			SetSort(key.mongoSort(false)).
+			SetSkip((page-1)*size).
+			SetLimit(size))
+	}
+
+	if err != nil {
+		return res, err
+	}
+
+	if err = found.All(ctx, &res.Items); err != nil || ranked {
+		return res, err
+	}
+
+	return res, key.withCursors(&res, page > 1, page*size < totalElements)
+}
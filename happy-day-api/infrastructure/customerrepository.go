@@ -3,7 +3,6 @@ package infrastructure
 import (
 	"context"
 	"errors"
-	"math"
 	"time"
 
 	"happy_day/domain/customer"
@@ -12,7 +11,6 @@ import (
 	"github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 const (
@@ -41,14 +39,21 @@ type (
 		Page   int64
 		Size   int64
 		SortBy CustomerSortBy
+		Cursor string
 	}
 
 	CustomerRepository interface {
 		GetById(ctx context.Context, id uuid.UUID) (customer.State, error)
 		GetAll(ctx context.Context, filter CustomerFilter) (Page[customer.State], error)
 
+		GetDeleted(ctx context.Context) ([]customer.State, error)
+
 		Save(ctx context.Context, state customer.State) (customer.State, error)
 		Delete(ctx context.Context, id uuid.UUID) error
+		GetHistory(ctx context.Context, id uuid.UUID) ([]Revision, error)
+		GetRevision(ctx context.Context, id, revisionId uuid.UUID) (customer.State, error)
+		Restore(ctx context.Context, id uuid.UUID) error
+		Purge(ctx context.Context, before time.Time, keep []uuid.UUID) (int64, error)
 	}
 
 	MongoDbCustomerRepository struct {
@@ -70,6 +75,11 @@ func (repository *MockCustomerRepository) GetAll(ctx context.Context, filter Cus
 	return args.Get(0).(Page[customer.State]), args.Error(1)
 }
 
+func (repository *MockCustomerRepository) GetDeleted(ctx context.Context) ([]customer.State, error) {
+	args := repository.Called(ctx)
+	return args.Get(0).([]customer.State), args.Error(1)
+}
+
 func (repository *MockCustomerRepository) Save(ctx context.Context, state customer.State) (customer.State, error) {
 	args := repository.Called(ctx, state)
 	return args.Get(0).(customer.State), args.Error(1)
@@ -80,81 +90,71 @@ func (repository *MockCustomerRepository) Delete(ctx context.Context, id uuid.UU
 	return args.Error(0)
 }
 
-func (repository MongoDbCustomerRepository) GetAll(ctx context.Context, filter CustomerFilter) (Page[customer.State], error) {
-	opt := options.Find().
-		SetSkip((filter.Page - 1) * filter.Size).
-		SetLimit(filter.Size)
-
-	if filter.SortBy == CustomerIdAsc {
-		opt.SetSort(bson.D{{"id", 1}})
-	} else if filter.SortBy == CustomerIdDesc {
-		opt.SetSort(bson.D{{"id", -1}})
-	} else if filter.SortBy == CustomerNameAsc {
-		opt.SetSort(bson.D{{"name", 1}})
-	} else if filter.SortBy == CustomerNameDesc {
-		opt.SetSort(bson.D{{"name", -1}})
-	} else if filter.SortBy == CustomerCommentAsc {
-		opt.SetSort(bson.D{{"comment", 1}})
-	} else if filter.SortBy == CustomerCommentDesc {
-		opt.SetSort(bson.D{{"comment", -1}})
-	}
+func (repository *MockCustomerRepository) GetHistory(ctx context.Context, id uuid.UUID) ([]Revision, error) {
+	args := repository.Called(ctx, id)
+	return args.Get(0).([]Revision), args.Error(1)
+}
 
-	query := bson.M{}
-	if len(filter.Text) > 0 {
-		query["$or"] = []interface{}{
-			bson.M{"id": bson.M{"$regex": filter.Text, "$options": "im"}},
-			bson.M{"name": bson.M{"$regex": filter.Text, "$options": "im"}},
-			bson.M{"comment": bson.M{"$regex": filter.Text, "$options": "im"}},
-			bson.M{"phones": bson.M{"$elemMatch": bson.M{"number": bson.M{"$regex": filter.Text, "$options": "im"}}}},
-		}
-	}
+func (repository *MockCustomerRepository) GetRevision(ctx context.Context, id, revisionId uuid.UUID) (customer.State, error) {
+	args := repository.Called(ctx, id, revisionId)
+	return args.Get(0).(customer.State), args.Error(1)
+}
 
-	client, err := repository.CreateClient(ctx)
-	var page Page[customer.State]
-	if err != nil {
-		return page, err
-	}
+func (repository *MockCustomerRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	args := repository.Called(ctx, id)
+	return args.Error(0)
+}
 
-	defer client.Disconnect(ctx)
-	collection := client.
-		Database(Database).
-		Collection(CustomersCollection)
+func (repository *MockCustomerRepository) Purge(ctx context.Context, before time.Time, keep []uuid.UUID) (int64, error) {
+	args := repository.Called(ctx, before, keep)
+	return args.Get(0).(int64), args.Error(1)
+}
 
-	totalElements, err := collection.CountDocuments(ctx, query)
-	if err != nil {
-		return page, err
+func customerSortKey(sortBy CustomerSortBy) sortKey[customer.State] {
+	switch sortBy {
+	case CustomerIdDesc:
+		return idSortKey(customerFields, true)
+	case CustomerNameAsc, CustomerNameDesc:
+		return sortKey[customer.State]{
+			field:      "name",
+			descending: sortBy == CustomerNameDesc,
+			value:      func(state *customer.State) any { return state.Name },
+			fields:     customerFields,
+		}
+	case CustomerCommentAsc, CustomerCommentDesc:
+		return sortKey[customer.State]{
+			field:      "comment",
+			descending: sortBy == CustomerCommentDesc,
+			value:      func(state *customer.State) any { return state.Comment },
+			fields:     customerFields,
+		}
 	}
 
-	page.Items = make([]customer.State, 0)
-	page.TotalElements = totalElements
-	if totalElements > 0 {
-		tmp := float64(totalElements) / float64(filter.Size)
-		tmp = math.Ceil(tmp)
-		page.TotalPages = int64(tmp)
-	}
+	return idSortKey(customerFields, false)
+}
 
-	cursor, err := collection.Find(ctx, query, opt)
-	if err != nil {
-		return page, err
+func (repository MongoDbCustomerRepository) GetAll(ctx context.Context, filter CustomerFilter) (Page[customer.State], error) {
+	query := bson.M{"deletedAt": nil}
+	terms := searchTokens(filter.Text)
+	if len(terms) > 0 {
+		query[searchField] = searchQuery(terms)
 	}
 
-	err = cursor.All(ctx, &page.Items)
-	return page, nil
+	collection := repository.client.
+		Database(Database).
+		Collection(CustomersCollection)
+
+	ranked := len(terms) > 0 && len(filter.SortBy) == 0 && len(filter.Cursor) == 0
+	return findPage(ctx, collection, query, customerSortKey(filter.SortBy), terms, ranked, filter.Page, filter.Size, filter.Cursor)
 }
 
 func (repository MongoDbCustomerRepository) GetById(ctx context.Context, id uuid.UUID) (customer.State, error) {
-	query := bson.M{"id": id}
-	client, err := repository.CreateClient(ctx)
-	if err != nil {
-		return customer.State{}, err
-	}
-
-	defer client.Disconnect(ctx)
-	decode := client.Database(Database).
+	query := bson.M{"id": id, "deletedAt": nil}
+	decode := repository.client.Database(Database).
 		Collection(CustomersCollection).
 		FindOne(ctx, query)
 
-	err = decode.Err()
+	err := decode.Err()
 	if err == mongo.ErrNoDocuments {
 		return customer.State{}, ErrCustomerNotFound
 	}
@@ -169,45 +169,75 @@ func (repository MongoDbCustomerRepository) GetById(ctx context.Context, id uuid
 }
 
 func (repository MongoDbCustomerRepository) Save(ctx context.Context, state customer.State) (customer.State, error) {
-	client, err := repository.CreateClient(ctx)
-	if err != nil {
-		return customer.State{}, err
-	}
-
-	defer client.Disconnect(ctx)
-	collection := client.Database(Database).
+	collection := repository.client.Database(Database).
 		Collection(CustomersCollection)
 
+	// Only softDelete and restore change the deleted fields
+	state.DeletedAt = nil
+	state.DeletedBy = ""
 	if state.Id == uuid.Nil {
 		state.Id = uuid.New()
-		state.CreatedAt = time.Now().UTC()
-		state.ModifiedAt = time.Now().UTC()
-		_, err = collection.InsertOne(ctx, state)
-		return state, err
+		state.CreatedAt = now()
+		state.ModifiedAt = state.CreatedAt
+		_, err := collection.InsertOne(ctx, searchDocument[customer.State]{State: state, Search: customerSearchTokens(state)})
+		if err != nil {
+			return state, err
+		}
+
+		return state, repository.saveRevision(ctx, CustomersCollection, state.Id, state)
 	}
 
 	lastChange := state.ModifiedAt
-	state.ModifiedAt = time.Now().UTC()
+	state.ModifiedAt = now()
 
-	res, err := collection.ReplaceOne(ctx, bson.M{"id": state.Id, "modifiedAt": lastChange}, state)
+	res, err := collection.ReplaceOne(ctx,
+		bson.M{"id": state.Id, "modifiedAt": lastChange, "deletedAt": nil},
+		searchDocument[customer.State]{State: state, Search: customerSearchTokens(state)})
+	if err != nil {
+		return state, err
+	}
 
-	if res.ModifiedCount == 0 {
+	if res.MatchedCount == 0 {
 		return state, ErrCustomerConcurrencyIssue
 	}
 
-	return state, err
+	return state, repository.saveRevision(ctx, CustomersCollection, state.Id, state)
+}
+
+func (repository MongoDbCustomerRepository) GetDeleted(ctx context.Context) ([]customer.State, error) {
+	customers := make([]customer.State, 0)
+	err := repository.findDeleted(ctx, CustomersCollection, &customers)
+	return customers, err
 }
 
 func (repository MongoDbCustomerRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := bson.M{"id": id}
-	client, err := repository.CreateClient(ctx)
-	if err != nil {
-		return err
+	deleted, err := repository.softDelete(ctx, CustomersCollection, id)
+	if err == nil && !deleted {
+		return ErrCustomerNotFound
 	}
 
-	defer client.Disconnect(ctx)
-	_, err = client.Database(Database).
-		Collection(CustomersCollection).
-		DeleteOne(ctx, query)
 	return err
 }
+
+func (repository MongoDbCustomerRepository) GetHistory(ctx context.Context, id uuid.UUID) ([]Revision, error) {
+	return history[customer.State](ctx, repository.MongoDbRepository, CustomersCollection, id)
+}
+
+func (repository MongoDbCustomerRepository) GetRevision(ctx context.Context, id, revisionId uuid.UUID) (customer.State, error) {
+	var state customer.State
+	err := repository.revision(ctx, CustomersCollection, id, revisionId, &state)
+	return state, err
+}
+
+func (repository MongoDbCustomerRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	restored, err := repository.restore(ctx, CustomersCollection, id)
+	if err == nil && !restored {
+		return ErrCustomerNotFound
+	}
+
+	return err
+}
+
+func (repository MongoDbCustomerRepository) Purge(ctx context.Context, before time.Time, keep []uuid.UUID) (int64, error) {
+	return repository.purge(ctx, CustomersCollection, before, keep)
+}
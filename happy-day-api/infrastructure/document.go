@@ -0,0 +1,117 @@
+package infrastructure
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/spf13/viper"
+)
+
+type DocumentOptions struct {
+	TemplatesPath string
+	Location      *time.Location
+}
+
+func ProvideDocumentOptions() DocumentOptions {
+	return DocumentOptions{
+		TemplatesPath: viper.GetString("documents.templates_path"),
+		Location:      BusinessLocation(),
+	}
+}
+
+var documentFuncs = template.FuncMap{
+	"money": func(value float64) string {
+		text := fmt.Sprintf("%.2f", value)
+		integer, decimal, _ := strings.Cut(text, ".")
+		negative := strings.HasPrefix(integer, "-")
+		integer = strings.TrimPrefix(integer, "-")
+
+		var res strings.Builder
+		for i, c := range integer {
+			if i > 0 && (len(integer)-i)%3 == 0 {
+				res.WriteByte('.')
+			}
+
+			res.WriteRune(c)
+		}
+
+		if negative {
+			return "-R$ " + res.String() + "," + decimal
+		}
+
+		return "R$ " + res.String() + "," + decimal
+	},
+	"inc": func(value int) int {
+		return value + 1
+	},
+}
+
+// dateFuncs prints the dates in the business time zone so a party never moves to another day
+func dateFuncs(location *time.Location) template.FuncMap {
+	if location == nil {
+		location = time.UTC
+	}
+
+	return template.FuncMap{
+		"date": func(value time.Time) string {
+			return value.In(location).Format("02/01/2006")
+		},
+		"datetime": func(value time.Time) string {
+			return value.In(location).Format("02/01/2006 15:04")
+		},
+	}
+}
+
+func RenderPdf(options DocumentOptions, name string, data any) ([]byte, error) {
+	tmpl, err := template.New(name).
+		Funcs(documentFuncs).
+		Funcs(dateFuncs(options.Location)).
+		ParseFiles(filepath.Join(options.TemplatesPath, name))
+	if err != nil {
+		return nil, err
+	}
+
+	var text bytes.Buffer
+	err = tmpl.Execute(&text, data)
+	if err != nil {
+		return nil, err
+	}
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	translate := pdf.UnicodeTranslatorFromDescriptor("")
+	left, _, right, _ := pdf.GetMargins()
+	width, _ := pdf.GetPageSize()
+
+	pdf.AddPage()
+	for _, line := range strings.Split(text.String(), "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		switch {
+		case strings.HasPrefix(line, "# "):
+			pdf.SetFont("Helvetica", "B", 16)
+			pdf.MultiCell(0, 8, translate(line[2:]), "", "C", false)
+			pdf.Ln(2)
+		case strings.HasPrefix(line, "## "):
+			pdf.Ln(2)
+			pdf.SetFont("Helvetica", "B", 12)
+			pdf.MultiCell(0, 6, translate(line[3:]), "", "L", false)
+		case line == "---":
+			y := pdf.GetY() + 2
+			pdf.Line(left, y, width-right, y)
+			pdf.Ln(4)
+		case len(line) == 0:
+			pdf.Ln(3)
+		default:
+			pdf.SetFont("Helvetica", "", 10)
+			pdf.MultiCell(0, 5, translate(line), "", "L", false)
+		}
+	}
+
+	var res bytes.Buffer
+	err = pdf.Output(&res)
+	return res.Bytes(), err
+}
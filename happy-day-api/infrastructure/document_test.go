@@ -0,0 +1,17 @@
+package infrastructure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocumentMoney(t *testing.T) {
+	money := documentFuncs["money"].(func(float64) string)
+
+	assert.Equal(t, "R$ 0,00", money(0))
+	assert.Equal(t, "R$ 12,50", money(12.5))
+	assert.Equal(t, "R$ 1.250,00", money(1250))
+	assert.Equal(t, "R$ 1.234.567,89", money(1234567.891))
+	assert.Equal(t, "-R$ 100,00", money(-100))
+}
@@ -0,0 +1,81 @@
+package infrastructure
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type (
+	embeddedFields struct {
+		Id         *uuid.UUID
+		CreatedAt  *time.Time
+		ModifiedAt *time.Time
+		DeletedAt  **time.Time
+		DeletedBy  *string
+	}
+
+	embeddedCollection[T any] interface {
+		find(filter func(state T) bool) ([]T, error)
+		get(id uuid.UUID) (T, bool, error)
+		save(ctx context.Context, state T, concurrencyErr error) (T, error)
+		update(ctx context.Context, filter func(state T) bool, change func(state *T)) error
+		softDelete(ctx context.Context, id uuid.UUID) (bool, error)
+		restore(ctx context.Context, id uuid.UUID) (bool, error)
+		deleted() ([]T, error)
+		purge(before time.Time, keep []uuid.UUID) (int64, error)
+		history(id uuid.UUID) ([]Revision, error)
+		revision(id, revisionId uuid.UUID) (T, error)
+	}
+)
+
+func cloneState[T any](state T) (T, error) {
+	var clone T
+	raw, err := bson.MarshalWithRegistry(mongoDbRegistry, state)
+	if err != nil {
+		return clone, err
+	}
+
+	err = bson.UnmarshalWithRegistry(mongoDbRegistry, raw, &clone)
+	return clone, err
+}
+
+func embeddedPage[T any](states []T, page, size int64) Page[T] {
+	res := Page[T]{
+		Items:         make([]T, 0),
+		TotalElements: int64(len(states)),
+	}
+
+	if size <= 0 {
+		res.Items = states
+		if len(states) > 0 {
+			res.TotalPages = 1
+		}
+
+		return res
+	}
+
+	if res.TotalElements > 0 {
+		res.TotalPages = int64(math.Ceil(float64(res.TotalElements) / float64(size)))
+	}
+
+	if page < 1 {
+		page = 1
+	}
+
+	start := (page - 1) * size
+	if start >= res.TotalElements {
+		return res
+	}
+
+	end := start + size
+	if end > res.TotalElements {
+		end = res.TotalElements
+	}
+
+	res.Items = states[start:end]
+	return res
+}
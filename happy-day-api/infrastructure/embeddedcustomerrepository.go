@@ -0,0 +1,102 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"happy_day/domain/customer"
+
+	"github.com/google/uuid"
+	"go.etcd.io/bbolt"
+)
+
+var _ CustomerRepository = (*EmbeddedCustomerRepository)(nil)
+
+type EmbeddedCustomerRepository struct {
+	collection embeddedCollection[customer.State]
+}
+
+func NewMemoryCustomerRepository() *EmbeddedCustomerRepository {
+	return &EmbeddedCustomerRepository{collection: newMemoryCollection(CustomersCollection, customerFields)}
+}
+
+func NewBoltCustomerRepository(db *bbolt.DB) *EmbeddedCustomerRepository {
+	return &EmbeddedCustomerRepository{collection: newBoltCollection(db, CustomersCollection, customerFields)}
+}
+
+func customerFields(state *customer.State) embeddedFields {
+	return embeddedFields{
+		Id:         &state.Id,
+		CreatedAt:  &state.CreatedAt,
+		ModifiedAt: &state.ModifiedAt,
+		DeletedAt:  &state.DeletedAt,
+		DeletedBy:  &state.DeletedBy,
+	}
+}
+
+func (repository *EmbeddedCustomerRepository) GetById(_ context.Context, id uuid.UUID) (customer.State, error) {
+	state, exists, err := repository.collection.get(id)
+	if err == nil && !exists {
+		return state, ErrCustomerNotFound
+	}
+
+	return state, err
+}
+
+func (repository *EmbeddedCustomerRepository) GetAll(_ context.Context, filter CustomerFilter) (Page[customer.State], error) {
+	terms := searchTokens(filter.Text)
+	customers, err := repository.collection.find(func(state customer.State) bool {
+		matched, _ := matchSearch(terms, customerSearchTokens(state))
+		return matched
+	})
+	if err != nil {
+		return Page[customer.State]{}, err
+	}
+
+	if len(terms) > 0 && len(filter.SortBy) == 0 && len(filter.Cursor) == 0 {
+		rankBySearch(customers, terms, customerSearchTokens)
+		return embeddedPage(customers, filter.Page, filter.Size), nil
+	}
+
+	key := customerSortKey(filter.SortBy)
+	key.sort(customers)
+	return keysetPage(customers, key, filter.Page, filter.Size, filter.Cursor)
+}
+
+func (repository *EmbeddedCustomerRepository) GetDeleted(_ context.Context) ([]customer.State, error) {
+	return repository.collection.deleted()
+}
+
+func (repository *EmbeddedCustomerRepository) Save(ctx context.Context, state customer.State) (customer.State, error) {
+	return repository.collection.save(ctx, state, ErrCustomerConcurrencyIssue)
+}
+
+func (repository *EmbeddedCustomerRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	deleted, err := repository.collection.softDelete(ctx, id)
+	if err == nil && !deleted {
+		return ErrCustomerNotFound
+	}
+
+	return err
+}
+
+func (repository *EmbeddedCustomerRepository) GetHistory(_ context.Context, id uuid.UUID) ([]Revision, error) {
+	return repository.collection.history(id)
+}
+
+func (repository *EmbeddedCustomerRepository) GetRevision(_ context.Context, id, revisionId uuid.UUID) (customer.State, error) {
+	return repository.collection.revision(id, revisionId)
+}
+
+func (repository *EmbeddedCustomerRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	restored, err := repository.collection.restore(ctx, id)
+	if err == nil && !restored {
+		return ErrCustomerNotFound
+	}
+
+	return err
+}
+
+func (repository *EmbeddedCustomerRepository) Purge(_ context.Context, before time.Time, keep []uuid.UUID) (int64, error) {
+	return repository.collection.purge(before, keep)
+}
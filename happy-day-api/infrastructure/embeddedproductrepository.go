@@ -0,0 +1,162 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"happy_day/domain/product"
+
+	"github.com/google/uuid"
+	"go.etcd.io/bbolt"
+)
+
+var _ ProductRepository = (*EmbeddedProductRepository)(nil)
+
+type EmbeddedProductRepository struct {
+	collection embeddedCollection[product.State]
+}
+
+func NewMemoryProductRepository() *EmbeddedProductRepository {
+	return &EmbeddedProductRepository{collection: newMemoryCollection(ProductCollection, productFields)}
+}
+
+func NewBoltProductRepository(db *bbolt.DB) *EmbeddedProductRepository {
+	return &EmbeddedProductRepository{collection: newBoltCollection(db, ProductCollection, productFields)}
+}
+
+func productFields(state *product.State) embeddedFields {
+	return embeddedFields{
+		Id:         &state.Id,
+		CreatedAt:  &state.CreatedAt,
+		ModifiedAt: &state.ModifiedAt,
+		DeletedAt:  &state.DeletedAt,
+		DeletedBy:  &state.DeletedBy,
+	}
+}
+
+func (repository *EmbeddedProductRepository) GetById(_ context.Context, id uuid.UUID) (product.State, error) {
+	state, exists, err := repository.collection.get(id)
+	if err == nil && !exists {
+		return state, ErrProductNotFound
+	}
+
+	return state, err
+}
+
+func (repository *EmbeddedProductRepository) GetByProducts(_ context.Context, productsId []uuid.UUID) ([]product.State, error) {
+	ids := map[uuid.UUID]bool{}
+	for _, id := range productsId {
+		ids[id] = true
+	}
+
+	products, err := repository.collection.find(func(state product.State) bool {
+		return ids[state.Id]
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(productsId) != len(products) {
+		return nil, ErrOneProductNotFound
+	}
+
+	return products, nil
+}
+
+func (repository *EmbeddedProductRepository) GetComposed(_ context.Context, productsId []uuid.UUID) ([]product.State, error) {
+	ids := map[uuid.UUID]bool{}
+	for _, id := range productsId {
+		ids[id] = true
+	}
+
+	return repository.collection.find(func(state product.State) bool {
+		if len(state.Products) == 0 {
+			return false
+		}
+
+		for _, item := range state.Products {
+			if !ids[item.Id] {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+func (repository *EmbeddedProductRepository) GetAll(_ context.Context, filter ProductFilter) (Page[product.State], error) {
+	terms := searchTokens(filter.Text)
+	products, err := repository.collection.find(func(state product.State) bool {
+		matched, _ := matchSearch(terms, productSearchTokens(state))
+		return matched
+	})
+	if err != nil {
+		return Page[product.State]{}, err
+	}
+
+	if len(terms) > 0 && len(filter.SortBy) == 0 && len(filter.Cursor) == 0 {
+		rankBySearch(products, terms, productSearchTokens)
+		return embeddedPage(products, filter.Page, filter.Size), nil
+	}
+
+	key := productSortKey(filter.SortBy)
+	key.sort(products)
+	return keysetPage(products, key, filter.Page, filter.Size, filter.Cursor)
+}
+
+func (repository *EmbeddedProductRepository) Exists(_ context.Context, id uuid.UUID) (bool, error) {
+	_, exists, err := repository.collection.get(id)
+	return exists, err
+}
+
+func (repository *EmbeddedProductRepository) ExistAnyWithProduct(_ context.Context, productId uuid.UUID) (bool, error) {
+	products, err := repository.collection.find(func(state product.State) bool {
+		for _, item := range state.Products {
+			if item.Id == productId {
+				return true
+			}
+		}
+
+		return false
+	})
+
+	return len(products) > 0, err
+}
+
+func (repository *EmbeddedProductRepository) GetDeleted(_ context.Context) ([]product.State, error) {
+	return repository.collection.deleted()
+}
+
+func (repository *EmbeddedProductRepository) Save(ctx context.Context, state product.State) (product.State, error) {
+	return repository.collection.save(ctx, state, ErrProductConcurrencyIssue)
+}
+
+func (repository *EmbeddedProductRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	deleted, err := repository.collection.softDelete(ctx, id)
+	if err == nil && !deleted {
+		return ErrProductNotFound
+	}
+
+	return err
+}
+
+func (repository *EmbeddedProductRepository) GetHistory(_ context.Context, id uuid.UUID) ([]Revision, error) {
+	return repository.collection.history(id)
+}
+
+func (repository *EmbeddedProductRepository) GetRevision(_ context.Context, id, revisionId uuid.UUID) (product.State, error) {
+	return repository.collection.revision(id, revisionId)
+}
+
+func (repository *EmbeddedProductRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	restored, err := repository.collection.restore(ctx, id)
+	if err == nil && !restored {
+		return ErrProductNotFound
+	}
+
+	return err
+}
+
+func (repository *EmbeddedProductRepository) Purge(_ context.Context, before time.Time, keep []uuid.UUID) (int64, error) {
+	return repository.collection.purge(before, keep)
+}
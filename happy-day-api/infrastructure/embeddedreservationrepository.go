@@ -0,0 +1,245 @@
+package infrastructure
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"happy_day/common"
+	"happy_day/domain/customer"
+	"happy_day/domain/reservation"
+
+	"github.com/google/uuid"
+	"go.etcd.io/bbolt"
+)
+
+var _ ReservationRepository = (*EmbeddedReservationRepository)(nil)
+
+type EmbeddedReservationRepository struct {
+	collection embeddedCollection[reservation.State]
+}
+
+func NewMemoryReservationRepository() *EmbeddedReservationRepository {
+	return &EmbeddedReservationRepository{collection: newMemoryCollection(ReservationCollection, reservationFields)}
+}
+
+func NewBoltReservationRepository(db *bbolt.DB) *EmbeddedReservationRepository {
+	return &EmbeddedReservationRepository{collection: newBoltCollection(db, ReservationCollection, reservationFields)}
+}
+
+func reservationFields(state *reservation.State) embeddedFields {
+	return embeddedFields{
+		Id:         &state.Id,
+		CreatedAt:  &state.CreatedAt,
+		ModifiedAt: &state.ModifiedAt,
+		DeletedAt:  &state.DeletedAt,
+		DeletedBy:  &state.DeletedBy,
+	}
+}
+
+func (repository *EmbeddedReservationRepository) GetAll(_ context.Context, filter ReservationFilter) (Page[reservation.State], error) {
+	terms := searchTokens(filter.Text)
+	statuses := map[reservation.Status]bool{}
+	for _, status := range filter.Status {
+		statuses[status] = true
+	}
+
+	reservations, err := repository.collection.find(func(state reservation.State) bool {
+		if len(statuses) > 0 && !statuses[state.Status] {
+			return false
+		}
+
+		if !matchReservationFilter(filter, state) {
+			return false
+		}
+
+		matched, _ := matchSearch(terms, reservationSearchTokens(state))
+		return matched
+	})
+	if err != nil {
+		return Page[reservation.State]{}, err
+	}
+
+	if len(terms) > 0 && len(filter.SortBy) == 0 && len(filter.Cursor) == 0 {
+		rankBySearch(reservations, terms, reservationSearchTokens)
+		return embeddedPage(reservations, filter.Page, filter.Size), nil
+	}
+
+	key := reservationSortKey(filter.SortBy)
+	key.sort(reservations)
+	return keysetPage(reservations, key, filter.Page, filter.Size, filter.Cursor)
+}
+
+func (repository *EmbeddedReservationRepository) GetById(_ context.Context, id uuid.UUID) (reservation.State, error) {
+	state, exists, err := repository.collection.get(id)
+	if err == nil && !exists {
+		return state, ErrReservationNotFound
+	}
+
+	return state, err
+}
+
+func (repository *EmbeddedReservationRepository) GetByPeriod(_ context.Context, from, to time.Time) ([]reservation.State, error) {
+	return repository.collection.find(func(state reservation.State) bool {
+		return state.Delivery.At.Before(to) && state.PickUp.At.After(from) && state.Status != reservation.Cancelled
+	})
+}
+
+func (repository *EmbeddedReservationRepository) GetByCustomer(_ context.Context, customerId uuid.UUID) ([]reservation.State, error) {
+	return repository.collection.find(func(state reservation.State) bool {
+		return state.CustomerId == customerId
+	})
+}
+
+func (repository *EmbeddedReservationRepository) GetByStaff(_ context.Context, staffId uuid.UUID, from, to time.Time) ([]reservation.State, error) {
+	return repository.collection.find(func(state reservation.State) bool {
+		if state.Status == reservation.Cancelled {
+			return false
+		}
+
+		return (common.Contains(state.Delivery.By, staffId) && inPeriod(state.Delivery.At, from, to)) ||
+			(common.Contains(state.PickUp.By, staffId) && inPeriod(state.PickUp.At, from, to))
+	})
+}
+
+func (repository *EmbeddedReservationRepository) ExistsOpenWithProduct(_ context.Context, productId uuid.UUID) (bool, error) {
+	reservations, err := repository.collection.find(func(state reservation.State) bool {
+		if !isOpenReservation(state) {
+			return false
+		}
+
+		for _, item := range state.Products {
+			if item.Id == productId {
+				return true
+			}
+		}
+
+		return false
+	})
+
+	return len(reservations) > 0, err
+}
+
+func (repository *EmbeddedReservationRepository) ExistsOpenWithCustomer(_ context.Context, customerId uuid.UUID) (bool, error) {
+	reservations, err := repository.collection.find(func(state reservation.State) bool {
+		return isOpenReservation(state) && state.CustomerId == customerId
+	})
+
+	return len(reservations) > 0, err
+}
+
+func (repository *EmbeddedReservationRepository) ExistsOpenWithStaff(_ context.Context, staffId uuid.UUID) (bool, error) {
+	reservations, err := repository.collection.find(func(state reservation.State) bool {
+		return isOpenReservation(state) && (common.Contains(state.Delivery.By, staffId) || common.Contains(state.PickUp.By, staffId))
+	})
+
+	return len(reservations) > 0, err
+}
+
+func (repository *EmbeddedReservationRepository) GetDeleted(_ context.Context) ([]reservation.State, error) {
+	return repository.collection.deleted()
+}
+
+func (repository *EmbeddedReservationRepository) Save(ctx context.Context, state reservation.State) (reservation.State, error) {
+	return repository.collection.save(ctx, state, ErrReservationConcurrencyIssue)
+}
+
+func (repository *EmbeddedReservationRepository) UpdateCustomer(ctx context.Context, state customer.State) error {
+	return repository.collection.update(ctx, func(item reservation.State) bool {
+		return item.CustomerId == state.Id &&
+			item.Status != reservation.PickedUp &&
+			item.Status != reservation.Closed &&
+			item.Status != reservation.Cancelled
+	}, func(item *reservation.State) {
+		item.Customer = reservation.Customer{State: state}
+	})
+}
+
+func (repository *EmbeddedReservationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	deleted, err := repository.collection.softDelete(ctx, id)
+	if err == nil && !deleted {
+		return ErrReservationNotFound
+	}
+
+	return err
+}
+
+func (repository *EmbeddedReservationRepository) GetHistory(_ context.Context, id uuid.UUID) ([]Revision, error) {
+	return repository.collection.history(id)
+}
+
+func (repository *EmbeddedReservationRepository) GetRevision(_ context.Context, id, revisionId uuid.UUID) (reservation.State, error) {
+	return repository.collection.revision(id, revisionId)
+}
+
+func (repository *EmbeddedReservationRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	restored, err := repository.collection.restore(ctx, id)
+	if err == nil && !restored {
+		return ErrReservationNotFound
+	}
+
+	return err
+}
+
+func (repository *EmbeddedReservationRepository) Purge(_ context.Context, before time.Time) (int64, error) {
+	return repository.collection.purge(before, nil)
+}
+
+func (repository *EmbeddedReservationRepository) GetReferences(_ context.Context) (ReservationReferences, error) {
+	reservations, err := repository.collection.find(func(reservation.State) bool { return true })
+	if err != nil {
+		return ReservationReferences{}, err
+	}
+
+	deleted, err := repository.collection.deleted()
+	if err != nil {
+		return ReservationReferences{}, err
+	}
+
+	return reservationReferences(append(reservations, deleted...)), nil
+}
+
+func isOpenReservation(state reservation.State) bool {
+	return state.Status != reservation.Closed && state.Status != reservation.Cancelled
+}
+
+func matchReservationFilter(filter ReservationFilter, state reservation.State) bool {
+	if filter.CustomerId != uuid.Nil && state.CustomerId != filter.CustomerId {
+		return false
+	}
+
+	if filter.ProductId != uuid.Nil && !common.Any(state.Products, func(item reservation.Product) bool {
+		return item.Id == filter.ProductId
+	}) {
+		return false
+	}
+
+	if !inPeriod(state.Delivery.At, filter.DeliveryFrom, filter.DeliveryTo) ||
+		!inPeriod(state.PickUp.At, filter.PickUpFrom, filter.PickUpTo) {
+		return false
+	}
+
+	if len(filter.City) > 0 && !strings.EqualFold(state.Address.City, filter.City) {
+		return false
+	}
+
+	if len(filter.Neighborhood) > 0 && !strings.EqualFold(state.Address.Neighborhood, filter.Neighborhood) {
+		return false
+	}
+
+	if len(filter.PaymentMethod) > 0 && !common.Any(state.PaymentInstallments, func(item reservation.PaymentInstallment) bool {
+		return item.Method == filter.PaymentMethod
+	}) {
+		return false
+	}
+
+	if filter.By != uuid.Nil && !common.Contains(state.Delivery.By, filter.By) && !common.Contains(state.PickUp.By, filter.By) {
+		return false
+	}
+
+	return filter.HasBalanceDue == nil || *filter.HasBalanceDue == (state.BalanceDue > 0)
+}
+
+func inPeriod(at, from, to time.Time) bool {
+	return (from.IsZero() || !at.Before(from)) && (to.IsZero() || at.Before(to))
+}
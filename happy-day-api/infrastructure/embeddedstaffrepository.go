@@ -0,0 +1,122 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"happy_day/domain/staff"
+
+	"github.com/google/uuid"
+	"go.etcd.io/bbolt"
+)
+
+var _ StaffRepository = (*EmbeddedStaffRepository)(nil)
+
+type EmbeddedStaffRepository struct {
+	collection embeddedCollection[staff.State]
+}
+
+func NewMemoryStaffRepository() *EmbeddedStaffRepository {
+	return &EmbeddedStaffRepository{collection: newMemoryCollection(StaffCollection, staffFields)}
+}
+
+func NewBoltStaffRepository(db *bbolt.DB) *EmbeddedStaffRepository {
+	return &EmbeddedStaffRepository{collection: newBoltCollection(db, StaffCollection, staffFields)}
+}
+
+func staffFields(state *staff.State) embeddedFields {
+	return embeddedFields{
+		Id:         &state.Id,
+		CreatedAt:  &state.CreatedAt,
+		ModifiedAt: &state.ModifiedAt,
+		DeletedAt:  &state.DeletedAt,
+		DeletedBy:  &state.DeletedBy,
+	}
+}
+
+func (repository *EmbeddedStaffRepository) GetById(_ context.Context, id uuid.UUID) (staff.State, error) {
+	state, exists, err := repository.collection.get(id)
+	if err == nil && !exists {
+		return state, ErrStaffNotFound
+	}
+
+	return state, err
+}
+
+func (repository *EmbeddedStaffRepository) GetByIds(_ context.Context, ids []uuid.UUID) ([]staff.State, error) {
+	expected := map[uuid.UUID]bool{}
+	for _, id := range ids {
+		expected[id] = true
+	}
+
+	members, err := repository.collection.find(func(state staff.State) bool {
+		return expected[state.Id]
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ids) != len(members) {
+		return nil, ErrOneStaffNotFound
+	}
+
+	return members, nil
+}
+
+func (repository *EmbeddedStaffRepository) GetAll(_ context.Context, filter StaffFilter) (Page[staff.State], error) {
+	terms := searchTokens(filter.Text)
+	members, err := repository.collection.find(func(state staff.State) bool {
+		if filter.Active != nil && state.Active != *filter.Active {
+			return false
+		}
+
+		if len(filter.Role) > 0 && state.Role != filter.Role {
+			return false
+		}
+
+		matched, _ := matchSearch(terms, staffSearchTokens(state))
+		return matched
+	})
+	if err != nil {
+		return Page[staff.State]{}, err
+	}
+
+	if len(terms) > 0 && len(filter.SortBy) == 0 && len(filter.Cursor) == 0 {
+		rankBySearch(members, terms, staffSearchTokens)
+		return embeddedPage(members, filter.Page, filter.Size), nil
+	}
+
+	key := staffSortKey(filter.SortBy)
+	key.sort(members)
+	return keysetPage(members, key, filter.Page, filter.Size, filter.Cursor)
+}
+
+func (repository *EmbeddedStaffRepository) GetDeleted(_ context.Context) ([]staff.State, error) {
+	return repository.collection.deleted()
+}
+
+func (repository *EmbeddedStaffRepository) Save(ctx context.Context, state staff.State) (staff.State, error) {
+	return repository.collection.save(ctx, state, ErrStaffConcurrencyIssue)
+}
+
+func (repository *EmbeddedStaffRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	deleted, err := repository.collection.softDelete(ctx, id)
+	if err == nil && !deleted {
+		return ErrStaffNotFound
+	}
+
+	return err
+}
+
+func (repository *EmbeddedStaffRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	restored, err := repository.collection.restore(ctx, id)
+	if err == nil && !restored {
+		return ErrStaffNotFound
+	}
+
+	return err
+}
+
+func (repository *EmbeddedStaffRepository) Purge(_ context.Context, before time.Time, keep []uuid.UUID) (int64, error) {
+	return repository.collection.purge(before, keep)
+}
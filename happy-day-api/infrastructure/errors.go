@@ -6,11 +6,15 @@ var (
 	ErrCustomerNameIsEmpty    = errors.New("customer name is empty")
 	ErrCustomerPhonesIsEmpty  = errors.New("customer phones is empty")
 	ErrCustomerPhoneIsInvalid = errors.New("customer phone is invalid")
+	ErrCustomerInUse          = errors.New("customer has open reservations")
 
 	ErrProductNameIsEmpty               = errors.New("product name is empty")
 	ErrProductPriceIsLessThanZero       = errors.New("product price is less than zero")
+	ErrProductStockIsLessThanZero       = errors.New("product stock is less than zero")
 	ErrProductAmountIsInvalid           = errors.New("product amount is invalid")
 	ErrExistOtherProductWithThisProduct = errors.New("exist other product with this product")
+	ErrAvailabilityPeriodIsInvalid      = errors.New("availability period is invalid")
+	ErrProductInUse                     = errors.New("product has open reservations")
 
 	ErrReservationPaymentInstallmentAmount = errors.New("payment installment amount cannot be less or equal to zero")
 	ErrReservationAddressCityIsEmpty       = errors.New("address city cannot be empty")
@@ -18,4 +22,29 @@ var (
 	ErrReservationAddressNumberIsInvalid   = errors.New("address number cannot be empty")
 	ErrReservationAddressPostalCodeIsEmpty = errors.New("address postal code cannot be empty")
 	ErrProductListIsEmpty                  = errors.New("product list cannot be empty")
+	ErrReservationProductNotAvailable      = errors.New("product not available for the reservation period")
+	ErrQuoteStrategyIsInvalid              = errors.New("quote strategy is invalid")
+	ErrReservationInvalidStatusTransition  = errors.New("reservation status transition is invalid")
+	ErrReservationInstallmentsMismatch     = errors.New("payment installments total must match the final price")
+	ErrReservationInstallmentNotFound      = errors.New("payment installment not found")
+	ErrReservationInstallmentAlreadyPaid   = errors.New("payment installment already paid")
+	ErrPixIsNotConfigured                  = errors.New("pix is not configured")
+	ErrDocumentNotFound                    = errors.New("document not found")
+	ErrReservationFilterIsInvalid          = errors.New("reservation filter is invalid")
+	ErrReservationPriceMismatch            = errors.New("price does not match the quote for the products")
+	ErrReservationDiscountIsInvalid        = errors.New("discount is invalid")
+	ErrReservationScheduleIsInvalid        = errors.New("delivery and pick up are required and pick up must be after delivery")
+	ErrReservationLeadTimeIsTooShort       = errors.New("delivery is earlier than the minimum lead time")
+	ErrReservationOutsideBusinessHours     = errors.New("delivery and pick up must be within business hours")
+	ErrReservationInstallmentIsNotPix      = errors.New("payment installment method is not pix")
+
+	ErrStaffNameIsEmpty             = errors.New("staff name is empty")
+	ErrStaffPhoneIsEmpty            = errors.New("staff phone is empty")
+	ErrStaffRoleIsInvalid           = errors.New("staff role is invalid")
+	ErrStaffInUse                   = errors.New("staff has open reservations")
+	ErrStaffIsInactive              = errors.New("staff is inactive")
+	ErrStaffScheduleConflict        = errors.New("staff is already assigned to another reservation at this time")
+	ErrStaffSchedulePeriodIsInvalid = errors.New("staff schedule period is invalid")
+
+	ErrTrashKindIsInvalid = errors.New("trash kind is invalid")
 )
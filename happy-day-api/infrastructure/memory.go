@@ -0,0 +1,252 @@
+package infrastructure
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"happy_day/common"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var _ embeddedCollection[any] = (*memoryCollection[any])(nil)
+
+type memoryCollection[T any] struct {
+	mutex     sync.RWMutex
+	name      string
+	fields    func(state *T) embeddedFields
+	ids       []uuid.UUID
+	items     map[uuid.UUID]T
+	revisions []Revision
+}
+
+func newMemoryCollection[T any](name string, fields func(state *T) embeddedFields) *memoryCollection[T] {
+	return &memoryCollection[T]{
+		name:   name,
+		fields: fields,
+		items:  map[uuid.UUID]T{},
+	}
+}
+
+func (collection *memoryCollection[T]) find(filter func(state T) bool) ([]T, error) {
+	collection.mutex.RLock()
+	defer collection.mutex.RUnlock()
+
+	states := make([]T, 0)
+	for _, id := range collection.ids {
+		state := collection.items[id]
+		if *collection.fields(&state).DeletedAt != nil || !filter(state) {
+			continue
+		}
+
+		clone, err := cloneState(state)
+		if err != nil {
+			return nil, err
+		}
+
+		states = append(states, clone)
+	}
+
+	return states, nil
+}
+
+func (collection *memoryCollection[T]) get(id uuid.UUID) (T, bool, error) {
+	collection.mutex.RLock()
+	defer collection.mutex.RUnlock()
+
+	state, exists := collection.items[id]
+	if !exists || *collection.fields(&state).DeletedAt != nil {
+		var empty T
+		return empty, false, nil
+	}
+
+	clone, err := cloneState(state)
+	return clone, true, err
+}
+
+func (collection *memoryCollection[T]) save(ctx context.Context, state T, concurrencyErr error) (T, error) {
+	collection.mutex.Lock()
+	defer collection.mutex.Unlock()
+
+	fields := collection.fields(&state)
+	// Only softDelete and restore change the deleted fields
+	*fields.DeletedAt = nil
+	*fields.DeletedBy = ""
+	if *fields.Id == uuid.Nil {
+		*fields.Id = uuid.New()
+		*fields.CreatedAt = now()
+		*fields.ModifiedAt = *fields.CreatedAt
+		collection.ids = append(collection.ids, *fields.Id)
+	} else {
+		current, exists := collection.items[*fields.Id]
+		currentFields := collection.fields(&current)
+		if !exists || *currentFields.DeletedAt != nil || !currentFields.ModifiedAt.Equal(*fields.ModifiedAt) {
+			return state, concurrencyErr
+		}
+
+		*fields.ModifiedAt = now()
+	}
+
+	return state, collection.store(ctx, state, true)
+}
+
+func (collection *memoryCollection[T]) update(ctx context.Context, filter func(state T) bool, change func(state *T)) error {
+	collection.mutex.Lock()
+	defer collection.mutex.Unlock()
+
+	for _, id := range collection.ids {
+		state := collection.items[id]
+		if *collection.fields(&state).DeletedAt != nil || !filter(state) {
+			continue
+		}
+
+		change(&state)
+		*collection.fields(&state).ModifiedAt = now()
+		err := collection.store(ctx, state, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (collection *memoryCollection[T]) store(ctx context.Context, state T, revision bool) error {
+	raw, err := bson.MarshalWithRegistry(mongoDbRegistry, state)
+	if err != nil {
+		return err
+	}
+
+	var clone T
+	err = bson.UnmarshalWithRegistry(mongoDbRegistry, raw, &clone)
+	if err != nil {
+		return err
+	}
+
+	id := *collection.fields(&state).Id
+	collection.items[id] = clone
+	if revision {
+		collection.revisions = append(collection.revisions, Revision{
+			Id:         uuid.New(),
+			EntityId:   id,
+			Collection: collection.name,
+			Actor:      common.Actor(ctx),
+			At:         time.Now().UTC(),
+			State:      raw,
+		})
+	}
+
+	return nil
+}
+
+func (collection *memoryCollection[T]) softDelete(ctx context.Context, id uuid.UUID) (bool, error) {
+	collection.mutex.Lock()
+	defer collection.mutex.Unlock()
+
+	state, exists := collection.items[id]
+	fields := collection.fields(&state)
+	if !exists || *fields.DeletedAt != nil {
+		return false, nil
+	}
+
+	at := now()
+	*fields.DeletedAt = &at
+	*fields.DeletedBy = common.Actor(ctx)
+	*fields.ModifiedAt = at
+	return true, collection.store(ctx, state, true)
+}
+
+func (collection *memoryCollection[T]) restore(ctx context.Context, id uuid.UUID) (bool, error) {
+	collection.mutex.Lock()
+	defer collection.mutex.Unlock()
+
+	state, exists := collection.items[id]
+	fields := collection.fields(&state)
+	if !exists || *fields.DeletedAt == nil {
+		return false, nil
+	}
+
+	*fields.DeletedAt = nil
+	*fields.DeletedBy = ""
+	*fields.ModifiedAt = now()
+	return true, collection.store(ctx, state, true)
+}
+
+func (collection *memoryCollection[T]) deleted() ([]T, error) {
+	collection.mutex.RLock()
+	defer collection.mutex.RUnlock()
+
+	states := make([]T, 0)
+	for _, id := range collection.ids {
+		state := collection.items[id]
+		if *collection.fields(&state).DeletedAt == nil {
+			continue
+		}
+
+		clone, err := cloneState(state)
+		if err != nil {
+			return nil, err
+		}
+
+		states = append(states, clone)
+	}
+
+	sort.SliceStable(states, func(i, j int) bool {
+		return (*collection.fields(&states[i]).DeletedAt).After(**collection.fields(&states[j]).DeletedAt)
+	})
+
+	return states, nil
+}
+
+func (collection *memoryCollection[T]) purge(before time.Time, keep []uuid.UUID) (int64, error) {
+	collection.mutex.Lock()
+	defer collection.mutex.Unlock()
+
+	var count int64
+	ids := make([]uuid.UUID, 0, len(collection.ids))
+	for _, id := range collection.ids {
+		state := collection.items[id]
+		deletedAt := *collection.fields(&state).DeletedAt
+		if deletedAt != nil && deletedAt.Before(before) && !common.Contains(keep, id) {
+			delete(collection.items, id)
+			count++
+			continue
+		}
+
+		ids = append(ids, id)
+	}
+
+	collection.ids = ids
+	return count, nil
+}
+
+func (collection *memoryCollection[T]) history(id uuid.UUID) ([]Revision, error) {
+	collection.mutex.RLock()
+	revisions := make([]Revision, 0)
+	for _, revision := range collection.revisions {
+		if revision.EntityId == id {
+			revisions = append(revisions, revision)
+		}
+	}
+	collection.mutex.RUnlock()
+
+	return revisionChanges[T](revisions)
+}
+
+func (collection *memoryCollection[T]) revision(id, revisionId uuid.UUID) (T, error) {
+	collection.mutex.RLock()
+	defer collection.mutex.RUnlock()
+
+	var state T
+	for _, revision := range collection.revisions {
+		if revision.EntityId == id && revision.Id == revisionId {
+			err := bson.UnmarshalWithRegistry(mongoDbRegistry, revision.State, &state)
+			return state, err
+		}
+	}
+
+	return state, ErrRevisionNotFound
+}
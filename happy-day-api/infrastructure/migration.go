@@ -0,0 +1,106 @@
+package infrastructure
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const MigrationCollection = "migrations"
+
+type (
+	Migration struct {
+		Version int64
+		Name    string
+		Up      func(ctx context.Context, database *mongo.Database) error
+	}
+
+	MigrationRecord struct {
+		Version   int64     `bson:"version" json:"version"`
+		Name      string    `bson:"name" json:"name"`
+		AppliedAt time.Time `bson:"appliedAt" json:"appliedAt"`
+	}
+
+	MongoDbMigrator struct {
+		client     *mongo.Client
+		migrations []Migration
+	}
+)
+
+func ProvideMigrator(client *mongo.Client) *MongoDbMigrator {
+	return &MongoDbMigrator{
+		client:     client,
+		migrations: migrations,
+	}
+}
+
+func (migrator MongoDbMigrator) Run(ctx context.Context) ([]MigrationRecord, error) {
+	database := migrator.client.Database(Database)
+	collection := database.Collection(MigrationCollection)
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "version", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := collection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []MigrationRecord
+	err = cursor.All(ctx, &applied)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]MigrationRecord, 0)
+	for _, migration := range pendingMigrations(migrator.migrations, applied) {
+		err = migration.Up(ctx, database)
+		if err != nil {
+			return records, err
+		}
+
+		record := MigrationRecord{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			AppliedAt: time.Now().UTC(),
+		}
+
+		_, err = collection.InsertOne(ctx, record)
+		if err != nil {
+			return records, err
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func pendingMigrations(migrations []Migration, applied []MigrationRecord) []Migration {
+	versions := map[int64]bool{}
+	for _, record := range applied {
+		versions[record.Version] = true
+	}
+
+	pending := make([]Migration, 0)
+	for _, migration := range migrations {
+		if !versions[migration.Version] {
+			pending = append(pending, migration)
+		}
+	}
+
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].Version < pending[j].Version
+	})
+
+	return pending
+}
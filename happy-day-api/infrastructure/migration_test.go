@@ -0,0 +1,88 @@
+package infrastructure
+
+import (
+	"testing"
+
+	"happy_day/domain/customer"
+	"happy_day/domain/reservation"
+	"happy_day/domain/staff"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+func TestPendingMigrations(t *testing.T) {
+	migrations := []Migration{
+		{Version: 3, Name: "third"},
+		{Version: 1, Name: "first"},
+		{Version: 2, Name: "second"},
+		{Version: 4, Name: "fourth"},
+	}
+
+	pending := pendingMigrations(migrations, []MigrationRecord{{Version: 1}, {Version: 3}})
+	assert.Len(t, pending, 2)
+	assert.Equal(t, int64(2), pending[0].Version)
+	assert.Equal(t, int64(4), pending[1].Version)
+}
+
+func TestMigrationsVersionsAreUnique(t *testing.T) {
+	versions := map[int64]bool{}
+	for _, migration := range migrations {
+		assert.False(t, versions[migration.Version], migration.Name)
+		assert.NotNil(t, migration.Up, migration.Name)
+		versions[migration.Version] = true
+	}
+}
+
+func TestCrewByNameWhenNamesDifferOnlyByCaseAndAccents(t *testing.T) {
+	existing := staff.State{Id: uuid.New(), Name: "José"}
+	crew := newCrewByName([]staff.State{existing})
+
+	assigned := uuid.New()
+	name := func(value string) bson.RawValue {
+		_, data, _ := bson.MarshalValue(value)
+		return bson.RawValue{Type: bsontype.String, Value: data}
+	}
+
+	_, data, _ := bson.MarshalValueWithRegistry(mongoDbRegistry, assigned)
+	ids := crew.ids([]bson.RawValue{
+		name("jose "),
+		name("Ana"),
+		name(" ANA"),
+		name(""),
+		{Type: bsontype.Binary, Value: data},
+	})
+
+	assert.Len(t, crew.created, 1)
+	assert.Equal(t, "Ana", crew.created[0].Name)
+	assert.True(t, crew.created[0].Active)
+	assert.Equal(t, MigratedStaffPhone, crew.created[0].Phone)
+	assert.Equal(t, []uuid.UUID{existing.Id, crew.created[0].Id, assigned}, ids)
+}
+
+func TestReservationCustomerWhenSnapshotIsNested(t *testing.T) {
+	expected := customer.State{Id: uuid.New(), Name: "Ana", Phones: []customer.Phone{{Number: "123456789"}}}
+
+	legacy, err := bson.MarshalWithRegistry(mongoDbRegistry, bson.M{
+		"id":       uuid.New(),
+		"customer": bson.M{"state": expected},
+	})
+	assert.Nil(t, err)
+
+	var state reservation.State
+	err = bson.UnmarshalWithRegistry(mongoDbRegistry, legacy, &state)
+	assert.Nil(t, err)
+	assert.Equal(t, expected.Id, state.Customer.Id)
+	assert.Equal(t, expected.Name, state.Customer.Name)
+
+	flat, err := bson.MarshalWithRegistry(mongoDbRegistry, reservation.State{Customer: reservation.Customer{State: expected}})
+	assert.Nil(t, err)
+
+	state = reservation.State{}
+	err = bson.UnmarshalWithRegistry(mongoDbRegistry, flat, &state)
+	assert.Nil(t, err)
+	assert.Equal(t, expected.Id, state.Customer.Id)
+	assert.Equal(t, expected.Phones, state.Customer.Phones)
+}
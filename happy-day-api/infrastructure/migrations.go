@@ -0,0 +1,513 @@
+package infrastructure
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"happy_day/common"
+	"happy_day/domain/reservation"
+	"happy_day/domain/staff"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "create indexes",
+		Up:      createIndexes,
+	},
+	{
+		Version: 2,
+		Name:    "flatten reservation customer snapshot",
+		Up:      flattenReservationCustomer,
+	},
+	{
+		Version: 3,
+		Name:    "backfill reservation customer id",
+		Up:      backfillReservationCustomerId,
+	},
+	{
+		Version: 4,
+		Name:    "backfill reservation status and balance",
+		Up:      backfillReservationStatus,
+	},
+	{
+		Version: 5,
+		Name:    "backfill search tokens",
+		Up:      backfillSearchTokens,
+	},
+	{
+		Version: 6,
+		Name:    "replace crew names by staff",
+		Up:      replaceCrewNamesByStaff,
+	},
+	{
+		Version: 7,
+		Name:    "replace crew names in reservation revisions",
+		Up:      replaceRevisionCrewNames,
+	},
+}
+
+func createIndexes(ctx context.Context, database *mongo.Database) error {
+	indexes := map[string][]mongo.IndexModel{
+		CustomersCollection: {
+			{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "name", Value: 1}}},
+		},
+		ProductCollection: {
+			{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "name", Value: 1}}},
+			{Keys: bson.D{{Key: "products.id", Value: 1}}},
+		},
+		ReservationCollection: {
+			{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "delivery.at", Value: 1}}},
+			{Keys: bson.D{{Key: "pickUp.at", Value: 1}}},
+			{Keys: bson.D{{Key: "products.id", Value: 1}}},
+			{Keys: bson.D{{Key: "customerId", Value: 1}}},
+			{Keys: bson.D{{Key: "status", Value: 1}}},
+		},
+		RevisionCollection: {
+			{Keys: bson.D{{Key: "collection", Value: 1}, {Key: "entityId", Value: 1}, {Key: "at", Value: 1}}},
+		},
+	}
+
+	for collection, models := range indexes {
+		_, err := database.Collection(collection).Indexes().CreateMany(ctx, models)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func flattenReservationCustomer(ctx context.Context, database *mongo.Database) error {
+	_, err := database.Collection(ReservationCollection).UpdateMany(ctx,
+		bson.M{"customer.state": bson.M{"$exists": true}},
+		mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{"customer": "$customer.state"}}},
+		})
+	return err
+}
+
+func backfillReservationCustomerId(ctx context.Context, database *mongo.Database) error {
+	_, err := database.Collection(ReservationCollection).UpdateMany(ctx,
+		bson.M{"customerId": nil, "customer.id": bson.M{"$exists": true}},
+		mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{"customerId": "$customer.id"}}},
+		})
+	return err
+}
+
+func backfillReservationStatus(ctx context.Context, database *mongo.Database) error {
+	collection := database.Collection(ReservationCollection)
+	_, err := collection.UpdateMany(ctx,
+		bson.M{"status": nil},
+		mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{"status": legacyReservationStatus(now())}}},
+			{{Key: "$set", Value: bson.M{
+				"statusHistory": bson.A{
+					bson.M{"status": "$status", "at": "$createdAt"},
+				},
+			}}},
+		})
+	if err != nil {
+		return err
+	}
+
+	_, err = collection.UpdateMany(ctx,
+		bson.M{"balanceDue": nil},
+		mongo.Pipeline{
+			// A closed legacy reservation was settled outside the ledger
+			{{Key: "$set", Value: bson.M{
+				"amountPaid": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$status", reservation.Closed}}, "$finalPrice", 0}},
+				"balanceDue": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$status", reservation.Closed}}, 0, "$finalPrice"}},
+			}}},
+		})
+	return err
+}
+
+// legacyReservationStatus derives the status from the schedule, reservations before the lifecycle were already booked
+func legacyReservationStatus(at time.Time) bson.M {
+	return bson.M{"$switch": bson.M{
+		"branches": bson.A{
+			bson.M{
+				"case": bson.M{"$or": bson.A{
+					bson.M{"$eq": bson.A{bson.M{"$ifNull": bson.A{"$delivery.at", nil}}, nil}},
+					bson.M{"$eq": bson.A{bson.M{"$ifNull": bson.A{"$pickUp.at", nil}}, nil}},
+				}},
+				"then": reservation.Draft,
+			},
+			bson.M{"case": bson.M{"$lte": bson.A{"$pickUp.at", at}}, "then": reservation.Closed},
+			bson.M{"case": bson.M{"$lte": bson.A{"$delivery.at", at}}, "then": reservation.Delivered},
+		},
+		"default": reservation.Confirmed,
+	}}
+}
+
+func backfillSearchTokens(ctx context.Context, database *mongo.Database) error {
+	err := backfillCollectionSearchTokens(ctx, database, CustomersCollection, customerSearchTokens)
+	if err != nil {
+		return err
+	}
+
+	err = backfillCollectionSearchTokens(ctx, database, ProductCollection, productSearchTokens)
+	if err != nil {
+		return err
+	}
+
+	// The crew names are only replaced by staff ids on the next migration, so they are not decoded here
+	err = backfillCollectionSearchTokens(ctx, database, ReservationCollection, reservationSearchTokens, "delivery.by", "pickUp.by")
+	if err != nil {
+		return err
+	}
+
+	for _, collection := range []string{CustomersCollection, ProductCollection, ReservationCollection} {
+		_, err = database.Collection(collection).Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys: bson.D{{Key: searchField, Value: 1}},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func backfillCollectionSearchTokens[T any](
+	ctx context.Context,
+	database *mongo.Database,
+	name string,
+	tokens func(state T) []string,
+	ignore ...string) error {
+	projection := bson.M{}
+	for _, field := range ignore {
+		projection[field] = 0
+	}
+
+	collection := database.Collection(name)
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().SetProjection(projection))
+	if err != nil {
+		return err
+	}
+
+	defer cursor.Close(ctx)
+
+	var models []mongo.WriteModel
+	for cursor.Next(ctx) {
+		var state T
+		if err = cursor.Decode(&state); err != nil {
+			return err
+		}
+
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": cursor.Current.Lookup("_id")}).
+			SetUpdate(bson.M{"$set": bson.M{searchField: tokens(state)}}))
+	}
+
+	if err = cursor.Err(); err != nil || len(models) == 0 {
+		return err
+	}
+
+	_, err = collection.BulkWrite(ctx, models)
+	return err
+}
+
+// MigratedStaffPhone keeps staff created from crew names valid until their phone is known
+const MigratedStaffPhone = "-"
+
+type crewNames struct {
+	Id       primitive.ObjectID `bson:"_id"`
+	Delivery struct {
+		By []bson.RawValue `bson:"by"`
+	} `bson:"delivery"`
+	PickUp struct {
+		By []bson.RawValue `bson:"by"`
+	} `bson:"pickUp"`
+}
+
+func replaceCrewNamesByStaff(ctx context.Context, database *mongo.Database) error {
+	members := database.Collection(StaffCollection)
+	_, err := members.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "name", Value: 1}}},
+		{Keys: bson.D{{Key: searchField, Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	reservations := database.Collection(ReservationCollection)
+	_, err = reservations.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "delivery.by", Value: 1}, {Key: "delivery.at", Value: 1}}},
+		{Keys: bson.D{{Key: "pickUp.by", Value: 1}, {Key: "pickUp.at", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	crew, err := loadCrewByName(ctx, members)
+	if err != nil {
+		return err
+	}
+
+	cursor, err := reservations.Find(ctx,
+		bson.M{"$or": bson.A{
+			bson.M{"delivery.by": bson.M{"$type": "string"}},
+			bson.M{"pickUp.by": bson.M{"$type": "string"}},
+		}},
+		options.Find().SetProjection(bson.M{"_id": 1, "delivery.by": 1, "pickUp.by": 1}))
+	if err != nil {
+		return err
+	}
+
+	defer cursor.Close(ctx)
+
+	var models []mongo.WriteModel
+	for cursor.Next(ctx) {
+		var names crewNames
+		if err = cursor.Decode(&names); err != nil {
+			return err
+		}
+
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": names.Id}).
+			SetUpdate(bson.M{"$set": bson.M{
+				"delivery.by": crew.ids(names.Delivery.By),
+				"pickUp.by":   crew.ids(names.PickUp.By),
+			}}))
+	}
+
+	if err = cursor.Err(); err != nil {
+		return err
+	}
+
+	if err = insertCreatedCrew(ctx, members, crew); err != nil {
+		return err
+	}
+
+	if len(models) == 0 {
+		return nil
+	}
+
+	_, err = reservations.BulkWrite(ctx, models)
+	return err
+}
+
+// replaceRevisionCrewNames converts what replaceCrewNamesByStaff left behind, the history and the revert decode old revisions
+func replaceRevisionCrewNames(ctx context.Context, database *mongo.Database) error {
+	members := database.Collection(StaffCollection)
+	_, err := members.UpdateMany(ctx,
+		bson.M{"phone": bson.M{"$in": bson.A{nil, ""}}},
+		bson.M{"$set": bson.M{"phone": MigratedStaffPhone}})
+	if err != nil {
+		return err
+	}
+
+	crew, err := loadCrewByName(ctx, members)
+	if err != nil {
+		return err
+	}
+
+	revisions := database.Collection(RevisionCollection)
+	cursor, err := revisions.Find(ctx, bson.M{
+		"collection": ReservationCollection,
+		"$or": bson.A{
+			bson.M{"state.delivery.by": bson.M{"$type": "string"}},
+			bson.M{"state.pickUp.by": bson.M{"$type": "string"}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	defer cursor.Close(ctx)
+
+	var models []mongo.WriteModel
+	for cursor.Next(ctx) {
+		var revision struct {
+			Id    primitive.ObjectID `bson:"_id"`
+			State bson.Raw           `bson:"state"`
+		}
+		if err = cursor.Decode(&revision); err != nil {
+			return err
+		}
+
+		state, changed, err := replaceCrewNames(revision.State, crew)
+		if err != nil {
+			return err
+		}
+
+		if changed {
+			models = append(models, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"_id": revision.Id}).
+				SetUpdate(bson.M{"$set": bson.M{"state": state}}))
+		}
+	}
+
+	if err = cursor.Err(); err != nil {
+		return err
+	}
+
+	if err = insertCreatedCrew(ctx, members, crew); err != nil {
+		return err
+	}
+
+	if len(models) == 0 {
+		return nil
+	}
+
+	_, err = revisions.BulkWrite(ctx, models)
+	return err
+}
+
+func loadCrewByName(ctx context.Context, members *mongo.Collection) (*crewByName, error) {
+	existing := make([]staff.State, 0)
+	cursor, err := members.Find(ctx, bson.M{"deletedAt": nil})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = cursor.All(ctx, &existing); err != nil {
+		return nil, err
+	}
+
+	return newCrewByName(existing), nil
+}
+
+func insertCreatedCrew(ctx context.Context, members *mongo.Collection, crew *crewByName) error {
+	if len(crew.created) == 0 {
+		return nil
+	}
+
+	documents := common.Map(crew.created, func(state staff.State) interface{} {
+		return searchDocument[staff.State]{State: state, Search: staffSearchTokens(state)}
+	})
+
+	_, err := members.InsertMany(ctx, documents)
+	return err
+}
+
+// crewByName matches names ignoring case, accents and spacing, so "Ana " and "ana" become the same staff
+type crewByName struct {
+	byName  map[string]uuid.UUID
+	created []staff.State
+}
+
+func newCrewByName(existing []staff.State) *crewByName {
+	crew := &crewByName{byName: map[string]uuid.UUID{}}
+	for _, state := range existing {
+		crew.byName[crewKey(state.Name)] = state.Id
+	}
+
+	return crew
+}
+
+func crewKey(name string) string {
+	return strings.Join(searchTokens(name), " ")
+}
+
+func (crew *crewByName) ids(values []bson.RawValue) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(values))
+	for _, value := range values {
+		if value.Type == bsontype.Binary {
+			_, data := value.Binary()
+			if id, err := uuid.FromBytes(data); err == nil {
+				ids = append(ids, id)
+			}
+
+			continue
+		}
+
+		name, ok := value.StringValueOK()
+		key := crewKey(name)
+		if !ok || len(key) == 0 {
+			continue
+		}
+
+		id, exists := crew.byName[key]
+		if !exists {
+			at := now()
+			id = uuid.New()
+			crew.byName[key] = id
+			crew.created = append(crew.created, staff.State{
+				Id:         id,
+				Name:       strings.TrimSpace(name),
+				Phone:      MigratedStaffPhone,
+				Role:       staff.Driver,
+				Active:     true,
+				CreatedAt:  at,
+				ModifiedAt: at,
+			})
+		}
+
+		if !common.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+func replaceCrewNames(raw bson.Raw, crew *crewByName) (bson.Raw, bool, error) {
+	var document bson.D
+	if err := bson.Unmarshal(raw, &document); err != nil {
+		return nil, false, err
+	}
+
+	changed := false
+	for _, element := range document {
+		if element.Key != "delivery" && element.Key != "pickUp" {
+			continue
+		}
+
+		names, ok := crewNameValues(raw.Lookup(element.Key, "by"))
+		nested, isDocument := element.Value.(bson.D)
+		if !ok || !isDocument {
+			continue
+		}
+
+		for i := range nested {
+			if nested[i].Key == "by" {
+				nested[i].Value = crew.ids(names)
+				changed = true
+			}
+		}
+	}
+
+	if !changed {
+		return raw, false, nil
+	}
+
+	converted, err := bson.MarshalWithRegistry(mongoDbRegistry, document)
+	return converted, true, err
+}
+
+// crewNameValues returns the crew values when at least one of them is still a name
+func crewNameValues(value bson.RawValue) ([]bson.RawValue, bool) {
+	array, ok := value.ArrayOK()
+	if !ok {
+		return nil, false
+	}
+
+	values, err := array.Values()
+	if err != nil {
+		return nil, false
+	}
+
+	for _, item := range values {
+		if item.Type == bsontype.String {
+			return values, true
+		}
+	}
+
+	return nil, false
+}
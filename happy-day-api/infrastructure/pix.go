@@ -0,0 +1,94 @@
+package infrastructure
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"happy_day/common"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	pixGui              = "br.gov.bcb.pix"
+	pixMaxMerchantName  = 25
+	pixMaxMerchantCity  = 15
+	pixMaxTransactionId = 25
+)
+
+type PixOptions struct {
+	Key          string
+	MerchantName string
+	MerchantCity string
+}
+
+func ProvidePixOptions() PixOptions {
+	return PixOptions{
+		Key:          viper.GetString("pix.key"),
+		MerchantName: viper.GetString("pix.merchant_name"),
+		MerchantCity: viper.GetString("pix.merchant_city"),
+	}
+}
+
+func (options PixOptions) IsValid() bool {
+	return len(options.Key) > 0 && len(options.MerchantName) > 0 && len(options.MerchantCity) > 0
+}
+
+func BuildPixPayload(options PixOptions, amount float64, transactionId string) string {
+	if len(transactionId) == 0 {
+		transactionId = "***"
+	}
+
+	var payload strings.Builder
+	payload.WriteString(emvField("00", "01"))
+	payload.WriteString(emvField("26", emvField("00", pixGui)+emvField("01", options.Key)))
+	payload.WriteString(emvField("52", "0000"))
+	payload.WriteString(emvField("53", "986"))
+	if amount > 0 {
+		payload.WriteString(emvField("54", strconv.FormatFloat(amount, 'f', 2, 64)))
+	}
+
+	payload.WriteString(emvField("58", "BR"))
+	payload.WriteString(emvField("59", pixText(options.MerchantName, pixMaxMerchantName)))
+	payload.WriteString(emvField("60", pixText(options.MerchantCity, pixMaxMerchantCity)))
+	payload.WriteString(emvField("62", emvField("05", truncate(transactionId, pixMaxTransactionId))))
+	payload.WriteString("6304")
+
+	return payload.String() + fmt.Sprintf("%04X", crc16(payload.String()))
+}
+
+func emvField(id, value string) string {
+	return fmt.Sprintf("%s%02d%s", id, len(value), value)
+}
+
+func pixText(value string, max int) string {
+	return truncate(common.RemoveDiacritics(value), max)
+}
+
+// truncate keeps the first max characters, a multi-byte character is never split
+func truncate(value string, max int) string {
+	runes := []rune(value)
+	if len(runes) > max {
+		return string(runes[:max])
+	}
+
+	return value
+}
+
+// crc16 is the CRC-16/CCITT-FALSE checksum required by the EMV QR code specification
+func crc16(value string) uint16 {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(value); i++ {
+		crc ^= uint16(value[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+
+	return crc
+}
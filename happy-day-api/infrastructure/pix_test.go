@@ -0,0 +1,57 @@
+package infrastructure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildPixPayload(t *testing.T) {
+	type param struct {
+		name          string
+		amount        float64
+		transactionId string
+		options       PixOptions
+		expected      string
+	}
+
+	cases := []param{
+		{
+			name: "Without amount",
+			options: PixOptions{
+				Key:          "123e4567-e12b-12d1-a456-426655440000",
+				MerchantName: "Fulano de Tal",
+				MerchantCity: "BRASILIA",
+			},
+			expected: "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D",
+		},
+		{
+			name:          "With amount and transaction id",
+			amount:        150.5,
+			transactionId: "RSV01",
+			options: PixOptions{
+				Key:          "festas@happyday.com.br",
+				MerchantName: "Happy Day Festas e Eventos Ltda",
+				MerchantCity: "São José dos Campos",
+			},
+			expected: "00020126440014br.gov.bcb.pix0122festas@happyday.com.br5204000053039865406150.50" +
+				"5802BR5925Happy Day Festas e Evento6015Sao Jose dos Ca62090505RSV0163042740",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(k *testing.T) {
+			payload := BuildPixPayload(c.options, c.amount, c.transactionId)
+			assert.Equal(k, c.expected, payload)
+		})
+	}
+}
+
+func TestTruncateWhenValueHasMultiByteCharacters(t *testing.T) {
+	assert.Equal(t, "Sã", truncate("São Paulo", 2))
+	assert.Equal(t, "ação", truncate("ação", 4))
+}
+
+func TestCrc16(t *testing.T) {
+	assert.Equal(t, uint16(0x29B1), crc16("123456789"))
+}
@@ -0,0 +1,13 @@
+package infrastructure
+
+import "github.com/spf13/viper"
+
+type PricingOptions struct {
+	MaxDiscountPercentage float64
+}
+
+func ProvidePricingOptions() PricingOptions {
+	return PricingOptions{
+		MaxDiscountPercentage: viper.GetFloat64("pricing.max_discount_percentage"),
+	}
+}
@@ -3,7 +3,6 @@ package infrastructure
 import (
 	"context"
 	"errors"
-	"math"
 	"time"
 
 	"happy_day/common"
@@ -13,7 +12,6 @@ import (
 	"github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 const (
@@ -43,6 +41,7 @@ type (
 		Page   int64
 		Size   int64
 		SortBy ProductSortBy
+		Cursor string
 	}
 
 	ProductRepository interface {
@@ -53,8 +52,13 @@ type (
 
 		Exists(ctx context.Context, id uuid.UUID) (bool, error)
 		ExistAnyWithProduct(ctx context.Context, productId uuid.UUID) (bool, error)
+		GetDeleted(ctx context.Context) ([]product.State, error)
 		Save(ctx context.Context, state product.State) (product.State, error)
 		Delete(ctx context.Context, id uuid.UUID) error
+		GetHistory(ctx context.Context, id uuid.UUID) ([]Revision, error)
+		GetRevision(ctx context.Context, id, revisionId uuid.UUID) (product.State, error)
+		Restore(ctx context.Context, id uuid.UUID) error
+		Purge(ctx context.Context, before time.Time, keep []uuid.UUID) (int64, error)
 	}
 
 	MongoDbProductRepository struct {
@@ -96,6 +100,11 @@ func (m *MockProductRepository) ExistAnyWithProduct(ctx context.Context, product
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockProductRepository) GetDeleted(ctx context.Context) ([]product.State, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]product.State), args.Error(1)
+}
+
 func (m *MockProductRepository) Save(ctx context.Context, state product.State) (product.State, error) {
 	args := m.Called(ctx, state)
 	return args.Get(0).(product.State), args.Error(1)
@@ -106,15 +115,29 @@ func (m *MockProductRepository) Delete(ctx context.Context, id uuid.UUID) error
 	return args.Error(0)
 }
 
-func (repository MongoDbProductRepository) GetByProducts(ctx context.Context, productsId []uuid.UUID) ([]product.State, error) {
-	query := bson.M{"id": bson.M{"$in": productsId}}
-	client, err := repository.CreateClient(ctx)
-	if err != nil {
-		return nil, err
-	}
+func (m *MockProductRepository) GetHistory(ctx context.Context, id uuid.UUID) ([]Revision, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]Revision), args.Error(1)
+}
+
+func (m *MockProductRepository) GetRevision(ctx context.Context, id, revisionId uuid.UUID) (product.State, error) {
+	args := m.Called(ctx, id, revisionId)
+	return args.Get(0).(product.State), args.Error(1)
+}
+
+func (m *MockProductRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) Purge(ctx context.Context, before time.Time, keep []uuid.UUID) (int64, error) {
+	args := m.Called(ctx, before, keep)
+	return args.Get(0).(int64), args.Error(1)
+}
 
-	defer client.Disconnect(ctx)
-	cursor, err := client.Database(Database).
+func (repository MongoDbProductRepository) GetByProducts(ctx context.Context, productsId []uuid.UUID) ([]product.State, error) {
+	query := bson.M{"id": bson.M{"$in": productsId}, "deletedAt": nil}
+	cursor, err := repository.client.Database(Database).
 		Collection(ProductCollection).
 		Find(ctx, query)
 
@@ -136,14 +159,8 @@ func (repository MongoDbProductRepository) GetByProducts(ctx context.Context, pr
 }
 
 func (repository MongoDbProductRepository) GetComposed(ctx context.Context, productsId []uuid.UUID) ([]product.State, error) {
-	query := bson.M{"products.id": bson.M{"$in": productsId}}
-	client, err := repository.CreateClient(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	defer client.Disconnect(ctx)
-	cursor, err := client.Database(Database).
+	query := bson.M{"products.id": bson.M{"$in": productsId}, "deletedAt": nil}
+	cursor, err := repository.client.Database(Database).
 		Collection(ProductCollection).
 		Find(ctx, query)
 
@@ -186,18 +203,12 @@ func (repository MongoDbProductRepository) GetComposed(ctx context.Context, prod
 }
 
 func (repository MongoDbProductRepository) GetById(ctx context.Context, id uuid.UUID) (product.State, error) {
-	query := bson.M{"id": id}
-	client, err := repository.CreateClient(ctx)
-	if err != nil {
-		return product.State{}, err
-	}
-
-	defer client.Disconnect(ctx)
-	decode := client.Database(Database).
+	query := bson.M{"id": id, "deletedAt": nil}
+	decode := repository.client.Database(Database).
 		Collection(ProductCollection).
 		FindOne(ctx, query)
 
-	err = decode.Err()
+	err := decode.Err()
 	if err == mongo.ErrNoDocuments {
 		return product.State{}, ErrProductNotFound
 	}
@@ -211,14 +222,8 @@ func (repository MongoDbProductRepository) GetById(ctx context.Context, id uuid.
 }
 
 func (repository MongoDbProductRepository) Exists(ctx context.Context, id uuid.UUID) (bool, error) {
-	query := bson.M{"id": id}
-	client, err := repository.CreateClient(ctx)
-	if err != nil {
-		return false, err
-	}
-
-	defer client.Disconnect(ctx)
-	count, err := client.Database(Database).
+	query := bson.M{"id": id, "deletedAt": nil}
+	count, err := repository.client.Database(Database).
 		Collection(ProductCollection).
 		CountDocuments(ctx, query)
 
@@ -230,14 +235,8 @@ func (repository MongoDbProductRepository) Exists(ctx context.Context, id uuid.U
 }
 
 func (repository MongoDbProductRepository) ExistAnyWithProduct(ctx context.Context, productId uuid.UUID) (bool, error) {
-	query := bson.M{"products.id": productId}
-	client, err := repository.CreateClient(ctx)
-	if err != nil {
-		return false, err
-	}
-
-	defer client.Disconnect(ctx)
-	count, err := client.Database(Database).
+	query := bson.M{"products.id": productId, "deletedAt": nil}
+	count, err := repository.client.Database(Database).
 		Collection(ProductCollection).
 		CountDocuments(ctx, query)
 
@@ -249,102 +248,113 @@ func (repository MongoDbProductRepository) ExistAnyWithProduct(ctx context.Conte
 }
 
 func (repository MongoDbProductRepository) Save(ctx context.Context, state product.State) (product.State, error) {
-	client, err := repository.CreateClient(ctx)
-	if err != nil {
-		return product.State{}, err
-	}
-
-	defer client.Disconnect(ctx)
-	collection := client.Database(Database).
+	collection := repository.client.Database(Database).
 		Collection(ProductCollection)
 
+	// Only softDelete and restore change the deleted fields
+	state.DeletedAt = nil
+	state.DeletedBy = ""
 	if state.Id == uuid.Nil {
 		state.Id = uuid.New()
-		state.CreatedAt = time.Now().UTC()
-		state.ModifiedAt = time.Now().UTC()
-		_, err = collection.InsertOne(ctx, state)
-		return state, err
+		state.CreatedAt = now()
+		state.ModifiedAt = state.CreatedAt
+		_, err := collection.InsertOne(ctx, searchDocument[product.State]{State: state, Search: productSearchTokens(state)})
+		if err != nil {
+			return state, err
+		}
+
+		return state, repository.saveRevision(ctx, ProductCollection, state.Id, state)
 	}
 
 	lastChange := state.ModifiedAt
-	state.ModifiedAt = time.Now().UTC()
+	state.ModifiedAt = now()
+
+	res, err := collection.ReplaceOne(ctx,
+		bson.M{"id": state.Id, "modifiedAt": lastChange, "deletedAt": nil},
+		searchDocument[product.State]{State: state, Search: productSearchTokens(state)})
+	if err != nil {
+		return state, err
+	}
 
-	res, err := collection.ReplaceOne(ctx, bson.M{"id": state.Id, "modifiedAt": lastChange}, state)
-	if res.ModifiedCount == 0 {
+	if res.MatchedCount == 0 {
 		return state, ErrProductConcurrencyIssue
 	}
 
-	return state, err
+	return state, repository.saveRevision(ctx, ProductCollection, state.Id, state)
+}
+
+func (repository MongoDbProductRepository) GetDeleted(ctx context.Context) ([]product.State, error) {
+	products := make([]product.State, 0)
+	err := repository.findDeleted(ctx, ProductCollection, &products)
+	return products, err
 }
 
 func (repository MongoDbProductRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := bson.M{"id": id}
-	client, err := repository.CreateClient(ctx)
-	if err != nil {
-		return err
+	deleted, err := repository.softDelete(ctx, ProductCollection, id)
+	if err == nil && !deleted {
+		return ErrProductNotFound
 	}
 
-	defer client.Disconnect(ctx)
-	_, err = client.Database(Database).
-		Collection(ProductCollection).
-		DeleteOne(ctx, query)
 	return err
 }
 
-func (repository MongoDbProductRepository) GetAll(ctx context.Context, filter ProductFilter) (Page[product.State], error) {
-	opt := options.Find().
-		SetSkip((filter.Page - 1) * filter.Size).
-		SetLimit(filter.Size)
-
-	if filter.SortBy == ProductIdAsc {
-		opt.SetSort(bson.D{{"id", 1}})
-	} else if filter.SortBy == ProductIdDesc {
-		opt.SetSort(bson.D{{"id", -1}})
-	} else if filter.SortBy == ProductNameAsc {
-		opt.SetSort(bson.D{{"name", 1}})
-	} else if filter.SortBy == ProductNameDesc {
-		opt.SetSort(bson.D{{"name", -1}})
-	} else if filter.SortBy == ProductPriceAsc {
-		opt.SetSort(bson.D{{"price", 1}})
-	} else if filter.SortBy == ProductPriceDesc {
-		opt.SetSort(bson.D{{"price", -1}})
-	}
+func (repository MongoDbProductRepository) GetHistory(ctx context.Context, id uuid.UUID) ([]Revision, error) {
+	return history[product.State](ctx, repository.MongoDbRepository, ProductCollection, id)
+}
 
-	query := bson.M{}
-	if len(filter.Text) > 0 {
-		query["$or"] = []interface{}{
-			bson.M{"id": bson.M{"$regex": filter.Text, "$options": "im"}},
-			bson.M{"name": bson.M{"$regex": filter.Text, "$options": "im"}},
-		}
-	}
+func (repository MongoDbProductRepository) GetRevision(ctx context.Context, id, revisionId uuid.UUID) (product.State, error) {
+	var state product.State
+	err := repository.revision(ctx, ProductCollection, id, revisionId, &state)
+	return state, err
+}
 
-	client, err := repository.CreateClient(ctx)
-	var page Page[product.State]
-	if err != nil {
-		return page, err
+func (repository MongoDbProductRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	restored, err := repository.restore(ctx, ProductCollection, id)
+	if err == nil && !restored {
+		return ErrProductNotFound
 	}
 
-	collection := client.
-		Database(Database).
-		Collection(ProductCollection)
+	return err
+}
 
-	totalElements, err := collection.CountDocuments(ctx, query)
-	if err != nil {
-		return page, err
-	}
+func (repository MongoDbProductRepository) Purge(ctx context.Context, before time.Time, keep []uuid.UUID) (int64, error) {
+	return repository.purge(ctx, ProductCollection, before, keep)
+}
 
-	page.TotalElements = totalElements
-	if totalElements > 0 {
-		tmp := float64(totalElements) / float64(filter.Size)
-		tmp = math.Ceil(tmp)
-		page.TotalPages = int64(tmp)
+func productSortKey(sortBy ProductSortBy) sortKey[product.State] {
+	switch sortBy {
+	case ProductIdDesc:
+		return idSortKey(productFields, true)
+	case ProductNameAsc, ProductNameDesc:
+		return sortKey[product.State]{
+			field:      "name",
+			descending: sortBy == ProductNameDesc,
+			value:      func(state *product.State) any { return state.Name },
+			fields:     productFields,
+		}
+	case ProductPriceAsc, ProductPriceDesc:
+		return sortKey[product.State]{
+			field:      "price",
+			descending: sortBy == ProductPriceDesc,
+			value:      func(state *product.State) any { return state.Price },
+			fields:     productFields,
+		}
 	}
 
-	cursor, err := collection.Find(ctx, query, opt)
-	if err != nil {
-		return page, err
+	return idSortKey(productFields, false)
+}
+
+func (repository MongoDbProductRepository) GetAll(ctx context.Context, filter ProductFilter) (Page[product.State], error) {
+	query := bson.M{"deletedAt": nil}
+	terms := searchTokens(filter.Text)
+	if len(terms) > 0 {
+		query[searchField] = searchQuery(terms)
 	}
 
-	err = cursor.All(ctx, &page.Items)
-	return page, err
+	collection := repository.client.
+		Database(Database).
+		Collection(ProductCollection)
+
+	ranked := len(terms) > 0 && len(filter.SortBy) == 0 && len(filter.Cursor) == 0
+	return findPage(ctx, collection, query, productSortKey(filter.SortBy), terms, ranked, filter.Page, filter.Size, filter.Cursor)
 }
@@ -1,8 +1,13 @@
 package infrastructure
 
 import (
+	"context"
 	"fmt"
+	"log"
 	"reflect"
+	"time"
+
+	"happy_day/domain/reservation"
 
 	"github.com/google/uuid"
 	"github.com/google/wire"
@@ -11,24 +16,39 @@ import (
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/bsonrw"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 var (
-	uuidType    = reflect.TypeOf(uuid.UUID{})
-	uuidSubtype = byte(0x04)
+	uuidType                = reflect.TypeOf(uuid.UUID{})
+	uuidSubtype             = byte(0x04)
+	reservationCustomerType = reflect.TypeOf(reservation.Customer{})
+
+	mongoDbRegistry = bson.NewRegistryBuilder().
+			RegisterTypeEncoder(uuidType, bsoncodec.ValueEncoderFunc(uuidEncodeValue)).
+			RegisterTypeDecoder(uuidType, bsoncodec.ValueDecoderFunc(uuidDecodeValue)).
+			RegisterTypeDecoder(reservationCustomerType, bsoncodec.ValueDecoderFunc(reservationCustomerDecodeValue)).
+			Build()
+
+	mongoDbClient *mongo.Client
 
 	ProviderSet = wire.NewSet(
 		ProvideMongoDbOptions,
-
+		ProvideMongoDbClient,
+		ProvideMongoDbClientFactory,
+		ProvideMigrator,
+		ProvidePixOptions,
+		ProvideDocumentOptions,
+		ProvideTrashOptions,
+		ProvidePricingOptions,
+		ProvideReservationOptions,
+
+		ProvideStorageOptions,
+		ProvideCustomerRepository,
 		ProvideProductRepository,
-		wire.Bind(new(ProductRepository), new(*MongoDbProductRepository)),
-
-		ProviderCustomerRepository,
-		wire.Bind(new(CustomerRepository), new(*MongoDbCustomerRepository)),
-
 		ProvideReservationRepository,
-		wire.Bind(new(ReservationRepository), new(*MongoDbReservationRepository)),
+		ProvideStaffRepository,
 	)
 )
 
@@ -86,38 +106,147 @@ func uuidDecodeValue(_ bsoncodec.DecodeContext, reader bsonrw.ValueReader, value
 	return nil
 }
 
+// reservationCustomerDecodeValue also reads the snapshot nested under "state", the layout used before the
+// migration that flattens it
+func reservationCustomerDecodeValue(dc bsoncodec.DecodeContext, reader bsonrw.ValueReader, value reflect.Value) error {
+	if !value.CanSet() || value.Type() != reservationCustomerType {
+		return bsoncodec.ValueDecoderError{
+			Name:     "reservationCustomerDecodeValue",
+			Types:    []reflect.Type{reservationCustomerType},
+			Received: value,
+		}
+	}
+
+	switch valueType := reader.Type(); valueType {
+	case bsontype.Null:
+		value.Set(reflect.Zero(reservationCustomerType))
+		return reader.ReadNull()
+	case bsontype.Undefined:
+		value.Set(reflect.Zero(reservationCustomerType))
+		return reader.ReadUndefined()
+	case bsontype.EmbeddedDocument:
+	default:
+		return fmt.Errorf("cannot decode %v into a reservation customer", valueType)
+	}
+
+	raw, err := bsonrw.Copier{}.CopyDocumentToBytes(reader)
+	if err != nil {
+		return err
+	}
+
+	if nested, ok := bson.Raw(raw).Lookup("state").DocumentOK(); ok {
+		raw = nested
+	}
+
+	state := value.FieldByName("State")
+	decoder, err := dc.LookupDecoder(state.Type())
+	if err != nil {
+		return err
+	}
+
+	return decoder.DecodeValue(dc, bsonrw.NewBSONDocumentReader(raw), state)
+}
+
 func ProvideMongoDbOptions() *options.ClientOptions {
 	connectionString := viper.GetString("connecting_strings.mongo")
 
-	return options.Client().
+	opt := options.Client().
 		ApplyURI(connectionString).
-		SetRegistry(bson.NewRegistryBuilder().
-			RegisterTypeEncoder(uuidType, bsoncodec.ValueEncoderFunc(uuidEncodeValue)).
-			RegisterTypeDecoder(uuidType, bsoncodec.ValueDecoderFunc(uuidDecodeValue)).
-			Build())
+		SetRegistry(mongoDbRegistry)
 
+	if size := viper.GetUint64("mongo.max_pool_size"); size > 0 {
+		opt.SetMaxPoolSize(size)
+	}
+
+	if size := viper.GetUint64("mongo.min_pool_size"); size > 0 {
+		opt.SetMinPoolSize(size)
+	}
+
+	if timeout := viper.GetDuration("mongo.connect_timeout"); timeout > 0 {
+		opt.SetConnectTimeout(timeout)
+	}
+
+	if timeout := viper.GetDuration("mongo.server_selection_timeout"); timeout > 0 {
+		opt.SetServerSelectionTimeout(timeout)
+	}
+
+	if timeout := viper.GetDuration("mongo.timeout"); timeout > 0 {
+		opt.SetTimeout(timeout)
+	}
+
+	if idle := viper.GetDuration("mongo.max_conn_idle_time"); idle > 0 {
+		opt.SetMaxConnIdleTime(idle)
+	}
+
+	return opt
 }
 
-func ProvideProductRepository(opt *options.ClientOptions) *MongoDbProductRepository {
+// ProvideMongoDbClient connects the pooled client, the cleanup disconnects it
+func ProvideMongoDbClient(opt *options.ClientOptions) (*mongo.Client, func(), error) {
+	client, err := mongo.Connect(context.Background(), opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Connect does not reach the server, a wrong address must fail at startup
+	if err = client.Ping(context.Background(), nil); err != nil {
+		_ = client.Disconnect(context.Background())
+		return nil, nil, err
+	}
+
+	cleanup := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := client.Disconnect(ctx); err != nil {
+			log.Println(err)
+		}
+	}
+
+	return client, cleanup, nil
+}
+
+// MongoDbClientFactory returns the client shared at startup, the embedded storages never call it
+type MongoDbClientFactory func() *mongo.Client
+
+func ProvideMongoDbClientFactory() MongoDbClientFactory {
+	return func() *mongo.Client {
+		return mongoDbClient
+	}
+}
+
+// ShareMongoDbClient hands the client built at startup to the repositories created for each request
+func ShareMongoDbClient(client *mongo.Client) {
+	mongoDbClient = client
+}
+
+func NewMongoDbProductRepository(client *mongo.Client) *MongoDbProductRepository {
 	return &MongoDbProductRepository{
 		MongoDbRepository{
-			options: opt,
+			client: client,
 		},
 	}
 }
 
-func ProviderCustomerRepository(opt *options.ClientOptions) *MongoDbCustomerRepository {
+func NewMongoDbCustomerRepository(client *mongo.Client) *MongoDbCustomerRepository {
 	return &MongoDbCustomerRepository{
 		MongoDbRepository{
-			options: opt,
+			client: client,
 		},
 	}
 }
 
-func ProvideReservationRepository(opt *options.ClientOptions) *MongoDbReservationRepository {
+func NewMongoDbReservationRepository(client *mongo.Client) *MongoDbReservationRepository {
 	return &MongoDbReservationRepository{
 		MongoDbRepository{
-			options: opt,
+			client: client,
+		},
+	}
+}
+
+func NewMongoDbStaffRepository(client *mongo.Client) *MongoDbStaffRepository {
+	return &MongoDbStaffRepository{
+		MongoDbRepository{
+			client: client,
 		},
 	}
 }
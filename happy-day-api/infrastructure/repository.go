@@ -2,15 +2,101 @@ package infrastructure
 
 import (
 	"context"
+	"time"
 
+	"happy_day/common"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type MongoDbRepository struct {
-	options *options.ClientOptions
+	client *mongo.Client
+}
+
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
+
+func (repository MongoDbRepository) softDelete(ctx context.Context, collection string, id uuid.UUID) (bool, error) {
+	at := now()
+	query := bson.M{"id": id, "deletedAt": nil}
+	update := bson.M{
+		"$set": bson.M{
+			"deletedAt":  at,
+			"deletedBy":  common.Actor(ctx),
+			"modifiedAt": at,
+		},
+	}
+
+	return repository.updateWithRevision(ctx, collection, id, query, update)
+}
+
+func (repository MongoDbRepository) restore(ctx context.Context, collection string, id uuid.UUID) (bool, error) {
+	query := bson.M{"id": id, "deletedAt": bson.M{"$ne": nil}}
+	update := bson.M{
+		"$set":   bson.M{"modifiedAt": now()},
+		"$unset": bson.M{"deletedAt": "", "deletedBy": ""},
+	}
+
+	return repository.updateWithRevision(ctx, collection, id, query, update)
 }
 
-func (repository MongoDbRepository) CreateClient(ctx context.Context) (*mongo.Client, error) {
-	return mongo.Connect(ctx, repository.options)
+// updateWithRevision records the updated document as a revision, without the storage only fields
+func (repository MongoDbRepository) updateWithRevision(
+	ctx context.Context,
+	collection string,
+	id uuid.UUID,
+	query, update bson.M) (bool, error) {
+	var document bson.D
+	err := repository.client.Database(Database).
+		Collection(collection).
+		FindOneAndUpdate(ctx, query, update, options.FindOneAndUpdate().SetReturnDocument(options.After)).
+		Decode(&document)
+	if err == mongo.ErrNoDocuments {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	state := make(bson.D, 0, len(document))
+	for _, element := range document {
+		if element.Key != "_id" && element.Key != searchField {
+			state = append(state, element)
+		}
+	}
+
+	return true, repository.saveRevision(ctx, collection, id, state)
+}
+
+func (repository MongoDbRepository) findDeleted(ctx context.Context, collection string, results any) error {
+	query := bson.M{"deletedAt": bson.M{"$ne": nil}}
+	opt := options.Find().SetSort(bson.D{{Key: "deletedAt", Value: -1}})
+	cursor, err := repository.client.Database(Database).
+		Collection(collection).
+		Find(ctx, query, opt)
+	if err != nil {
+		return err
+	}
+
+	return cursor.All(ctx, results)
+}
+
+func (repository MongoDbRepository) purge(ctx context.Context, collection string, before time.Time, keep []uuid.UUID) (int64, error) {
+	query := bson.M{"deletedAt": bson.M{"$lt": before}}
+	if len(keep) > 0 {
+		query["id"] = bson.M{"$nin": keep}
+	}
+	res, err := repository.client.Database(Database).
+		Collection(collection).
+		DeleteMany(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.DeletedCount, nil
 }
@@ -3,8 +3,10 @@ package infrastructure
 import (
 	"context"
 	"errors"
+	"happy_day/common"
+	"happy_day/domain/customer"
 	"happy_day/domain/reservation"
-	"math"
+	"regexp"
 	"time"
 
 	"github.com/google/uuid"
@@ -24,6 +26,9 @@ const (
 )
 
 var (
+	_ ReservationRepository = (*MockReservationRepository)(nil)
+	_ ReservationRepository = (*MongoDbReservationRepository)(nil)
+
 	ErrReservationConcurrencyIssue = errors.New("reservation concurrency issue")
 	ErrReservationNotFound         = errors.New("reservation not found")
 )
@@ -31,17 +36,50 @@ var (
 type (
 	ReservationOrderBy string
 	ReservationFilter  struct {
-		Text   string
-		Page   int64
-		Size   int64
-		SortBy ReservationOrderBy
+		Text          string
+		Status        []reservation.Status
+		CustomerId    uuid.UUID
+		ProductId     uuid.UUID
+		DeliveryFrom  time.Time
+		DeliveryTo    time.Time
+		PickUpFrom    time.Time
+		PickUpTo      time.Time
+		City          string
+		Neighborhood  string
+		PaymentMethod reservation.PaymentMethod
+		By            uuid.UUID
+		HasBalanceDue *bool
+		Page          int64
+		Size          int64
+		SortBy        ReservationOrderBy
+		Cursor        string
 	}
 
 	ReservationRepository interface {
 		GetAll(ctx context.Context, filter ReservationFilter) (Page[reservation.State], error)
 		GetById(ctx context.Context, id uuid.UUID) (reservation.State, error)
+		GetByPeriod(ctx context.Context, from, to time.Time) ([]reservation.State, error)
+		GetByCustomer(ctx context.Context, customerId uuid.UUID) ([]reservation.State, error)
+		GetByStaff(ctx context.Context, staffId uuid.UUID, from, to time.Time) ([]reservation.State, error)
+		ExistsOpenWithProduct(ctx context.Context, productId uuid.UUID) (bool, error)
+		ExistsOpenWithCustomer(ctx context.Context, customerId uuid.UUID) (bool, error)
+		ExistsOpenWithStaff(ctx context.Context, staffId uuid.UUID) (bool, error)
+		GetDeleted(ctx context.Context) ([]reservation.State, error)
 		Save(ctx context.Context, state reservation.State) (reservation.State, error)
+		UpdateCustomer(ctx context.Context, state customer.State) error
 		Delete(ctx context.Context, id uuid.UUID) error
+		GetHistory(ctx context.Context, id uuid.UUID) ([]Revision, error)
+		GetRevision(ctx context.Context, id, revisionId uuid.UUID) (reservation.State, error)
+		Restore(ctx context.Context, id uuid.UUID) error
+		Purge(ctx context.Context, before time.Time) (int64, error)
+		GetReferences(ctx context.Context) (ReservationReferences, error)
+	}
+
+	// ReservationReferences are the customers, products and staff used by any reservation, even a deleted one
+	ReservationReferences struct {
+		Customers []uuid.UUID
+		Products  []uuid.UUID
+		Staff     []uuid.UUID
 	}
 
 	MockReservationRepository struct {
@@ -53,83 +91,125 @@ type (
 	}
 )
 
+// IsValid accepts the empty order, the repositories fall back to their default one
+func (sortBy ReservationOrderBy) IsValid() bool {
+	switch sortBy {
+	case "", DeliveryAsc, DeliveryDesc, PickupAsc, PickupDesc:
+		return true
+	default:
+		return false
+	}
+}
+
+func reservationSortKey(sortBy ReservationOrderBy) sortKey[reservation.State] {
+	switch sortBy {
+	case DeliveryAsc, DeliveryDesc:
+		return sortKey[reservation.State]{
+			field:      "delivery.at",
+			descending: sortBy == DeliveryDesc,
+			value:      func(state *reservation.State) any { return state.Delivery.At },
+			fields:     reservationFields,
+		}
+	case PickupAsc, PickupDesc:
+		return sortKey[reservation.State]{
+			field:      "pickUp.at",
+			descending: sortBy == PickupDesc,
+			value:      func(state *reservation.State) any { return state.PickUp.At },
+			fields:     reservationFields,
+		}
+	}
+
+	return idSortKey(reservationFields, false)
+}
+
 func (repository MongoDbReservationRepository) GetAll(ctx context.Context, filter ReservationFilter) (Page[reservation.State], error) {
-	opt := options.Find().
-		SetSkip((filter.Page - 1) * filter.Size).
-		SetLimit(filter.Size)
-
-	if filter.SortBy == DeliveryAsc {
-		opt.SetSort(bson.D{{"delivery.at", 1}})
-	} else if filter.SortBy == DeliveryDesc {
-		opt.SetSort(bson.D{{"delivery.at", -1}})
-	} else if filter.SortBy == PickupAsc {
-		opt.SetSort(bson.D{{"pickUp.at", 1}})
-	} else if filter.SortBy == PickupDesc {
-		opt.SetSort(bson.D{{"pickUp.at", -1}})
-	}
-
-	query := bson.M{}
-	if len(filter.Text) > 0 {
+	collection := repository.client.
+		Database(Database).
+		Collection(ReservationCollection)
+
+	terms := searchTokens(filter.Text)
+	ranked := len(terms) > 0 && len(filter.SortBy) == 0 && len(filter.Cursor) == 0
+	return findPage(ctx, collection, reservationQuery(filter), reservationSortKey(filter.SortBy), terms, ranked, filter.Page, filter.Size, filter.Cursor)
+}
+
+func reservationQuery(filter ReservationFilter) bson.M {
+	query := bson.M{"deletedAt": nil}
+	if terms := searchTokens(filter.Text); len(terms) > 0 {
+		query[searchField] = searchQuery(terms)
+	}
+
+	if filter.By != uuid.Nil {
 		query["$or"] = []interface{}{
-			bson.M{"id": bson.M{"$regex": filter.Text, "$options": "im"}},
-			bson.M{"comment": bson.M{"$regex": filter.Text, "$options": "im"}},
-			bson.M{"address.street": bson.M{"$regex": filter.Text, "$options": "im"}},
-			bson.M{"address.number": bson.M{"$regex": filter.Text, "$options": "im"}},
-			bson.M{"address.neighborhood": bson.M{"$regex": filter.Text, "$options": "im"}},
-			bson.M{"address.complement": bson.M{"$regex": filter.Text, "$options": "im"}},
-			bson.M{"address.postalCode": bson.M{"$regex": filter.Text, "$options": "im"}},
-			bson.M{"address.city": bson.M{"$regex": filter.Text, "$options": "im"}},
-			bson.M{"customer.name": bson.M{"$regex": filter.Text, "$options": "im"}},
-			bson.M{"customer.phones": bson.M{"$elemMatch": bson.M{"number": bson.M{"$regex": filter.Text, "$options": "im"}}}},
+			bson.M{"delivery.by": filter.By},
+			bson.M{"pickUp.by": filter.By},
 		}
 	}
 
-	client, err := repository.CreateClient(ctx)
-	var page Page[reservation.State]
-	if err != nil {
-		return page, err
+	if len(filter.Status) > 0 {
+		query["status"] = bson.M{"$in": filter.Status}
 	}
 
-	collection := client.
-		Database(Database).
-		Collection(ReservationCollection)
+	if filter.CustomerId != uuid.Nil {
+		query["customerId"] = filter.CustomerId
+	}
 
-	totalElements, err := collection.CountDocuments(ctx, query)
-	if err != nil {
-		return page, err
+	if filter.ProductId != uuid.Nil {
+		query["products.id"] = filter.ProductId
 	}
 
-	page.TotalElements = totalElements
-	if totalElements > 0 {
-		tmp := float64(totalElements) / float64(filter.Size)
-		tmp = math.Ceil(tmp)
-		page.TotalPages = int64(tmp)
+	if period := periodQuery(filter.DeliveryFrom, filter.DeliveryTo); len(period) > 0 {
+		query["delivery.at"] = period
 	}
 
-	cursor, err := collection.Find(ctx, query, opt)
-	if err != nil {
-		return page, err
+	if period := periodQuery(filter.PickUpFrom, filter.PickUpTo); len(period) > 0 {
+		query["pickUp.at"] = period
+	}
+
+	if len(filter.City) > 0 {
+		query["address.city"] = bson.M{"$regex": "^" + regexp.QuoteMeta(filter.City) + "$", "$options": "i"}
+	}
+
+	if len(filter.Neighborhood) > 0 {
+		query["address.neighborhood"] = bson.M{"$regex": "^" + regexp.QuoteMeta(filter.Neighborhood) + "$", "$options": "i"}
 	}
 
-	err = cursor.All(ctx, &page.Items)
-	return page, err
+	if len(filter.PaymentMethod) > 0 {
+		query["paymentInstallments.method"] = filter.PaymentMethod
+	}
+
+	if filter.HasBalanceDue != nil {
+		if *filter.HasBalanceDue {
+			query["balanceDue"] = bson.M{"$gt": 0}
+		} else {
+			query["balanceDue"] = bson.M{"$lte": 0}
+		}
+	}
+
+	return query
 }
 
-func (repository MongoDbReservationRepository) GetById(ctx context.Context, id uuid.UUID) (reservation.State, error) {
-	query := bson.M{"id": id}
-	client, err := repository.CreateClient(ctx)
-	if err != nil {
-		return reservation.State{}, err
+func periodQuery(from, to time.Time) bson.M {
+	period := bson.M{}
+	if !from.IsZero() {
+		period["$gte"] = from
+	}
+
+	if !to.IsZero() {
+		period["$lt"] = to
 	}
 
-	defer client.Disconnect(ctx)
-	decode := client.Database(Database).
+	return period
+}
+
+func (repository MongoDbReservationRepository) GetById(ctx context.Context, id uuid.UUID) (reservation.State, error) {
+	query := bson.M{"id": id, "deletedAt": nil}
+	decode := repository.client.Database(Database).
 		Collection(ReservationCollection).
 		FindOne(ctx, query)
 
-	err = decode.Err()
+	err := decode.Err()
 	if err == mongo.ErrNoDocuments {
-		return reservation.State{}, ErrProductNotFound
+		return reservation.State{}, ErrReservationNotFound
 	}
 	if err != nil {
 		return reservation.State{}, err
@@ -140,49 +220,273 @@ func (repository MongoDbReservationRepository) GetById(ctx context.Context, id u
 	return state, nil
 }
 
-func (repository MongoDbReservationRepository) Save(ctx context.Context, state reservation.State) (reservation.State, error) {
-	client, err := repository.CreateClient(ctx)
+func (repository MongoDbReservationRepository) GetByPeriod(ctx context.Context, from, to time.Time) ([]reservation.State, error) {
+	query := bson.M{
+		"delivery.at": bson.M{"$lt": to},
+		"pickUp.at":   bson.M{"$gt": from},
+		"status":      bson.M{"$ne": reservation.Cancelled},
+		"deletedAt":   nil,
+	}
+
+	cursor, err := repository.client.Database(Database).
+		Collection(ReservationCollection).
+		Find(ctx, query)
+
 	if err != nil {
-		return reservation.State{}, err
+		return nil, err
+	}
+
+	reservations := make([]reservation.State, 0)
+	err = cursor.All(ctx, &reservations)
+	if err != nil {
+		return nil, err
+	}
+
+	return reservations, nil
+}
+
+func (repository MongoDbReservationRepository) GetByCustomer(ctx context.Context, customerId uuid.UUID) ([]reservation.State, error) {
+	query := bson.M{"customerId": customerId, "deletedAt": nil}
+	cursor, err := repository.client.Database(Database).
+		Collection(ReservationCollection).
+		Find(ctx, query)
+
+	if err != nil {
+		return nil, err
 	}
 
-	defer client.Disconnect(ctx)
-	collection := client.Database(Database).
+	reservations := make([]reservation.State, 0)
+	err = cursor.All(ctx, &reservations)
+	if err != nil {
+		return nil, err
+	}
+
+	return reservations, nil
+}
+
+// GetByStaff returns the reservations with a delivery or a pick up assigned to the staff inside the period
+func (repository MongoDbReservationRepository) GetByStaff(ctx context.Context, staffId uuid.UUID, from, to time.Time) ([]reservation.State, error) {
+	period := bson.M{"$gte": from, "$lt": to}
+	query := bson.M{
+		"$or": bson.A{
+			bson.M{"delivery.by": staffId, "delivery.at": period},
+			bson.M{"pickUp.by": staffId, "pickUp.at": period},
+		},
+		"status":    bson.M{"$ne": reservation.Cancelled},
+		"deletedAt": nil,
+	}
+
+	cursor, err := repository.client.Database(Database).
+		Collection(ReservationCollection).
+		Find(ctx, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	reservations := make([]reservation.State, 0)
+	err = cursor.All(ctx, &reservations)
+	if err != nil {
+		return nil, err
+	}
+
+	return reservations, nil
+}
+
+func (repository MongoDbReservationRepository) ExistsOpenWithProduct(ctx context.Context, productId uuid.UUID) (bool, error) {
+	return repository.existsOpen(ctx, bson.M{"products.id": productId})
+}
+
+func (repository MongoDbReservationRepository) ExistsOpenWithCustomer(ctx context.Context, customerId uuid.UUID) (bool, error) {
+	return repository.existsOpen(ctx, bson.M{"customerId": customerId})
+}
+
+func (repository MongoDbReservationRepository) ExistsOpenWithStaff(ctx context.Context, staffId uuid.UUID) (bool, error) {
+	return repository.existsOpen(ctx, bson.M{"$or": bson.A{
+		bson.M{"delivery.by": staffId},
+		bson.M{"pickUp.by": staffId},
+	}})
+}
+
+func (repository MongoDbReservationRepository) existsOpen(ctx context.Context, query bson.M) (bool, error) {
+	query["status"] = bson.M{"$nin": []reservation.Status{reservation.Closed, reservation.Cancelled}}
+	query["deletedAt"] = nil
+	count, err := repository.client.Database(Database).
+		Collection(ReservationCollection).
+		CountDocuments(ctx, query, options.Count().SetLimit(1))
+	return count > 0, err
+}
+
+func (repository MongoDbReservationRepository) Save(ctx context.Context, state reservation.State) (reservation.State, error) {
+	collection := repository.client.Database(Database).
 		Collection(ReservationCollection)
 
+	// Only softDelete and restore change the deleted fields
+	state.DeletedAt = nil
+	state.DeletedBy = ""
 	if state.Id == uuid.Nil {
 		state.Id = uuid.New()
-		state.CreatedAt = time.Now().UTC()
-		state.ModifiedAt = time.Now().UTC()
-		_, err = collection.InsertOne(ctx, state)
-		return state, err
+		state.CreatedAt = now()
+		state.ModifiedAt = state.CreatedAt
+		_, err := collection.InsertOne(ctx, searchDocument[reservation.State]{State: state, Search: reservationSearchTokens(state)})
+		if err != nil {
+			return state, err
+		}
+
+		return state, repository.saveRevision(ctx, ReservationCollection, state.Id, state)
 	}
 
 	lastChange := state.ModifiedAt
-	state.ModifiedAt = time.Now().UTC()
+	state.ModifiedAt = now()
+
+	res, err := collection.ReplaceOne(ctx,
+		bson.M{"id": state.Id, "modifiedAt": lastChange, "deletedAt": nil},
+		searchDocument[reservation.State]{State: state, Search: reservationSearchTokens(state)})
+	if err != nil {
+		return state, err
+	}
 
-	res, err := collection.ReplaceOne(ctx, bson.M{"id": state.Id, "modifiedAt": lastChange}, state)
-	if res.ModifiedCount == 0 {
+	if res.MatchedCount == 0 {
 		return state, ErrReservationConcurrencyIssue
 	}
 
-	return state, err
+	return state, repository.saveRevision(ctx, ReservationCollection, state.Id, state)
 }
 
-func (repository MongoDbReservationRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := bson.M{"id": id}
-	client, err := repository.CreateClient(ctx)
+func (repository MongoDbReservationRepository) UpdateCustomer(ctx context.Context, state customer.State) error {
+	query := bson.M{
+		"customerId": state.Id,
+		"status":     bson.M{"$nin": []reservation.Status{reservation.PickedUp, reservation.Closed, reservation.Cancelled}},
+		"deletedAt":  nil,
+	}
+
+	collection := repository.client.Database(Database).
+		Collection(ReservationCollection)
+
+	cursor, err := collection.Find(ctx, query)
 	if err != nil {
 		return err
 	}
 
-	defer client.Disconnect(ctx)
-	_, err = client.Database(Database).
-		Collection(ReservationCollection).
-		DeleteOne(ctx, query)
+	reservations := make([]reservation.State, 0)
+	if err = cursor.All(ctx, &reservations); err != nil {
+		return err
+	}
+
+	if len(reservations) == 0 {
+		return nil
+	}
+
+	// The search tokens include the customer, so each reservation is updated with its own tokens
+	at := now()
+	for i := range reservations {
+		reservations[i].Customer = reservation.Customer{State: state}
+		reservations[i].ModifiedAt = at
+	}
+
+	models := common.Map(reservations, func(item reservation.State) mongo.WriteModel {
+		return mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"id": item.Id, "status": query["status"], "deletedAt": nil}).
+			SetUpdate(bson.M{
+				"$set": bson.M{
+					"customer":   item.Customer,
+					"modifiedAt": at,
+					searchField:  reservationSearchTokens(item),
+				},
+			})
+	})
+
+	_, err = collection.BulkWrite(ctx, models)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range reservations {
+		err = repository.saveRevision(ctx, ReservationCollection, item.Id, item)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (repository MongoDbReservationRepository) GetDeleted(ctx context.Context) ([]reservation.State, error) {
+	reservations := make([]reservation.State, 0)
+	err := repository.findDeleted(ctx, ReservationCollection, &reservations)
+	return reservations, err
+}
+
+func (repository MongoDbReservationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	deleted, err := repository.softDelete(ctx, ReservationCollection, id)
+	if err == nil && !deleted {
+		return ErrReservationNotFound
+	}
+
 	return err
 }
 
+func (repository MongoDbReservationRepository) GetHistory(ctx context.Context, id uuid.UUID) ([]Revision, error) {
+	return history[reservation.State](ctx, repository.MongoDbRepository, ReservationCollection, id)
+}
+
+func (repository MongoDbReservationRepository) GetRevision(ctx context.Context, id, revisionId uuid.UUID) (reservation.State, error) {
+	var state reservation.State
+	err := repository.revision(ctx, ReservationCollection, id, revisionId, &state)
+	return state, err
+}
+
+func (repository MongoDbReservationRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	restored, err := repository.restore(ctx, ReservationCollection, id)
+	if err == nil && !restored {
+		return ErrReservationNotFound
+	}
+
+	return err
+}
+
+func (repository MongoDbReservationRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	return repository.purge(ctx, ReservationCollection, before, nil)
+}
+
+func (repository MongoDbReservationRepository) GetReferences(ctx context.Context) (ReservationReferences, error) {
+	cursor, err := repository.client.Database(Database).
+		Collection(ReservationCollection).
+		Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"customerId": 1, "products.id": 1, "delivery.by": 1, "pickUp.by": 1}))
+	if err != nil {
+		return ReservationReferences{}, err
+	}
+
+	reservations := make([]reservation.State, 0)
+	err = cursor.All(ctx, &reservations)
+	if err != nil {
+		return ReservationReferences{}, err
+	}
+
+	return reservationReferences(reservations), nil
+}
+
+func reservationReferences(reservations []reservation.State) ReservationReferences {
+	var references ReservationReferences
+	for _, state := range reservations {
+		if state.CustomerId != uuid.Nil {
+			references.Customers = append(references.Customers, state.CustomerId)
+		}
+
+		for _, item := range state.Products {
+			references.Products = append(references.Products, item.Id)
+		}
+
+		references.Staff = append(references.Staff, state.Delivery.By...)
+		references.Staff = append(references.Staff, state.PickUp.By...)
+	}
+
+	references.Customers = common.Distinct(references.Customers)
+	references.Products = common.Distinct(references.Products)
+	references.Staff = common.Distinct(references.Staff)
+	return references
+}
+
 func (m *MockReservationRepository) GetAll(ctx context.Context, filter ReservationFilter) (Page[reservation.State], error) {
 	args := m.Called(ctx, filter)
 	return args.Get(0).(Page[reservation.State]), args.Error(1)
@@ -193,12 +497,77 @@ func (m *MockReservationRepository) GetById(ctx context.Context, id uuid.UUID) (
 	return args.Get(0).(reservation.State), args.Error(1)
 }
 
+func (m *MockReservationRepository) GetByPeriod(ctx context.Context, from, to time.Time) ([]reservation.State, error) {
+	args := m.Called(ctx, from, to)
+	return args.Get(0).([]reservation.State), args.Error(1)
+}
+
+func (m *MockReservationRepository) GetByCustomer(ctx context.Context, customerId uuid.UUID) ([]reservation.State, error) {
+	args := m.Called(ctx, customerId)
+	return args.Get(0).([]reservation.State), args.Error(1)
+}
+
+func (m *MockReservationRepository) GetByStaff(ctx context.Context, staffId uuid.UUID, from, to time.Time) ([]reservation.State, error) {
+	args := m.Called(ctx, staffId, from, to)
+	return args.Get(0).([]reservation.State), args.Error(1)
+}
+
+func (m *MockReservationRepository) ExistsOpenWithProduct(ctx context.Context, productId uuid.UUID) (bool, error) {
+	args := m.Called(ctx, productId)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockReservationRepository) ExistsOpenWithCustomer(ctx context.Context, customerId uuid.UUID) (bool, error) {
+	args := m.Called(ctx, customerId)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockReservationRepository) ExistsOpenWithStaff(ctx context.Context, staffId uuid.UUID) (bool, error) {
+	args := m.Called(ctx, staffId)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockReservationRepository) GetDeleted(ctx context.Context) ([]reservation.State, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]reservation.State), args.Error(1)
+}
+
 func (m *MockReservationRepository) Save(ctx context.Context, state reservation.State) (reservation.State, error) {
 	args := m.Called(ctx, state)
 	return args.Get(0).(reservation.State), args.Error(1)
 }
 
+func (m *MockReservationRepository) UpdateCustomer(ctx context.Context, state customer.State) error {
+	args := m.Called(ctx, state)
+	return args.Error(0)
+}
+
 func (m *MockReservationRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
+
+func (m *MockReservationRepository) GetHistory(ctx context.Context, id uuid.UUID) ([]Revision, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]Revision), args.Error(1)
+}
+
+func (m *MockReservationRepository) GetRevision(ctx context.Context, id, revisionId uuid.UUID) (reservation.State, error) {
+	args := m.Called(ctx, id, revisionId)
+	return args.Get(0).(reservation.State), args.Error(1)
+}
+
+func (m *MockReservationRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockReservationRepository) GetReferences(ctx context.Context) (ReservationReferences, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(ReservationReferences), args.Error(1)
+}
+
+func (m *MockReservationRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,208 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"time"
+
+	"happy_day/common"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const RevisionCollection = "revisions"
+
+var (
+	ErrRevisionNotFound = errors.New("revision not found")
+
+	ignoredRevisionFields = map[string]bool{
+		"createdAt":  true,
+		"modifiedAt": true,
+	}
+)
+
+type (
+	Revision struct {
+		Id         uuid.UUID `bson:"id" json:"id"`
+		EntityId   uuid.UUID `bson:"entityId" json:"entityId"`
+		Collection string    `bson:"collection" json:"-"`
+		Actor      string    `bson:"actor" json:"actor"`
+		At         time.Time `bson:"at" json:"at"`
+		State      bson.Raw  `bson:"state" json:"-"`
+		Changes    []Change  `bson:"-" json:"changes"`
+	}
+
+	Change struct {
+		Field  string `json:"field"`
+		Before any    `json:"before"`
+		After  any    `json:"after"`
+	}
+)
+
+func (repository MongoDbRepository) saveRevision(ctx context.Context, collection string, id uuid.UUID, state any) error {
+	raw, err := bson.MarshalWithRegistry(mongoDbRegistry, state)
+	if err != nil {
+		return err
+	}
+
+	_, err = repository.client.Database(Database).
+		Collection(RevisionCollection).
+		InsertOne(ctx, Revision{
+			Id:         uuid.New(),
+			EntityId:   id,
+			Collection: collection,
+			Actor:      common.Actor(ctx),
+			At:         time.Now().UTC(),
+			State:      raw,
+		})
+	return err
+}
+
+func (repository MongoDbRepository) revisions(ctx context.Context, collection string, id uuid.UUID) ([]Revision, error) {
+	query := bson.M{"collection": collection, "entityId": id}
+	opt := options.Find().SetSort(bson.D{{Key: "at", Value: 1}})
+	cursor, err := repository.client.Database(Database).
+		Collection(RevisionCollection).
+		Find(ctx, query, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make([]Revision, 0)
+	err = cursor.All(ctx, &revisions)
+	return revisions, err
+}
+
+func (repository MongoDbRepository) revision(ctx context.Context, collection string, id, revisionId uuid.UUID, state any) error {
+	query := bson.M{"collection": collection, "entityId": id, "id": revisionId}
+	var revision Revision
+	err := repository.client.Database(Database).
+		Collection(RevisionCollection).
+		FindOne(ctx, query).
+		Decode(&revision)
+	if err == mongo.ErrNoDocuments {
+		return ErrRevisionNotFound
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return bson.UnmarshalWithRegistry(mongoDbRegistry, revision.State, state)
+}
+
+func history[T any](ctx context.Context, repository MongoDbRepository, collection string, id uuid.UUID) ([]Revision, error) {
+	revisions, err := repository.revisions(ctx, collection, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return revisionChanges[T](revisions)
+}
+
+func revisionChanges[T any](revisions []Revision) ([]Revision, error) {
+	var previous *T
+	for i := range revisions {
+		var state T
+		err := bson.UnmarshalWithRegistry(mongoDbRegistry, revisions[i].State, &state)
+		if err != nil {
+			return nil, err
+		}
+
+		if previous == nil {
+			revisions[i].Changes, err = Diff(nil, state)
+		} else {
+			revisions[i].Changes, err = Diff(*previous, state)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		previous = &state
+	}
+
+	for i, j := 0, len(revisions)-1; i < j; i, j = i+1, j-1 {
+		revisions[i], revisions[j] = revisions[j], revisions[i]
+	}
+
+	return revisions, nil
+}
+
+func Diff(before, after any) ([]Change, error) {
+	beforeFields, err := flattenState(before)
+	if err != nil {
+		return nil, err
+	}
+
+	afterFields, err := flattenState(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]Change, 0)
+	for field, value := range afterFields {
+		if previous, exists := beforeFields[field]; !exists || !reflect.DeepEqual(previous, value) {
+			changes = append(changes, Change{Field: field, Before: beforeFields[field], After: value})
+		}
+	}
+
+	for field, value := range beforeFields {
+		if _, exists := afterFields[field]; !exists {
+			changes = append(changes, Change{Field: field, Before: value})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+
+	return changes, nil
+}
+
+func flattenState(state any) (map[string]any, error) {
+	fields := map[string]any{}
+	if state == nil {
+		return fields, nil
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+
+	var document map[string]any
+	err = json.Unmarshal(data, &document)
+	if err != nil {
+		return nil, err
+	}
+
+	flatten("", document, fields)
+	return fields, nil
+}
+
+func flatten(prefix string, document map[string]any, fields map[string]any) {
+	for key, value := range document {
+		if ignoredRevisionFields[key] {
+			continue
+		}
+
+		field := key
+		if len(prefix) > 0 {
+			field = prefix + "." + key
+		}
+
+		if inner, ok := value.(map[string]any); ok {
+			flatten(field, inner, fields)
+			continue
+		}
+
+		fields[field] = value
+	}
+}
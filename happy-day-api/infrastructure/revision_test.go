@@ -0,0 +1,45 @@
+package infrastructure
+
+import (
+	"testing"
+	"time"
+
+	"happy_day/domain/customer"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffWhenBeforeIsNil(t *testing.T) {
+	changes, err := Diff(nil, customer.State{Name: "Maria"})
+	assert.Nil(t, err)
+	assert.Contains(t, changes, Change{Field: "name", After: "Maria"})
+	for _, change := range changes {
+		assert.NotEqual(t, "createdAt", change.Field)
+		assert.NotEqual(t, "modifiedAt", change.Field)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	before := customer.State{
+		Name:       "Maria",
+		Comment:    "old",
+		Phones:     []customer.Phone{{Number: "123456789"}},
+		ModifiedAt: time.Now(),
+	}
+
+	after := before
+	after.Comment = ""
+	after.Phones = []customer.Phone{{Number: "987654321"}}
+	after.ModifiedAt = time.Now().Add(time.Minute)
+
+	changes, err := Diff(before, after)
+	assert.Nil(t, err)
+	assert.Equal(t, []Change{
+		{Field: "comment", Before: "old"},
+		{
+			Field:  "phones",
+			Before: []any{map[string]any{"number": "123456789"}},
+			After:  []any{map[string]any{"number": "987654321"}},
+		},
+	}, changes)
+}
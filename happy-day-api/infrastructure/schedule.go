@@ -0,0 +1,80 @@
+package infrastructure
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type ReservationOptions struct {
+	Location        *time.Location
+	OpensAt         time.Duration
+	ClosesAt        time.Duration
+	MinimumLeadTime time.Duration
+	// AssignmentDuration is how long a delivery or a pick up keeps the crew busy
+	AssignmentDuration time.Duration
+}
+
+func ProvideReservationOptions() ReservationOptions {
+	assignmentDuration := viper.GetDuration("reservations.assignment_duration")
+	if assignmentDuration <= 0 {
+		assignmentDuration = time.Hour
+	}
+
+	return ReservationOptions{
+		Location:           BusinessLocation(),
+		OpensAt:            timeOfDay(viper.GetString("reservations.opens_at")),
+		ClosesAt:           timeOfDay(viper.GetString("reservations.closes_at")),
+		MinimumLeadTime:    viper.GetDuration("reservations.minimum_lead_time"),
+		AssignmentDuration: assignmentDuration,
+	}
+}
+
+// BusinessLocation is the time zone used to split days, UTC when it is not configured
+func BusinessLocation() *time.Location {
+	location, err := time.LoadLocation(viper.GetString("reservations.time_zone"))
+	if err != nil {
+		panic(err)
+	}
+
+	return location
+}
+
+func timeOfDay(value string) time.Duration {
+	if len(value) == 0 {
+		return 0
+	}
+
+	at, err := time.Parse("15:04", value)
+	if err != nil {
+		panic(err)
+	}
+
+	return time.Duration(at.Hour())*time.Hour + time.Duration(at.Minute())*time.Minute
+}
+
+func (options ReservationOptions) location() *time.Location {
+	if options.Location == nil {
+		return time.UTC
+	}
+
+	return options.Location
+}
+
+func (options ReservationOptions) StartOfDay(at time.Time) time.Time {
+	year, month, day := at.In(options.location()).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, options.location())
+}
+
+// InBusinessHours compares the wall clock in the business time zone, any time is accepted when hours are not configured
+func (options ReservationOptions) InBusinessHours(at time.Time) bool {
+	if options.OpensAt == 0 && options.ClosesAt == 0 {
+		return true
+	}
+
+	local := at.In(options.location())
+	clock := time.Duration(local.Hour())*time.Hour +
+		time.Duration(local.Minute())*time.Minute +
+		time.Duration(local.Second())*time.Second
+	return clock >= options.OpensAt && clock <= options.ClosesAt
+}
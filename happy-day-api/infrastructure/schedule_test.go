@@ -0,0 +1,43 @@
+package infrastructure
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReservationOptionsStartOfDayWhenUsingBusinessTimeZone(t *testing.T) {
+	options := ReservationOptions{Location: time.FixedZone("BRT", -3*60*60)}
+
+	// 01:00 UTC is still the previous evening in the business time zone
+	start := options.StartOfDay(time.Date(2022, 12, 13, 1, 0, 0, 0, time.UTC))
+
+	assert.True(t, time.Date(2022, 12, 12, 3, 0, 0, 0, time.UTC).Equal(start))
+}
+
+func TestReservationOptionsStartOfDayWhenLocationIsNotConfigured(t *testing.T) {
+	start := ReservationOptions{}.StartOfDay(time.Date(2022, 12, 13, 1, 0, 0, 0, time.UTC))
+
+	assert.True(t, time.Date(2022, 12, 13, 0, 0, 0, 0, time.UTC).Equal(start))
+}
+
+func TestReservationOptionsInBusinessHours(t *testing.T) {
+	options := ReservationOptions{
+		Location: time.FixedZone("BRT", -3*60*60),
+		OpensAt:  8 * time.Hour,
+		ClosesAt: 23 * time.Hour,
+	}
+
+	assert.True(t, options.InBusinessHours(time.Date(2022, 12, 13, 11, 0, 0, 0, time.UTC)))
+	assert.True(t, options.InBusinessHours(time.Date(2022, 12, 14, 2, 0, 0, 0, time.UTC)))
+	assert.False(t, options.InBusinessHours(time.Date(2022, 12, 13, 10, 59, 0, 0, time.UTC)))
+	assert.False(t, options.InBusinessHours(time.Date(2022, 12, 14, 2, 1, 0, 0, time.UTC)))
+	assert.True(t, ReservationOptions{}.InBusinessHours(time.Date(2022, 12, 13, 4, 0, 0, 0, time.UTC)))
+}
+
+func TestTimeOfDay(t *testing.T) {
+	assert.Equal(t, 8*time.Hour+30*time.Minute, timeOfDay("08:30"))
+	assert.Equal(t, time.Duration(0), timeOfDay(""))
+	assert.Panics(t, func() { timeOfDay("8h") })
+}
@@ -0,0 +1,127 @@
+package infrastructure
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"happy_day/common"
+	"happy_day/domain/customer"
+	"happy_day/domain/product"
+	"happy_day/domain/reservation"
+	"happy_day/domain/staff"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const searchField = "search"
+
+// searchDocument stores the normalized tokens next to the state so text search can use an index
+type searchDocument[T any] struct {
+	State  T        `bson:",inline"`
+	Search []string `bson:"search"`
+}
+
+// searchTokens lower cases, removes diacritics and splits on anything that is not a letter or digit
+func searchTokens(values ...string) []string {
+	tokens := make([]string, 0)
+	for _, value := range values {
+		value = strings.ToLower(common.RemoveDiacritics(value))
+		tokens = append(tokens, strings.FieldsFunc(value, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})...)
+	}
+
+	return common.Distinct(tokens)
+}
+
+func phoneTokens(phones []customer.Phone) []string {
+	var values []string
+	for _, phone := range phones {
+		values = append(values, phone.Number, strings.Join(searchTokens(phone.Number), ""))
+	}
+
+	return values
+}
+
+func customerSearchTokens(state customer.State) []string {
+	values := []string{state.Id.String(), state.Name, state.Comment}
+	return searchTokens(append(values, phoneTokens(state.Phones)...)...)
+}
+
+func productSearchTokens(state product.State) []string {
+	return searchTokens(state.Id.String(), state.Name)
+}
+
+func reservationSearchTokens(state reservation.State) []string {
+	values := []string{
+		state.Id.String(),
+		state.Comment,
+		state.Address.Street,
+		state.Address.Number,
+		state.Address.Neighborhood,
+		state.Address.Complement,
+		state.Address.PostalCode,
+		state.Address.City,
+		state.Customer.Name,
+	}
+
+	return searchTokens(append(values, phoneTokens(state.Customer.Phones)...)...)
+}
+
+func staffSearchTokens(state staff.State) []string {
+	return searchTokens(state.Id.String(), state.Name, state.Phone, strings.Join(searchTokens(state.Phone), ""))
+}
+
+// matchSearch requires every term to prefix one of the tokens, the score counts the terms matching a whole token
+func matchSearch(terms, tokens []string) (bool, int) {
+	score := 0
+	for _, term := range terms {
+		matched := false
+		for _, token := range tokens {
+			if !strings.HasPrefix(token, term) {
+				continue
+			}
+
+			matched = true
+			if token == term {
+				score++
+				break
+			}
+		}
+
+		if !matched {
+			return false, 0
+		}
+	}
+
+	return true, score
+}
+
+func searchQuery(terms []string) bson.M {
+	return bson.M{"$all": common.Map(terms, func(term string) primitive.Regex {
+		return primitive.Regex{Pattern: "^" + regexp.QuoteMeta(term)}
+	})}
+}
+
+func rankBySearch[T any](states []T, terms []string, tokens func(state T) []string) {
+	type ranked struct {
+		state T
+		score int
+	}
+
+	items := common.Map(states, func(state T) ranked {
+		_, score := matchSearch(terms, tokens(state))
+		return ranked{state: state, score: score}
+	})
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].score > items[j].score
+	})
+
+	for i, item := range items {
+		states[i] = item.state
+	}
+}
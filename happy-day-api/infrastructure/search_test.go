@@ -0,0 +1,34 @@
+package infrastructure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchTokens(t *testing.T) {
+	tokens := searchTokens("João da Conceição", "Rua São Paulo, 10", "(joão)")
+	assert.Equal(t, []string{"joao", "da", "conceicao", "rua", "sao", "paulo", "10"}, tokens)
+}
+
+func TestSearchTokensWhenTextHasOnlySymbols(t *testing.T) {
+	assert.Empty(t, searchTokens("(*[", "  "))
+}
+
+func TestMatchSearch(t *testing.T) {
+	tokens := searchTokens("Maria Silva", "11987654321")
+
+	matched, score := matchSearch(searchTokens("maria silva"), tokens)
+	assert.True(t, matched)
+	assert.Equal(t, 2, score)
+
+	matched, score = matchSearch(searchTokens("mar sil"), tokens)
+	assert.True(t, matched)
+	assert.Equal(t, 0, score)
+
+	matched, _ = matchSearch(searchTokens("maria souza"), tokens)
+	assert.False(t, matched)
+
+	matched, _ = matchSearch(nil, tokens)
+	assert.True(t, matched)
+}
@@ -0,0 +1,249 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"happy_day/domain/staff"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	StaffCollection = "staff"
+
+	StaffIdAsc    StaffSortBy = "id_asc"
+	StaffIdDesc   StaffSortBy = "id_desc"
+	StaffNameAsc  StaffSortBy = "name_asc"
+	StaffNameDesc StaffSortBy = "name_desc"
+)
+
+var (
+	_ StaffRepository = (*MockStaffRepository)(nil)
+	_ StaffRepository = (*MongoDbStaffRepository)(nil)
+
+	ErrStaffConcurrencyIssue = errors.New("staff concurrency issue")
+	ErrStaffNotFound         = errors.New("staff not found")
+	ErrOneStaffNotFound      = errors.New("one staff in the list not found")
+)
+
+type (
+	StaffSortBy string
+	StaffFilter struct {
+		Text   string
+		Active *bool
+		Role   staff.Role
+		Page   int64
+		Size   int64
+		SortBy StaffSortBy
+		Cursor string
+	}
+
+	StaffRepository interface {
+		GetById(ctx context.Context, id uuid.UUID) (staff.State, error)
+		GetByIds(ctx context.Context, ids []uuid.UUID) ([]staff.State, error)
+		GetAll(ctx context.Context, filter StaffFilter) (Page[staff.State], error)
+
+		GetDeleted(ctx context.Context) ([]staff.State, error)
+
+		Save(ctx context.Context, state staff.State) (staff.State, error)
+		Delete(ctx context.Context, id uuid.UUID) error
+		Restore(ctx context.Context, id uuid.UUID) error
+		Purge(ctx context.Context, before time.Time, keep []uuid.UUID) (int64, error)
+	}
+
+	MongoDbStaffRepository struct {
+		MongoDbRepository
+	}
+
+	MockStaffRepository struct {
+		mock.Mock
+	}
+)
+
+func (m *MockStaffRepository) GetById(ctx context.Context, id uuid.UUID) (staff.State, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(staff.State), args.Error(1)
+}
+
+func (m *MockStaffRepository) GetByIds(ctx context.Context, ids []uuid.UUID) ([]staff.State, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]staff.State), args.Error(1)
+}
+
+func (m *MockStaffRepository) GetAll(ctx context.Context, filter StaffFilter) (Page[staff.State], error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(Page[staff.State]), args.Error(1)
+}
+
+func (m *MockStaffRepository) GetDeleted(ctx context.Context) ([]staff.State, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]staff.State), args.Error(1)
+}
+
+func (m *MockStaffRepository) Save(ctx context.Context, state staff.State) (staff.State, error) {
+	args := m.Called(ctx, state)
+	return args.Get(0).(staff.State), args.Error(1)
+}
+
+func (m *MockStaffRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockStaffRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockStaffRepository) Purge(ctx context.Context, before time.Time, keep []uuid.UUID) (int64, error) {
+	args := m.Called(ctx, before, keep)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (repository MongoDbStaffRepository) GetById(ctx context.Context, id uuid.UUID) (staff.State, error) {
+	query := bson.M{"id": id, "deletedAt": nil}
+	decode := repository.client.Database(Database).
+		Collection(StaffCollection).
+		FindOne(ctx, query)
+
+	err := decode.Err()
+	if err == mongo.ErrNoDocuments {
+		return staff.State{}, ErrStaffNotFound
+	}
+	if err != nil {
+		return staff.State{}, err
+	}
+
+	var state staff.State
+	err = decode.Decode(&state)
+	return state, err
+}
+
+func (repository MongoDbStaffRepository) GetByIds(ctx context.Context, ids []uuid.UUID) ([]staff.State, error) {
+	query := bson.M{"id": bson.M{"$in": ids}, "deletedAt": nil}
+	cursor, err := repository.client.Database(Database).
+		Collection(StaffCollection).
+		Find(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]staff.State, 0)
+	if err = cursor.All(ctx, &members); err != nil {
+		return nil, err
+	}
+
+	if len(ids) != len(members) {
+		return nil, ErrOneStaffNotFound
+	}
+
+	return members, nil
+}
+
+func (repository MongoDbStaffRepository) Save(ctx context.Context, state staff.State) (staff.State, error) {
+	collection := repository.client.Database(Database).
+		Collection(StaffCollection)
+
+	// Only softDelete and restore change the deleted fields
+	state.DeletedAt = nil
+	state.DeletedBy = ""
+	if state.Id == uuid.Nil {
+		state.Id = uuid.New()
+		state.CreatedAt = now()
+		state.ModifiedAt = state.CreatedAt
+		_, err := collection.InsertOne(ctx, searchDocument[staff.State]{State: state, Search: staffSearchTokens(state)})
+		if err != nil {
+			return state, err
+		}
+
+		return state, repository.saveRevision(ctx, StaffCollection, state.Id, state)
+	}
+
+	lastChange := state.ModifiedAt
+	state.ModifiedAt = now()
+
+	res, err := collection.ReplaceOne(ctx,
+		bson.M{"id": state.Id, "modifiedAt": lastChange, "deletedAt": nil},
+		searchDocument[staff.State]{State: state, Search: staffSearchTokens(state)})
+	if err != nil {
+		return state, err
+	}
+
+	if res.MatchedCount == 0 {
+		return state, ErrStaffConcurrencyIssue
+	}
+
+	return state, repository.saveRevision(ctx, StaffCollection, state.Id, state)
+}
+
+func (repository MongoDbStaffRepository) GetDeleted(ctx context.Context) ([]staff.State, error) {
+	members := make([]staff.State, 0)
+	err := repository.findDeleted(ctx, StaffCollection, &members)
+	return members, err
+}
+
+func (repository MongoDbStaffRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	deleted, err := repository.softDelete(ctx, StaffCollection, id)
+	if err == nil && !deleted {
+		return ErrStaffNotFound
+	}
+
+	return err
+}
+
+func (repository MongoDbStaffRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	restored, err := repository.restore(ctx, StaffCollection, id)
+	if err == nil && !restored {
+		return ErrStaffNotFound
+	}
+
+	return err
+}
+
+func (repository MongoDbStaffRepository) Purge(ctx context.Context, before time.Time, keep []uuid.UUID) (int64, error) {
+	return repository.purge(ctx, StaffCollection, before, keep)
+}
+
+func staffSortKey(sortBy StaffSortBy) sortKey[staff.State] {
+	switch sortBy {
+	case StaffIdDesc:
+		return idSortKey(staffFields, true)
+	case StaffNameAsc, StaffNameDesc:
+		return sortKey[staff.State]{
+			field:      "name",
+			descending: sortBy == StaffNameDesc,
+			value:      func(state *staff.State) any { return state.Name },
+			fields:     staffFields,
+		}
+	}
+
+	return idSortKey(staffFields, false)
+}
+
+func (repository MongoDbStaffRepository) GetAll(ctx context.Context, filter StaffFilter) (Page[staff.State], error) {
+	query := bson.M{"deletedAt": nil}
+	terms := searchTokens(filter.Text)
+	if len(terms) > 0 {
+		query[searchField] = searchQuery(terms)
+	}
+
+	if filter.Active != nil {
+		query["active"] = *filter.Active
+	}
+
+	if len(filter.Role) > 0 {
+		query["role"] = filter.Role
+	}
+
+	collection := repository.client.
+		Database(Database).
+		Collection(StaffCollection)
+
+	ranked := len(terms) > 0 && len(filter.SortBy) == 0 && len(filter.Cursor) == 0
+	return findPage(ctx, collection, query, staffSortKey(filter.SortBy), terms, ranked, filter.Page, filter.Size, filter.Cursor)
+}
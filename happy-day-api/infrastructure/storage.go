@@ -0,0 +1,163 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/spf13/viper"
+	"go.etcd.io/bbolt"
+)
+
+const (
+	MongoDbStorage = "mongodb"
+	MemoryStorage  = "memory"
+	BoltStorage    = "bolt"
+)
+
+var (
+	memoryStorage      *memoryRepositories
+	memoryStorageMutex sync.Mutex
+
+	boltDatabase      *bbolt.DB
+	boltDatabaseMutex sync.Mutex
+
+	ErrStorageProviderIsUnknown = errors.New("storage provider is unknown")
+)
+
+type (
+	StorageOptions struct {
+		Provider string
+		Path     string
+	}
+
+	memoryRepositories struct {
+		customers    *EmbeddedCustomerRepository
+		products     *EmbeddedProductRepository
+		reservations *EmbeddedReservationRepository
+		staff        *EmbeddedStaffRepository
+	}
+)
+
+func ProvideStorageOptions() StorageOptions {
+	provider := viper.GetString("storage.provider")
+	if len(provider) == 0 {
+		provider = MongoDbStorage
+	}
+
+	path := viper.GetString("storage.path")
+	if len(path) == 0 {
+		path = "happy-day.db"
+	}
+
+	return StorageOptions{Provider: provider, Path: path}
+}
+
+func (options StorageOptions) Validate() error {
+	switch options.Provider {
+	case MongoDbStorage, MemoryStorage, BoltStorage:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrStorageProviderIsUnknown, options.Provider)
+	}
+}
+
+func ProvideCustomerRepository(options StorageOptions, client MongoDbClientFactory) CustomerRepository {
+	switch options.Provider {
+	case MemoryStorage:
+		return getMemoryStorage().customers
+	case BoltStorage:
+		return NewBoltCustomerRepository(ProvideBoltDatabase(options))
+	case MongoDbStorage:
+		return NewMongoDbCustomerRepository(client())
+	}
+
+	panic(options.Validate())
+}
+
+func ProvideProductRepository(options StorageOptions, client MongoDbClientFactory) ProductRepository {
+	switch options.Provider {
+	case MemoryStorage:
+		return getMemoryStorage().products
+	case BoltStorage:
+		return NewBoltProductRepository(ProvideBoltDatabase(options))
+	case MongoDbStorage:
+		return NewMongoDbProductRepository(client())
+	}
+
+	panic(options.Validate())
+}
+
+func ProvideReservationRepository(options StorageOptions, client MongoDbClientFactory) ReservationRepository {
+	switch options.Provider {
+	case MemoryStorage:
+		return getMemoryStorage().reservations
+	case BoltStorage:
+		return NewBoltReservationRepository(ProvideBoltDatabase(options))
+	case MongoDbStorage:
+		return NewMongoDbReservationRepository(client())
+	}
+
+	panic(options.Validate())
+}
+
+func ProvideStaffRepository(options StorageOptions, client MongoDbClientFactory) StaffRepository {
+	switch options.Provider {
+	case MemoryStorage:
+		return getMemoryStorage().staff
+	case BoltStorage:
+		return NewBoltStaffRepository(ProvideBoltDatabase(options))
+	case MongoDbStorage:
+		return NewMongoDbStaffRepository(client())
+	}
+
+	panic(options.Validate())
+}
+
+func ResetMemoryStorage() {
+	memoryStorageMutex.Lock()
+	defer memoryStorageMutex.Unlock()
+	memoryStorage = nil
+}
+
+func getMemoryStorage() *memoryRepositories {
+	memoryStorageMutex.Lock()
+	defer memoryStorageMutex.Unlock()
+	if memoryStorage == nil {
+		memoryStorage = &memoryRepositories{
+			customers:    NewMemoryCustomerRepository(),
+			products:     NewMemoryProductRepository(),
+			reservations: NewMemoryReservationRepository(),
+			staff:        NewMemoryStaffRepository(),
+		}
+	}
+
+	return memoryStorage
+}
+
+func ProvideBoltDatabase(options StorageOptions) *bbolt.DB {
+	boltDatabaseMutex.Lock()
+	defer boltDatabaseMutex.Unlock()
+	if boltDatabase == nil {
+		db, err := OpenBoltDatabase(options.Path)
+		if err != nil {
+			panic(err)
+		}
+
+		boltDatabase = db
+	}
+
+	return boltDatabase
+}
+
+func CloseBoltDatabase() error {
+	boltDatabaseMutex.Lock()
+	defer boltDatabaseMutex.Unlock()
+	if boltDatabase == nil {
+		return nil
+	}
+
+	err := boltDatabase.Close()
+	boltDatabase = nil
+	return err
+}
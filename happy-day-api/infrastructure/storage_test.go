@@ -0,0 +1,29 @@
+package infrastructure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestStorageOptionsValidate(t *testing.T) {
+	for _, provider := range []string{MongoDbStorage, MemoryStorage, BoltStorage} {
+		assert.Nil(t, StorageOptions{Provider: provider}.Validate(), provider)
+	}
+
+	assert.ErrorIs(t, StorageOptions{Provider: "mongo"}.Validate(), ErrStorageProviderIsUnknown)
+}
+
+func TestProvideCustomerRepositoryWhenProviderIsUnknown(t *testing.T) {
+	connected := false
+	client := func() *mongo.Client {
+		connected = true
+		return nil
+	}
+
+	assert.Panics(t, func() {
+		ProvideCustomerRepository(StorageOptions{Provider: "postgres"}, client)
+	})
+	assert.False(t, connected)
+}
@@ -0,0 +1,24 @@
+package infrastructure
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const defaultTrashRetentionDays = 30
+
+type TrashOptions struct {
+	Retention time.Duration
+}
+
+func ProvideTrashOptions() TrashOptions {
+	days := viper.GetInt("trash.retention_days")
+	if days <= 0 {
+		days = defaultTrashRetentionDays
+	}
+
+	return TrashOptions{
+		Retention: time.Duration(days) * 24 * time.Hour,
+	}
+}
@@ -1,7 +1,18 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	_ "time/tzdata"
+
 	"happy_day/apis"
+	"happy_day/infrastructure"
 	"happy_day/middlewares"
 
 	"github.com/labstack/echo/v4"
@@ -11,6 +22,48 @@ import (
 
 func main() {
 	initConfiguration()
+	if err := run(); err != nil {
+		log.Println(err)
+		os.Exit(1)
+	}
+}
+
+// run returns the error instead of exiting, so the storage is always closed first
+func run() error {
+	if err := infrastructure.ProvideStorageOptions().Validate(); err != nil {
+		return err
+	}
+
+	if isMongoDbStorage() {
+		cleanup, err := apis.OpenMongoDbClient()
+		if err != nil {
+			return err
+		}
+
+		defer cleanup()
+	}
+
+	defer closeBoltDatabase()
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "purge":
+			return purge()
+		case "migrate":
+			return migrate()
+		case "migrate-from-mongo":
+			return importFromMongoDb()
+		default:
+			return fmt.Errorf("unknown command %s", os.Args[1])
+		}
+	}
+
+	if viper.GetBool("migrations.run_on_startup") {
+		if err := migrate(); err != nil {
+			return err
+		}
+	}
+
 	e := echo.New()
 
 	if isDebug() {
@@ -21,14 +74,40 @@ func main() {
 	e.Use(middleware.Recover())
 
 	e.Use(middlewares.ErrorMiddleware)
+	e.Use(middlewares.ActorMiddleware)
 
 	e.Use(middleware.CORSWithConfig(getCorsConfig()))
 
 	apis.MapCustomerEndpoints(e)
 	apis.MapProductEndpoints(e)
 	apis.MapReservationEndpoints(e)
+	apis.MapStaffEndpoints(e)
+	apis.MapTrashEndpoints(e)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		if err := e.Start(":5100"); err != nil && err != http.ErrServerClosed {
+			serverErr <- err
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	select {
+	case err := <-serverErr:
+		return err
+	case <-quit:
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return e.Shutdown(ctx)
+}
 
-	e.Logger.Info(e.Start(":5100"))
+func closeBoltDatabase() {
+	if err := infrastructure.CloseBoltDatabase(); err != nil {
+		log.Println(err)
+	}
 }
 
 func initConfiguration() {
@@ -44,6 +123,42 @@ func initConfiguration() {
 	}
 }
 
+func purge() error {
+	res, err := apis.PurgeTrash(context.Background())
+	if err != nil {
+		return err
+	}
+
+	log.Printf("purged items deleted before %s: %d customers, %d products, %d reservations, %d staff",
+		res.Before.Format(time.RFC3339), res.Customers, res.Products, res.Reservations, res.Staff)
+	return nil
+}
+
+func importFromMongoDb() error {
+	res, err := apis.ImportMongoDbIntoBolt(context.Background())
+	if err != nil {
+		return err
+	}
+
+	log.Printf("imported %d customers, %d products, %d reservations, %d staff and %d revisions into %s",
+		res.Customers, res.Products, res.Reservations, res.Staff, res.Revisions, infrastructure.ProvideStorageOptions().Path)
+	return nil
+}
+
+func migrate() error {
+	if !isMongoDbStorage() {
+		log.Println("migrations are only available for mongodb storage")
+		return nil
+	}
+
+	records, err := apis.Migrate(context.Background())
+	for _, record := range records {
+		log.Printf("applied migration %d: %s", record.Version, record.Name)
+	}
+
+	return err
+}
+
 func getCorsConfig() middleware.CORSConfig {
 	var config middleware.CORSConfig
 	config.AllowOrigins = viper.GetStringSlice("cors.allow_origins")
@@ -55,3 +170,7 @@ func getCorsConfig() middleware.CORSConfig {
 func isDebug() bool {
 	return viper.GetBool("is_debug")
 }
+
+func isMongoDbStorage() bool {
+	return infrastructure.ProvideStorageOptions().Provider == infrastructure.MongoDbStorage
+}
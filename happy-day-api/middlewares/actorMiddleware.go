@@ -0,0 +1,21 @@
+package middlewares
+
+import (
+	"happy_day/common"
+
+	"github.com/labstack/echo/v4"
+)
+
+const ActorHeader = "X-Actor"
+
+func ActorMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		actor := ctx.Request().Header.Get(ActorHeader)
+		if len(actor) > 0 {
+			req := ctx.Request()
+			ctx.SetRequest(req.WithContext(common.WithActor(req.Context(), actor)))
+		}
+
+		return next(ctx)
+	}
+}
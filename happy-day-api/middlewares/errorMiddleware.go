@@ -47,6 +47,18 @@ var (
 			Message: apis.ErrInvalidBody.Error(),
 			Status:  http.StatusBadRequest,
 		},
+		infrastructure.ErrRevisionNotFound: {
+			Type:    "/api/v1/revision-not-found",
+			Title:   "APP001",
+			Message: infrastructure.ErrRevisionNotFound.Error(),
+			Status:  http.StatusNotFound,
+		},
+		infrastructure.ErrCursorIsInvalid: {
+			Type:    "/api/v1/invalid-cursor",
+			Title:   "APP002",
+			Message: infrastructure.ErrCursorIsInvalid.Error(),
+			Status:  http.StatusBadRequest,
+		},
 
 		// Products
 		infrastructure.ErrProductConcurrencyIssue: {
@@ -79,6 +91,30 @@ var (
 			Message: infrastructure.ErrProductPriceIsLessThanZero.Error(),
 			Status:  http.StatusUnprocessableEntity,
 		},
+		infrastructure.ErrProductStockIsLessThanZero: {
+			Type:    "/api/v1/products/stock-is-less-than-zero",
+			Title:   "PROD005",
+			Message: infrastructure.ErrProductStockIsLessThanZero.Error(),
+			Status:  http.StatusUnprocessableEntity,
+		},
+		infrastructure.ErrAvailabilityPeriodIsInvalid: {
+			Type:    "/api/v1/products/availability-period-is-invalid",
+			Title:   "PROD006",
+			Message: infrastructure.ErrAvailabilityPeriodIsInvalid.Error(),
+			Status:  http.StatusBadRequest,
+		},
+		infrastructure.ErrProductInUse: {
+			Type:    "/api/v1/products/in-use",
+			Title:   "PROD007",
+			Message: infrastructure.ErrProductInUse.Error(),
+			Status:  http.StatusConflict,
+		},
+		infrastructure.ErrExistOtherProductWithThisProduct: {
+			Type:    "/api/v1/products/used-by-other-product",
+			Title:   "PROD008",
+			Message: infrastructure.ErrExistOtherProductWithThisProduct.Error(),
+			Status:  http.StatusConflict,
+		},
 
 		// Customers
 		infrastructure.ErrCustomerConcurrencyIssue: {
@@ -111,6 +147,12 @@ var (
 			Message: infrastructure.ErrCustomerPhoneIsInvalid.Error(),
 			Status:  http.StatusUnprocessableEntity,
 		},
+		infrastructure.ErrCustomerInUse: {
+			Type:    "/api/v1/customers/in-use",
+			Title:   "CUS005",
+			Message: infrastructure.ErrCustomerInUse.Error(),
+			Status:  http.StatusConflict,
+		},
 
 		// Reservations
 		infrastructure.ErrProductListIsEmpty: {
@@ -144,5 +186,171 @@ var (
 			Message: infrastructure.ErrReservationAddressPostalCodeIsEmpty.Error(),
 			Status:  http.StatusBadRequest,
 		},
+		infrastructure.ErrReservationProductNotAvailable: {
+			Type:    "/api/v1/reservations/product-not-available",
+			Title:   "RSV005",
+			Message: infrastructure.ErrReservationProductNotAvailable.Error(),
+			Status:  http.StatusConflict,
+		},
+		infrastructure.ErrQuoteStrategyIsInvalid: {
+			Type:    "/api/v1/reservations/quote-strategy-is-invalid",
+			Title:   "RSV006",
+			Message: infrastructure.ErrQuoteStrategyIsInvalid.Error(),
+			Status:  http.StatusBadRequest,
+		},
+		infrastructure.ErrReservationInvalidStatusTransition: {
+			Type:    "/api/v1/reservations/status-transition-is-invalid",
+			Title:   "RSV007",
+			Message: infrastructure.ErrReservationInvalidStatusTransition.Error(),
+			Status:  http.StatusConflict,
+		},
+		infrastructure.ErrReservationPaymentInstallmentAmount: {
+			Type:    "/api/v1/reservations/payment-installment-amount-is-invalid",
+			Title:   "RSV008",
+			Message: infrastructure.ErrReservationPaymentInstallmentAmount.Error(),
+			Status:  http.StatusUnprocessableEntity,
+		},
+		infrastructure.ErrReservationInstallmentsMismatch: {
+			Type:    "/api/v1/reservations/payment-installments-mismatch",
+			Title:   "RSV009",
+			Message: infrastructure.ErrReservationInstallmentsMismatch.Error(),
+			Status:  http.StatusUnprocessableEntity,
+		},
+		infrastructure.ErrReservationInstallmentNotFound: {
+			Type:    "/api/v1/reservations/payment-installment-not-found",
+			Title:   "RSV010",
+			Message: infrastructure.ErrReservationInstallmentNotFound.Error(),
+			Status:  http.StatusNotFound,
+		},
+		infrastructure.ErrReservationInstallmentAlreadyPaid: {
+			Type:    "/api/v1/reservations/payment-installment-already-paid",
+			Title:   "RSV011",
+			Message: infrastructure.ErrReservationInstallmentAlreadyPaid.Error(),
+			Status:  http.StatusConflict,
+		},
+		infrastructure.ErrPixIsNotConfigured: {
+			Type:    "/api/v1/reservations/pix-is-not-configured",
+			Title:   "RSV012",
+			Message: infrastructure.ErrPixIsNotConfigured.Error(),
+			Status:  http.StatusNotImplemented,
+		},
+		infrastructure.ErrDocumentNotFound: {
+			Type:    "/api/v1/reservations/document-not-found",
+			Title:   "RSV013",
+			Message: infrastructure.ErrDocumentNotFound.Error(),
+			Status:  http.StatusNotFound,
+		},
+		infrastructure.ErrReservationFilterIsInvalid: {
+			Type:    "/api/v1/reservations/filter-is-invalid",
+			Title:   "RSV014",
+			Message: infrastructure.ErrReservationFilterIsInvalid.Error(),
+			Status:  http.StatusBadRequest,
+		},
+		infrastructure.ErrReservationPriceMismatch: {
+			Type:    "/api/v1/reservations/price-mismatch",
+			Title:   "RSV015",
+			Message: infrastructure.ErrReservationPriceMismatch.Error(),
+			Status:  http.StatusConflict,
+		},
+		infrastructure.ErrReservationDiscountIsInvalid: {
+			Type:    "/api/v1/reservations/discount-is-invalid",
+			Title:   "RSV016",
+			Message: infrastructure.ErrReservationDiscountIsInvalid.Error(),
+			Status:  http.StatusBadRequest,
+		},
+		infrastructure.ErrReservationScheduleIsInvalid: {
+			Type:    "/api/v1/reservations/schedule-is-invalid",
+			Title:   "RSV017",
+			Message: infrastructure.ErrReservationScheduleIsInvalid.Error(),
+			Status:  http.StatusBadRequest,
+		},
+		infrastructure.ErrReservationLeadTimeIsTooShort: {
+			Type:    "/api/v1/reservations/lead-time-is-too-short",
+			Title:   "RSV018",
+			Message: infrastructure.ErrReservationLeadTimeIsTooShort.Error(),
+			Status:  http.StatusBadRequest,
+		},
+		infrastructure.ErrReservationOutsideBusinessHours: {
+			Type:    "/api/v1/reservations/outside-business-hours",
+			Title:   "RSV019",
+			Message: infrastructure.ErrReservationOutsideBusinessHours.Error(),
+			Status:  http.StatusBadRequest,
+		},
+		infrastructure.ErrReservationInstallmentIsNotPix: {
+			Type:    "/api/v1/reservations/payment-installment-is-not-pix",
+			Title:   "RSV020",
+			Message: infrastructure.ErrReservationInstallmentIsNotPix.Error(),
+			Status:  http.StatusUnprocessableEntity,
+		},
+
+		// Staff
+		infrastructure.ErrStaffConcurrencyIssue: {
+			Type:    "/api/v1/staff/concurrency-issue",
+			Title:   "STF000",
+			Message: infrastructure.ErrStaffConcurrencyIssue.Error(),
+			Status:  http.StatusConflict,
+		},
+		infrastructure.ErrStaffNotFound: {
+			Type:    "/api/v1/staff/not-found",
+			Title:   "STF001",
+			Message: infrastructure.ErrStaffNotFound.Error(),
+			Status:  http.StatusNotFound,
+		},
+		infrastructure.ErrStaffNameIsEmpty: {
+			Type:    "/api/v1/staff/name-is-empty",
+			Title:   "STF002",
+			Message: infrastructure.ErrStaffNameIsEmpty.Error(),
+			Status:  http.StatusBadRequest,
+		},
+		infrastructure.ErrStaffPhoneIsEmpty: {
+			Type:    "/api/v1/staff/phone-is-empty",
+			Title:   "STF003",
+			Message: infrastructure.ErrStaffPhoneIsEmpty.Error(),
+			Status:  http.StatusBadRequest,
+		},
+		infrastructure.ErrStaffRoleIsInvalid: {
+			Type:    "/api/v1/staff/role-is-invalid",
+			Title:   "STF004",
+			Message: infrastructure.ErrStaffRoleIsInvalid.Error(),
+			Status:  http.StatusBadRequest,
+		},
+		infrastructure.ErrStaffInUse: {
+			Type:    "/api/v1/staff/in-use",
+			Title:   "STF005",
+			Message: infrastructure.ErrStaffInUse.Error(),
+			Status:  http.StatusConflict,
+		},
+		infrastructure.ErrOneStaffNotFound: {
+			Type:    "/api/v1/staff/one-staff-not-found",
+			Title:   "STF006",
+			Message: infrastructure.ErrOneStaffNotFound.Error(),
+			Status:  http.StatusUnprocessableEntity,
+		},
+		infrastructure.ErrStaffIsInactive: {
+			Type:    "/api/v1/staff/inactive",
+			Title:   "STF007",
+			Message: infrastructure.ErrStaffIsInactive.Error(),
+			Status:  http.StatusUnprocessableEntity,
+		},
+		infrastructure.ErrStaffScheduleConflict: {
+			Type:    "/api/v1/staff/schedule-conflict",
+			Title:   "STF008",
+			Message: infrastructure.ErrStaffScheduleConflict.Error(),
+			Status:  http.StatusConflict,
+		},
+		infrastructure.ErrStaffSchedulePeriodIsInvalid: {
+			Type:    "/api/v1/staff/schedule-period-is-invalid",
+			Title:   "STF009",
+			Message: infrastructure.ErrStaffSchedulePeriodIsInvalid.Error(),
+			Status:  http.StatusBadRequest,
+		},
+
+		// Trash
+		infrastructure.ErrTrashKindIsInvalid: {
+			Type:    "/api/v1/trash/kind-is-invalid",
+			Title:   "TRS001",
+			Message: infrastructure.ErrTrashKindIsInvalid.Error(),
+			Status:  http.StatusBadRequest,
+		},
 	}
 )
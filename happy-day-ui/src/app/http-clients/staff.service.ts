@@ -0,0 +1,52 @@
+import { Injectable } from '@angular/core';
+import {HttpClient} from "@angular/common/http";
+import {Observable} from "rxjs";
+import {Staff, StaffSchedule, StaffSort} from "../models/staff";
+import {Page} from "../models/page";
+import {environment} from "../../environments/environment";
+
+
+@Injectable({
+  providedIn: 'root'
+})
+export class StaffService {
+
+  constructor(private httpClient: HttpClient) { }
+
+  getAll(page: number, size: number, text: string, sort: StaffSort | null, active: boolean | null = null): Observable<Page<Staff>> {
+    let query = `page=${page}&size=${size}`;
+    if(sort !== null) {
+      query += `&sort=${sort}`
+    }
+
+    if(text !== "") {
+      query += `&text=${text}`
+    }
+
+    if(active !== null) {
+      query += `&active=${active}`
+    }
+
+    return this.httpClient.get<Page<Staff>>(`${environment.api}/api/v1/staff?${query}`)
+  }
+
+  get(id: string): Observable<Staff> {
+    return this.httpClient.get<Staff>(`${environment.api}/api/v1/staff/${id}`);
+  }
+
+  create(staff: Staff): Observable<Staff> {
+    return this.httpClient.post<Staff>(`${environment.api}/api/v1/staff`, staff);
+  }
+
+  update(id: string, staff: Staff): Observable<Staff> {
+    return this.httpClient.put<Staff>(`${environment.api}/api/v1/staff/${id}`, staff);
+  }
+
+  delete(id: string): Observable<any> {
+    return this.httpClient.delete<any>(`${environment.api}/api/v1/staff/${id}`);
+  }
+
+  schedule(id: string, from: string, to: string): Observable<StaffSchedule> {
+    return this.httpClient.get<StaffSchedule>(`${environment.api}/api/v1/staff/${id}/schedule?from=${from}&to=${to}`);
+  }
+}
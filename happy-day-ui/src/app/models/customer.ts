@@ -5,6 +5,8 @@ export interface Customer {
   phones: Phone[];
   createdAt: Date;
   modifiedAt: Date;
+  deletedAt?: Date;
+  deletedBy?: string;
 }
 
 export interface Phone {
@@ -2,4 +2,6 @@ export interface Page<T> {
   items: T[];
   totalElements: number;
   totalPages: number;
+  nextCursor?: string;
+  prevCursor?: string;
 }
@@ -5,6 +5,8 @@ export interface Product {
   products: InnerProduct[];
   createdAt: Date;
   modifiedAt: Date;
+  deletedAt?: Date;
+  deletedBy?: string;
 }
 
 export interface InnerProduct {
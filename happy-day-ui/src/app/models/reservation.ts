@@ -1,19 +1,27 @@
 import {Customer} from "./customer";
+import {Page} from "./page";
 
 export interface Reservation {
   id: string;
   price: number;
   discount: number;
   finalPrice: number;
+  amountPaid: number;
+  balanceDue: number;
   comment: string;
   products: Product[];
   delivery: DeliveryOrPickUp;
   pickUp: DeliveryOrPickUp;
   paymentInstallments: PaymentInstallment[];
+  customerId: string;
   customer: Customer;
   address: Address;
+  status: ReservationStatus;
+  statusHistory: StatusChange[];
   createdAt: Date;
   modifiedAt: Date;
+  deletedAt?: Date;
+  deletedBy?: string;
 }
 
 export interface Product {
@@ -31,6 +39,13 @@ export interface PaymentInstallment {
   at: Date;
   amount: number;
   method: PaymentMethod;
+  status: InstallmentStatus;
+  paidAt?: Date;
+}
+
+export enum InstallmentStatus {
+  Scheduled = "scheduled",
+  Paid = "paid",
 }
 
 export enum PaymentMethod {
@@ -39,6 +54,20 @@ export enum PaymentMethod {
   Cash = "cash",
 }
 
+export enum ReservationStatus {
+  Draft = "draft",
+  Confirmed = "confirmed",
+  Delivered = "delivered",
+  PickedUp = "pickedUp",
+  Closed = "closed",
+  Cancelled = "cancelled",
+}
+
+export interface StatusChange {
+  status: ReservationStatus;
+  at: Date;
+}
+
 export interface Address {
   street: string;
   number: string;
@@ -59,4 +88,29 @@ export interface Quote {
 
 export interface QuoteResponse {
   price: number;
+  loosePrice: number;
+  savings: number;
+  approximate: boolean;
+  kits: QuoteItem[];
+  products: QuoteItem[];
+}
+
+export interface QuoteItem {
+  id: string;
+  name: string;
+  quantity: number;
+  unitPrice: number;
+  price: number;
+}
+
+export interface CustomerReservations {
+  summary: CustomerReservationSummary;
+  reservations: Page<Reservation>;
+}
+
+export interface CustomerReservationSummary {
+  parties: number;
+  totalSpent: number;
+  outstandingBalance: number;
+  lastEventAt?: Date;
 }
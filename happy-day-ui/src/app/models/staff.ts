@@ -0,0 +1,40 @@
+export interface Staff {
+  id: string;
+  name: string;
+  phone: string;
+  role: StaffRole;
+  active: boolean;
+  createdAt: Date;
+  modifiedAt: Date;
+  deletedAt?: Date;
+  deletedBy?: string;
+}
+
+export enum StaffRole {
+  Driver = "driver",
+  Helper = "helper",
+}
+
+export enum StaffSort {
+  IdAsc = "id_asc",
+  IdDesc = "id_desc",
+  NameAsc = "name_asc",
+  NameDesc = "name_desc",
+}
+
+export interface StaffSchedule {
+  staff: Staff;
+  from: Date;
+  to: Date;
+  assignments: StaffAssignment[];
+}
+
+export interface StaffAssignment {
+  reservationId: string;
+  kind: "delivery" | "pickUp";
+  at: Date;
+  until: Date;
+  status: string;
+  customer: string;
+  crew: string[];
+}
@@ -9,6 +9,8 @@ import {ProductService} from "../http-clients/product.service";
 import {CustomerService} from "../http-clients/customer.service";
 import {DeliveryOrPickUp, PaymentInstallment, Product as RProduct, Quote, Reservation} from "../models/reservation";
 import {Customer, CustomerSort, Phone} from "../models/customer";
+import {StaffService} from "../http-clients/staff.service";
+import {Staff, StaffSort} from "../models/staff";
 
 @Component({
   selector: 'app-reservation',
@@ -29,12 +31,15 @@ export class ReservationComponent implements OnInit {
   filteredCustomers = new BehaviorSubject<Customer[]>([]);
   filteredCustomers$: Observable<Customer[]>;
 
+  staff: Staff[] = [];
+
   constructor(private dialogRef: MatDialogRef<ReservationComponent>,
               @Inject(MAT_DIALOG_DATA) private data: ReservationData,
               private builder: FormBuilder,
               private reservationService: ReservationService,
               private productService: ProductService,
-              private customerService: CustomerService) {
+              private customerService: CustomerService,
+              private staffService: StaffService) {
     this.filteredProducts$ = this.filteredProducts.asObservable();
     this.filteredCustomers$ = this.filteredCustomers.asObservable();
     this.formGroup = this.builder.group({
@@ -69,6 +74,10 @@ export class ReservationComponent implements OnInit {
   }
 
   ngOnInit(): void {
+    this.staffService.getAll(1, 1000, "", StaffSort.NameAsc, true)
+      .pipe(tap(page => this.staff = page.items))
+      .subscribe();
+
     this.formGroup.get("customer")!.get("name")!.valueChanges
       .pipe(
         debounceTime(1000),
@@ -215,12 +224,12 @@ export class ReservationComponent implements OnInit {
     return this.formGroup.get("pickUpBy") as FormArray;
   }
 
-  addDeliveryBy(employee: string | null = null): void {
-    this.addBy(this.deliveryBy, employee);
+  addDeliveryBy(staffId: string | null = null): void {
+    this.addBy(this.deliveryBy, staffId);
   }
 
-  addPickUpBy(employee: string | null = null): void {
-    this.addBy(this.pickUpBy, employee);
+  addPickUpBy(staffId: string | null = null): void {
+    this.addBy(this.pickUpBy, staffId);
   }
 
   deleteDeliveryBy(index: number): void {
@@ -231,9 +240,9 @@ export class ReservationComponent implements OnInit {
     this.pickUpBy.removeAt(index);
   }
 
-  private addBy(array: FormArray, name: string | null) {
+  private addBy(array: FormArray, staffId: string | null) {
     array.push(this.builder.group({
-      name: [{value: name, disabled: this.isDeleteMode()}, [Validators.required]]
+      staffId: [{value: staffId, disabled: this.isDeleteMode()}, [Validators.required]]
     }));
   }
 
@@ -292,21 +301,17 @@ export class ReservationComponent implements OnInit {
     reservation.paymentInstallments = this.paymentInstallments.getRawValue();
     reservation.delivery = <DeliveryOrPickUp>{
       at: this.formGroup.get("deliveryAt")!.getRawValue(),
-      by: this.deliveryBy.getRawValue().map(x => x.name),
+      by: this.deliveryBy.getRawValue().map(x => x.staffId),
     };
 
     reservation.pickUp = <DeliveryOrPickUp>{
       at: this.formGroup.get("pickUpAt")!.getRawValue(),
-      by: this.pickUpBy.getRawValue().map(x => x.name),
+      by: this.pickUpBy.getRawValue().map(x => x.staffId),
     };
 
     let obs: Observable<Reservation>;
     if (this.isCreateMode()) {
-      obs = this.reservationService.create(reservation)
-        .pipe(
-          tap(r => reservation.id = r.id),
-          switchMap(() => this.reservationService.update(reservation.id!, reservation))
-        );
+      obs = this.reservationService.create(reservation);
     } else {
       obs =this.reservationService.update(this.data.id, reservation);
     }